@@ -0,0 +1,270 @@
+// Package admin implements an HTTP handler exposing the state of a webwire
+// server as JSON for administration and introspection purposes.
+// The handler allows closing arbitrary connections and sessions and must
+// therefore only be served on internal network interfaces. Sessions are
+// identified by the hashes of their keys since the keys are credentials
+// that allow restoring the sessions
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// Handler implements the http.Handler interface serving the following
+// endpoints relative to the path the handler is mounted on:
+//
+//	GET  /                                 server overview
+//	GET  /options                          server options
+//	GET  /connections                      active connections
+//	POST /connections/{id}/close           closes a connection
+//	POST /connections/{id}/close-session   closes the session of a connection
+//	GET  /sessions                         active sessions
+//	POST /sessions/{hash}/close            closes a session
+//
+// Actions (POST requests) require the application/json content type which
+// can't be sent by plain cross-site HTML forms
+type Handler struct {
+	// ExposeSessionKeys enables exposing the raw session keys in the views
+	// and addressing sessions by their raw keys in addition to their hashes.
+	// Anyone who can read the exposed keys can restore the sessions
+	ExposeSessionKeys bool
+
+	server wwr.HeadlessServer
+}
+
+// New creates a new admin handler for the given server
+func New(server wwr.HeadlessServer) *Handler {
+	return &Handler{server: server}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (hd *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	path := strings.Trim(req.URL.EscapedPath(), "/")
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, "/")
+	}
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			writeError(resp, http.StatusBadRequest, err)
+			return
+		}
+		segments[i] = unescaped
+	}
+
+	switch {
+	case len(segments) == 0:
+		hd.get(resp, req, hd.overview)
+	case len(segments) == 1 && segments[0] == "options":
+		hd.get(resp, req, func() interface{} {
+			return newOptions(hd.server.Options())
+		})
+	case len(segments) == 1 && segments[0] == "connections":
+		hd.get(resp, req, hd.connections)
+	case len(segments) == 1 && segments[0] == "sessions":
+		hd.get(resp, req, hd.sessions)
+	case len(segments) == 3 && segments[0] == "connections":
+		hd.connectionAction(resp, req, segments[1], segments[2])
+	case len(segments) == 3 &&
+		segments[0] == "sessions" &&
+		segments[2] == "close":
+		hd.post(resp, req, func() (int, interface{}) {
+			return hd.closeSession(segments[1])
+		})
+	default:
+		writeError(resp, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// get writes the view returned by the given function
+// if the request is a GET request
+func (hd *Handler) get(
+	resp http.ResponseWriter,
+	req *http.Request,
+	view func() interface{},
+) {
+	if req.Method != http.MethodGet {
+		resp.Header().Set("Allow", http.MethodGet)
+		writeError(
+			resp,
+			http.StatusMethodNotAllowed,
+			errors.New("method not allowed"),
+		)
+		return
+	}
+	writeJSON(resp, http.StatusOK, view())
+}
+
+// post executes the given action if the request is a POST request
+func (hd *Handler) post(
+	resp http.ResponseWriter,
+	req *http.Request,
+	action func() (status int, view interface{}),
+) {
+	if req.Method != http.MethodPost {
+		resp.Header().Set("Allow", http.MethodPost)
+		writeError(
+			resp,
+			http.StatusMethodNotAllowed,
+			errors.New("method not allowed"),
+		)
+		return
+	}
+	// Reject simple requests which could be forged by cross-site forms
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		writeError(
+			resp,
+			http.StatusUnsupportedMediaType,
+			errors.New("actions require the application/json content type"),
+		)
+		return
+	}
+	status, view := action()
+	writeJSON(resp, status, view)
+}
+
+func (hd *Handler) overview() interface{} {
+	view := Overview{
+		CurrentOperations: hd.server.CurrentOperations(),
+		Connections:       len(hd.server.Connections()),
+		ActiveSessions:    hd.server.ActiveSessionsNum(),
+		MessagePool:       newMessagePool(hd.server.MessagePoolStats()),
+	}
+	if headed, isHeaded := hd.server.(wwr.Server); isHeaded {
+		addr := headed.Address()
+		view.Address = addr.String()
+	}
+	return view
+}
+
+func (hd *Handler) connections() interface{} {
+	connections := hd.server.Connections()
+	views := make([]Connection, len(connections))
+	for i, conn := range connections {
+		views[i] = newConnection(conn, hd.ExposeSessionKeys)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].ID < views[j].ID
+	})
+	return views
+}
+
+func (hd *Handler) sessions() interface{} {
+	summaries := hd.server.ActiveSessions()
+	views := make([]Session, 0, len(summaries))
+	for _, summary := range summaries {
+		connections := hd.server.SessionConnections(summary.Key)
+		ids := connectionIDs(connections)
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		view := Session{
+			KeyHash:     wwr.HashSessionKey(summary.Key),
+			Creation:    summary.Creation,
+			Connections: ids,
+		}
+		if hd.ExposeSessionKeys {
+			view.Key = summary.Key
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].KeyHash < views[j].KeyHash
+	})
+	return views
+}
+
+func (hd *Handler) connectionAction(
+	resp http.ResponseWriter,
+	req *http.Request,
+	rawID string,
+	action string,
+) {
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		writeError(
+			resp,
+			http.StatusBadRequest,
+			fmt.Errorf("invalid connection ID: %s", rawID),
+		)
+		return
+	}
+
+	var perform func(connectionID uint64) error
+	switch action {
+	case "close":
+		perform = hd.server.CloseConnection
+	case "close-session":
+		perform = hd.server.CloseConnectionSession
+	default:
+		writeError(resp, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	hd.post(resp, req, func() (int, interface{}) {
+		if err := perform(id); err != nil {
+			if _, notFound := err.(wwr.ErrConnectionNotFound); notFound {
+				return http.StatusNotFound, Error{Error: err.Error()}
+			}
+			return http.StatusInternalServerError, Error{Error: err.Error()}
+		}
+		return http.StatusOK, ActionResult{
+			AffectedConnections: []uint64{id},
+		}
+	})
+}
+
+// sessionKey returns the key of the active session identified by the given
+// reference which is either the hash of the key or, if exposing session keys
+// is enabled, the key itself
+func (hd *Handler) sessionKey(ref string) (key string, found bool) {
+	for _, summary := range hd.server.ActiveSessions() {
+		if wwr.HashSessionKey(summary.Key) == ref ||
+			(hd.ExposeSessionKeys && summary.Key == ref) {
+			return summary.Key, true
+		}
+	}
+	return "", false
+}
+
+func (hd *Handler) closeSession(ref string) (int, interface{}) {
+	notFound := Error{Error: fmt.Sprintf("session %s not found", ref)}
+	key, found := hd.sessionKey(ref)
+	if !found {
+		return http.StatusNotFound, notFound
+	}
+
+	affected, closeErrs, _ := hd.server.CloseSession(key)
+	if affected == nil {
+		return http.StatusNotFound, notFound
+	}
+
+	result := ActionResult{AffectedConnections: connectionIDs(affected)}
+	for _, err := range closeErrs {
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	return http.StatusOK, result
+}
+
+func writeJSON(resp http.ResponseWriter, status int, view interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	// The status is already written, encoding errors can't be reported
+	_ = json.NewEncoder(resp).Encode(view)
+}
+
+func writeError(resp http.ResponseWriter, status int, err error) {
+	writeJSON(resp, status, Error{Error: err.Error()})
+}
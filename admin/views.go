@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Overview represents the general state of the server
+type Overview struct {
+	Address           string      `json:"address,omitempty"`
+	CurrentOperations uint32      `json:"currentOperations"`
+	Connections       int         `json:"connections"`
+	ActiveSessions    int         `json:"activeSessions"`
+	MessagePool       MessagePool `json:"messagePool"`
+}
+
+// MessagePool represents the usage of the message buffer pool
+type MessagePool struct {
+	BufferSize uint32 `json:"bufferSize"`
+	Allocated  uint64 `json:"allocated"`
+	Acquired   uint64 `json:"acquired"`
+	Released   uint64 `json:"released"`
+	InUse      uint64 `json:"inUse"`
+}
+
+// Options represents the serializable subset of the server options
+type Options struct {
+	Sessions              bool   `json:"sessions"`
+	MaxSessionConnections uint   `json:"maxSessionConnections"`
+	SessionEvictionPolicy string `json:"sessionEvictionPolicy"`
+	SessionCodec          byte   `json:"sessionCodec"`
+	HashSessionEventKeys  bool   `json:"hashSessionEventKeys"`
+	ReadTimeout           string `json:"readTimeout"`
+	SubProtocolName       string `json:"subProtocolName,omitempty"`
+	MessageBufferSize     uint32 `json:"messageBufferSize"`
+	MaxConnections        uint   `json:"maxConnections"`
+	MaxConnectionsPerIP   uint   `json:"maxConnectionsPerIP"`
+}
+
+// Connection represents an active connection. SessionKeyHash is the hex
+// encoded SHA-256 hash of the session key, the raw key (Session) is only
+// exposed if Handler.ExposeSessionKeys is enabled
+type Connection struct {
+	ID             uint64          `json:"id"`
+	RemoteAddr     string          `json:"remoteAddr,omitempty"`
+	Creation       time.Time       `json:"creation"`
+	SessionKeyHash string          `json:"sessionKeyHash,omitempty"`
+	Session        string          `json:"session,omitempty"`
+	Stats          ConnectionStats `json:"stats"`
+}
+
+// ConnectionStats represents the statistics of a connection
+type ConnectionStats struct {
+	BytesReceived    uint64    `json:"bytesReceived"`
+	BytesSent        uint64    `json:"bytesSent"`
+	MessagesReceived uint64    `json:"messagesReceived"`
+	MessagesSent     uint64    `json:"messagesSent"`
+	RequestsServed   uint64    `json:"requestsServed"`
+	RequestsFailed   uint64    `json:"requestsFailed"`
+	SignalsReceived  uint64    `json:"signalsReceived"`
+	SignalsSent      uint64    `json:"signalsSent"`
+	LastActivity     time.Time `json:"lastActivity"`
+	InFlightHandlers int32     `json:"inFlightHandlers"`
+	RequestLatency   string    `json:"requestLatency"`
+}
+
+// Session represents an active session. KeyHash is the hex encoded SHA-256
+// hash of the session key, the raw key is only exposed
+// if Handler.ExposeSessionKeys is enabled
+type Session struct {
+	KeyHash     string    `json:"keyHash"`
+	Key         string    `json:"key,omitempty"`
+	Creation    time.Time `json:"creation"`
+	Connections []uint64  `json:"connections"`
+}
+
+// ActionResult represents the result of an administrative action
+type ActionResult struct {
+	// AffectedConnections lists the IDs of the affected connections
+	AffectedConnections []uint64 `json:"affectedConnections"`
+
+	// Errors lists the errors that occurred during the action
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Error represents an error response
+type Error struct {
+	Error string `json:"error"`
+}
+
+func newMessagePool(stats message.PoolStats) MessagePool {
+	return MessagePool{
+		BufferSize: stats.BufferSize,
+		Allocated:  stats.Allocated,
+		Acquired:   stats.Acquired,
+		Released:   stats.Released,
+		InUse:      stats.InUse(),
+	}
+}
+
+func newOptions(opts wwr.ServerOptions) Options {
+	view := Options{
+		Sessions:              opts.Sessions == wwr.Enabled,
+		MaxSessionConnections: opts.MaxSessionConnections,
+		SessionEvictionPolicy: opts.SessionEvictionPolicy.String(),
+		HashSessionEventKeys:  opts.HashSessionEventKeys,
+		ReadTimeout:           opts.ReadTimeout.String(),
+		SubProtocolName:       string(opts.SubProtocolName),
+		MessageBufferSize:     opts.MessageBufferSize,
+		MaxConnections:        opts.MaxConnections,
+		MaxConnectionsPerIP:   opts.MaxConnectionsPerIP,
+	}
+	if opts.SessionCodec != nil {
+		view.SessionCodec = opts.SessionCodec.Identifier()
+	}
+	return view
+}
+
+func newConnection(conn wwr.Connection, exposeKeys bool) Connection {
+	view := Connection{
+		ID:       conn.ID(),
+		Creation: conn.Creation(),
+	}
+	if key := conn.SessionKey(); key != "" {
+		view.SessionKeyHash = wwr.HashSessionKey(key)
+		if exposeKeys {
+			view.Session = key
+		}
+	}
+	if addr := conn.RemoteAddr(); addr != nil {
+		view.RemoteAddr = addr.String()
+	}
+
+	stats := conn.Stats()
+	view.Stats = ConnectionStats{
+		BytesReceived:    stats.BytesReceived,
+		BytesSent:        stats.BytesSent,
+		MessagesReceived: stats.MessagesReceived,
+		MessagesSent:     stats.MessagesSent,
+		RequestsServed:   stats.RequestsServed,
+		RequestsFailed:   stats.RequestsFailed,
+		SignalsReceived:  stats.SignalsReceived,
+		SignalsSent:      stats.SignalsSent,
+		LastActivity:     stats.LastActivity,
+		InFlightHandlers: stats.InFlightHandlers,
+		RequestLatency:   stats.RequestLatency.String(),
+	}
+	return view
+}
+
+func connectionIDs(connections []wwr.Connection) []uint64 {
+	ids := make([]uint64, len(connections))
+	for i, conn := range connections {
+		ids[i] = conn.ID()
+	}
+	return ids
+}
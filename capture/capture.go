@@ -0,0 +1,60 @@
+// Package capture implements a traffic capture format for webwire connections
+// as well as socket and transport wrappers teeing all traffic into it.
+//
+// A capture starts with the 8 byte file header "WWRCAP\x00" followed by the
+// format version. It's followed by length-prefixed records each consisting of
+// a little-endian uint32 length of the rest of the record, a little-endian
+// int64 timestamp in nanoseconds since the Unix epoch, the direction byte,
+// the little-endian uint64 connection ID and the raw message data
+package capture
+
+import "time"
+
+// Version is the version of the capture format
+const Version = byte(1)
+
+// fileHeader is the header every capture starts with
+var fileHeader = [8]byte{'W', 'W', 'R', 'C', 'A', 'P', 0, Version}
+
+// recordHeaderLen is the length of the record header following
+// the record length prefix
+const recordHeaderLen = 8 + 1 + 8
+
+// Direction represents the direction of a captured message
+type Direction byte
+
+const (
+	// ClientToServer represents messages sent by the client
+	ClientToServer Direction = 1
+
+	// ServerToClient represents messages sent by the server
+	ServerToClient Direction = 2
+)
+
+// String stringifies the direction
+func (dir Direction) String() string {
+	switch dir {
+	case ClientToServer:
+		return "client->server"
+	case ServerToClient:
+		return "server->client"
+	}
+	return "unknown"
+}
+
+// Record represents a single captured message
+type Record struct {
+	// Time is the time the message was captured at
+	Time time.Time
+
+	// Direction is the direction the message was sent in
+	Direction Direction
+
+	// ConnectionID identifies the connection the message was captured on.
+	// IDs are assigned by the capturing socket wrappers and are unique
+	// within a single capture only
+	ConnectionID uint64
+
+	// Data is the raw message data as transmitted over the transport
+	Data []byte
+}
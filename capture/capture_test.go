@@ -0,0 +1,112 @@
+package capture_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter is an io.Writer failing all writes
+type failingWriter struct {
+	writes int
+}
+
+// Write implements the io.Writer interface
+func (wr *failingWriter) Write(p []byte) (int, error) {
+	wr.writes++
+	if wr.writes > 1 {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+// testRecords returns a sample set of records
+func testRecords() []capture.Record {
+	return []capture.Record{
+		{
+			Time:         time.Unix(1500000000, 123),
+			Direction:    capture.ServerToClient,
+			ConnectionID: 1,
+			Data:         []byte{23, 2, 0},
+		},
+		{
+			Time:         time.Unix(1500000001, 0),
+			Direction:    capture.ClientToServer,
+			ConnectionID: 2,
+			Data:         []byte{33},
+		},
+	}
+}
+
+// TestWriteRead tests writing and reading a capture
+func TestWriteRead(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+	for _, rec := range testRecords() {
+		require.NoError(t, writer.WriteRecord(rec))
+	}
+	require.NoError(t, writer.Err())
+
+	reader, err := capture.NewReader(buf)
+	require.NoError(t, err)
+	for _, expected := range testRecords() {
+		rec, err := reader.Next()
+		require.NoError(t, err)
+		require.True(t, expected.Time.Equal(rec.Time))
+		require.Equal(t, expected.Direction, rec.Direction)
+		require.Equal(t, expected.ConnectionID, rec.ConnectionID)
+		require.Equal(t, expected.Data, rec.Data)
+	}
+	_, err = reader.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+// TestReadTruncated tests reading a truncated capture
+func TestReadTruncated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRecord(testRecords()[0]))
+
+	data := buf.Bytes()
+	reader, err := capture.NewReader(bytes.NewReader(data[:len(data)-1]))
+	require.NoError(t, err)
+	_, err = reader.Next()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+// TestReadInvalid tests reading invalid captures
+func TestReadInvalid(t *testing.T) {
+	_, err := capture.NewReader(bytes.NewReader([]byte("WWRCAP")))
+	require.Error(t, err)
+
+	_, err = capture.NewReader(bytes.NewReader([]byte("PCAPPCAP")))
+	require.Error(t, err)
+
+	_, err = capture.NewReader(bytes.NewReader([]byte("WWRCAP\x00\x09")))
+	require.Error(t, err)
+
+	// Record length shorter than the record header
+	reader, err := capture.NewReader(bytes.NewReader(append(
+		[]byte("WWRCAP\x00\x01"),
+		append([]byte{1, 0, 0, 0}, make([]byte, 17)...)...,
+	)))
+	require.NoError(t, err)
+	_, err = reader.Next()
+	require.Error(t, err)
+}
+
+// TestWriteFailure tests failing writes being reported persistently
+func TestWriteFailure(t *testing.T) {
+	writer, err := capture.NewWriter(&failingWriter{})
+	require.NoError(t, err)
+	require.Error(t, writer.WriteRecord(testRecords()[0]))
+	require.Error(t, writer.WriteRecord(testRecords()[1]))
+	require.EqualError(t, writer.Err(), "disk full")
+}
@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MaxRecordLen limits the length of records read to protect
+// against corrupted length prefixes
+const MaxRecordLen = 64 * 1024 * 1024
+
+// Reader reads capture records
+type Reader struct {
+	src io.Reader
+}
+
+// NewReader creates a new capture reader verifying the file header
+// read from the given source
+func NewReader(src io.Reader) (*Reader, error) {
+	var header [len(fileHeader)]byte
+	if _, err := io.ReadFull(src, header[:]); err != nil {
+		return nil, fmt.Errorf("couldn't read capture file header: %s", err)
+	}
+	if string(header[:len(header)-1]) != string(fileHeader[:len(header)-1]) {
+		return nil, errors.New("not a webwire capture")
+	}
+	if header[len(header)-1] != Version {
+		return nil, fmt.Errorf(
+			"unsupported capture format version %d",
+			header[len(header)-1],
+		)
+	}
+	return &Reader{src: src}, nil
+}
+
+// Next reads the next record. It returns io.EOF at the end of the capture
+// and io.ErrUnexpectedEOF if the capture is truncated
+func (rd *Reader) Next() (Record, error) {
+	var prefix [4 + recordHeaderLen]byte
+	if _, err := io.ReadFull(rd.src, prefix[:]); err != nil {
+		return Record{}, err
+	}
+
+	recLen := binary.LittleEndian.Uint32(prefix[0:4])
+	if recLen < recordHeaderLen || recLen > MaxRecordLen {
+		return Record{}, fmt.Errorf("invalid record length %d", recLen)
+	}
+
+	rec := Record{
+		Time: time.Unix(
+			0,
+			int64(binary.LittleEndian.Uint64(prefix[4:12])),
+		),
+		Direction:    Direction(prefix[12]),
+		ConnectionID: binary.LittleEndian.Uint64(prefix[13:21]),
+		Data:         make([]byte, recLen-recordHeaderLen),
+	}
+	if _, err := io.ReadFull(rd.src, rec.Data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+	return rec, nil
+}
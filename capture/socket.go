@@ -0,0 +1,127 @@
+package capture
+
+import (
+	"io"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Socket wraps a webwire socket teeing all sent and received messages
+// into a capture. Failing to capture doesn't affect the wrapped socket,
+// capture errors are reported by Writer.Err
+type Socket struct {
+	wwr.Socket
+	capture      *Writer
+	connectionID uint64
+	readDir      Direction
+	writeDir     Direction
+}
+
+// NewSocket wraps the given server-side socket capturing its traffic
+// under the given connection ID
+func NewSocket(
+	sock wwr.Socket,
+	connectionID uint64,
+	capture *Writer,
+) *Socket {
+	return &Socket{
+		Socket:       sock,
+		capture:      capture,
+		connectionID: connectionID,
+		readDir:      ClientToServer,
+		writeDir:     ServerToClient,
+	}
+}
+
+// ClientSocket wraps a webwire client socket teeing all sent and received
+// messages into a capture
+type ClientSocket struct {
+	*Socket
+	dialer wwr.ClientSocket
+}
+
+// NewClientSocket wraps the given client socket capturing its traffic
+// under the given connection ID
+func NewClientSocket(
+	sock wwr.ClientSocket,
+	connectionID uint64,
+	capture *Writer,
+) *ClientSocket {
+	return &ClientSocket{
+		Socket: &Socket{
+			Socket:       sock,
+			capture:      capture,
+			connectionID: connectionID,
+			readDir:      ServerToClient,
+			writeDir:     ClientToServer,
+		},
+		dialer: sock,
+	}
+}
+
+// Dial implements the webwire.ClientSocket interface
+func (sock *ClientSocket) Dial(deadline time.Time) error {
+	return sock.dialer.Dial(deadline)
+}
+
+// record captures the given message data
+func (sock *Socket) record(dir Direction, data []byte) {
+	sock.capture.WriteRecord(Record{
+		Time:         time.Now(),
+		Direction:    dir,
+		ConnectionID: sock.connectionID,
+		Data:         data,
+	})
+}
+
+// Read implements the webwire.Socket interface
+func (sock *Socket) Read(
+	into *message.Message,
+	deadline time.Time,
+) wwr.ErrSockRead {
+	onRaw := into.OnRaw
+	into.OnRaw = func(raw []byte) {
+		sock.record(sock.readDir, raw)
+		if onRaw != nil {
+			onRaw(raw)
+		}
+	}
+	err := sock.Socket.Read(into, deadline)
+	into.OnRaw = onRaw
+	return err
+}
+
+// GetWriter implements the webwire.Socket interface
+func (sock *Socket) GetWriter() (io.WriteCloser, error) {
+	sockWriter, err := sock.Socket.GetWriter()
+	if err != nil {
+		return nil, err
+	}
+	return &writer{sock: sock, writer: sockWriter}, nil
+}
+
+// writer tees the written message into the capture when it's flushed
+type writer struct {
+	sock   *Socket
+	writer io.WriteCloser
+	data   []byte
+}
+
+// Write implements the io.Writer interface
+func (wr *writer) Write(p []byte) (int, error) {
+	n, err := wr.writer.Write(p)
+	wr.data = append(wr.data, p[:n]...)
+	return n, err
+}
+
+// Close implements the io.Closer interface. The message is captured before
+// it's flushed to preserve the order of the messages in the capture,
+// it's thus captured even if flushing fails
+func (wr *writer) Close() error {
+	if len(wr.data) > 0 {
+		wr.sock.record(wr.sock.writeDir, wr.data)
+	}
+	return wr.writer.Close()
+}
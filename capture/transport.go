@@ -0,0 +1,43 @@
+package capture
+
+import (
+	"sync/atomic"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// Transport wraps a webwire transport capturing the traffic
+// of all connections it accepts
+type Transport struct {
+	wwr.Transport
+	capture      *Writer
+	connectionID uint64
+}
+
+// NewTransport wraps the given transport capturing all traffic
+// into the given capture
+func NewTransport(trans wwr.Transport, capture *Writer) *Transport {
+	return &Transport{
+		Transport: trans,
+		capture:   capture,
+	}
+}
+
+// Initialize implements the webwire.Transport interface
+func (trans *Transport) Initialize(
+	options wwr.ServerOptions,
+	isShuttingdown wwr.IsShuttingDown,
+	onNewConnection wwr.OnNewConnection,
+) error {
+	return trans.Transport.Initialize(
+		options,
+		isShuttingdown,
+		func(connectionOptions wwr.ConnectionOptions, sock wwr.Socket) {
+			onNewConnection(connectionOptions, NewSocket(
+				sock,
+				atomic.AddUint64(&trans.connectionID, 1),
+				trans.capture,
+			))
+		},
+	)
+}
@@ -0,0 +1,128 @@
+package capture_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/test"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/require"
+)
+
+// readRecords reads all records of the given capture
+func readRecords(t *testing.T, data []byte) []capture.Record {
+	reader, err := capture.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	var records []capture.Record
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+}
+
+// request sends a request over the given socket and reads the reply
+func request(t *testing.T, sock wwr.ClientSocket) *message.Message {
+	require.NoError(t, sock.Dial(time.Now().Add(time.Second)))
+	conf := message.NewMessage(1024)
+	require.Nil(t, sock.Read(conf, time.Now().Add(time.Second)))
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{1, 2, 3, 4, 5, 6, 7, 8},
+		[]byte("echo"),
+		pld.Utf8,
+		[]byte("hello"),
+		true,
+	))
+
+	reply := message.NewMessage(1024)
+	require.Nil(t, sock.Read(reply, time.Now().Add(time.Second)))
+	require.Equal(t, message.MsgReplyUtf8, reply.MsgType)
+	require.Equal(t, []byte("hello"), reply.MsgPayload.Data)
+	return reply
+}
+
+// requireTraffic verifies the captured traffic of a single echo request
+func requireTraffic(t *testing.T, records []capture.Record, connID uint64) {
+	require.Len(t, records, 3)
+	require.Equal(t, capture.ServerToClient, records[0].Direction)
+	require.Equal(t, message.MsgAcceptConf, records[0].Data[0])
+	require.Equal(t, capture.ClientToServer, records[1].Direction)
+	require.Equal(t, message.MsgRequestUtf8, records[1].Data[0])
+	require.Equal(t, capture.ServerToClient, records[2].Direction)
+	require.Equal(t, message.MsgReplyUtf8, records[2].Data[0])
+	for _, rec := range records {
+		require.Equal(t, connID, rec.ConnectionID)
+		require.False(t, rec.Time.IsZero())
+	}
+}
+
+// setupServer sets up an echo server on the given transport
+func setupServer(t *testing.T, trans wwr.Transport) {
+	server, err := wwr.NewServer(
+		&test.ServerImpl{
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.Payload{
+					Encoding: msg.PayloadEncoding(),
+					Data:     msg.Payload(),
+				}, nil
+			},
+		},
+		wwr.ServerOptions{},
+		trans,
+	)
+	require.NoError(t, err)
+	go server.Run()
+}
+
+// TestTransport tests capturing the traffic of a server transport
+func TestTransport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+
+	trans := &memchan.Transport{}
+	setupServer(t, capture.NewTransport(trans, writer))
+
+	sock, err := (&memchan.ClientTransport{Server: trans}).NewSocket(time.Second)
+	require.NoError(t, err)
+	request(t, sock)
+	require.NoError(t, sock.Close())
+
+	requireTraffic(t, readRecords(t, buf.Bytes()), 1)
+}
+
+// TestClientSocket tests capturing the traffic of a client socket
+func TestClientSocket(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+
+	trans := &memchan.Transport{}
+	setupServer(t, trans)
+
+	sock, err := (&memchan.ClientTransport{Server: trans}).NewSocket(time.Second)
+	require.NoError(t, err)
+	request(t, capture.NewClientSocket(sock, 7, writer))
+	require.NoError(t, sock.Close())
+
+	requireTraffic(t, readRecords(t, buf.Bytes()), 7)
+}
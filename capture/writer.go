@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// Writer writes capture records. It's safe for concurrent use
+type Writer struct {
+	lock sync.Mutex
+	dest io.Writer
+	buf  []byte
+	err  error
+}
+
+// NewWriter creates a new capture writer writing the file header
+// to the given destination
+func NewWriter(dest io.Writer) (*Writer, error) {
+	if _, err := dest.Write(fileHeader[:]); err != nil {
+		return nil, err
+	}
+	return &Writer{dest: dest}, nil
+}
+
+// WriteRecord writes the given record. Each record is written to the
+// destination by a single write call. Once a write failed all subsequent
+// writes fail with the same error
+func (wr *Writer) WriteRecord(rec Record) error {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+
+	if wr.err != nil {
+		return wr.err
+	}
+
+	recLen := 4 + recordHeaderLen + len(rec.Data)
+	if cap(wr.buf) < recLen {
+		wr.buf = make([]byte, recLen)
+	}
+	buf := wr.buf[:recLen]
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(recordHeaderLen+len(rec.Data)))
+	binary.LittleEndian.PutUint64(buf[4:12], uint64(rec.Time.UnixNano()))
+	buf[12] = byte(rec.Direction)
+	binary.LittleEndian.PutUint64(buf[13:21], rec.ConnectionID)
+	copy(buf[21:], rec.Data)
+
+	if _, err := wr.dest.Write(buf); err != nil {
+		wr.err = err
+		return err
+	}
+	return nil
+}
+
+// Err returns the error the first failed write failed with
+func (wr *Writer) Err() error {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	return wr.err
+}
@@ -0,0 +1,95 @@
+package webwire
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qbeon/webwire-go/message"
+)
+
+// maxMinorProtocolVersion defines the highest supported minor version
+// of the protocol
+const maxMinorProtocolVersion = byte(2)
+
+// negotiation represents the result of the negotiation
+// of the protocol version and capabilities with a client
+type negotiation struct {
+	// hello represents the client hello message, nil if not required
+	hello *message.ClientHello
+
+	// confMsg represents the server configuration message to be sent
+	confMsg []byte
+
+	// capabilities represents the negotiated capabilities
+	capabilities message.Capabilities
+}
+
+// negotiate awaits the client hello message (if required) and negotiates
+// the protocol version and capabilities with the client. Returns a non-empty
+// rejection reason if the connection must be rejected
+func (srv *server) negotiate(sock Socket) (
+	result negotiation,
+	rejection string,
+	err error,
+) {
+	if srv.options.ClientHello != Enabled {
+		return negotiation{confMsg: srv.configMsg}, "", nil
+	}
+
+	msg := srv.messagePool.Get()
+	defer msg.Close()
+
+	if err := sock.Read(
+		msg,
+		time.Now().Add(srv.options.ReadTimeout), // Deadline
+	); err != nil {
+		return negotiation{}, "", fmt.Errorf(
+			"couldn't read client hello: %s",
+			err,
+		)
+	}
+
+	if msg.MsgType != message.MsgClientHello {
+		srv.metrics.ProtocolViolation()
+		return negotiation{}, "client hello expected", nil
+	}
+
+	// Copy the client hello because the message buffer is released
+	hello := msg.ClientHello
+	hello.ClientName = append([]byte(nil), hello.ClientName...)
+	hello.AuthBlob = append([]byte(nil), hello.AuthBlob...)
+
+	// Choose the highest mutually supported minor protocol version
+	minMinorVersion := srv.configuration.MinorProtocolVersion
+	if hello.MinMinorProtocolVersion > minMinorVersion {
+		minMinorVersion = hello.MinMinorProtocolVersion
+	}
+	minorVersion := maxMinorProtocolVersion
+	if hello.MaxMinorProtocolVersion < minorVersion {
+		minorVersion = hello.MaxMinorProtocolVersion
+	}
+	if hello.MajorProtocolVersion != srv.configuration.MajorProtocolVersion ||
+		minorVersion < minMinorVersion {
+		return negotiation{}, "unsupported protocol version", nil
+	}
+
+	conf := srv.configuration
+	conf.MinorProtocolVersion = minorVersion
+	if minorVersion >= 2 {
+		conf.Capabilities = hello.Capabilities & srv.capabilities
+	}
+
+	confMsg, err := message.NewAcceptConfMessage(conf)
+	if err != nil {
+		return negotiation{}, "", fmt.Errorf(
+			"couldn't compose server configuration message: %s",
+			err,
+		)
+	}
+
+	return negotiation{
+		hello:        &hello,
+		confMsg:      confMsg,
+		capabilities: conf.Capabilities,
+	}, "", nil
+}
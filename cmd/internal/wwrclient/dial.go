@@ -0,0 +1,70 @@
+package wwrclient
+
+import (
+	"fmt"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// ConfBufferSize defines the size of the buffer the server configuration
+// message is read into before the message buffer size is known
+const ConfBufferSize = 1024
+
+// Dial connects a new socket of the given transport and reads the server
+// configuration. A client hello is sent before if hello isn't nil.
+// The socket is closed if the server configuration can't be read
+func Dial(
+	trans wwr.ClientTransport,
+	timeout time.Duration,
+	hello *message.ClientHello,
+) (wwr.ClientSocket, message.ServerConfiguration, error) {
+	sock, err := trans.NewSocket(timeout)
+	if err != nil {
+		return nil, message.ServerConfiguration{}, err
+	}
+	if err := sock.Dial(time.Now().Add(timeout)); err != nil {
+		sock.Close()
+		return nil, message.ServerConfiguration{}, err
+	}
+
+	if hello != nil {
+		writer, err := sock.GetWriter()
+		if err != nil {
+			sock.Close()
+			return nil, message.ServerConfiguration{}, err
+		}
+		if err := message.WriteMsgClientHello(writer, *hello); err != nil {
+			sock.Close()
+			return nil, message.ServerConfiguration{}, fmt.Errorf(
+				"couldn't send client hello: %s",
+				err,
+			)
+		}
+	}
+
+	msg := message.NewMessage(ConfBufferSize)
+	if err := sock.Read(msg, time.Now().Add(timeout)); err != nil {
+		sock.Close()
+		return nil, message.ServerConfiguration{}, fmt.Errorf(
+			"couldn't read server configuration: %s",
+			err,
+		)
+	}
+	switch msg.MsgType {
+	case message.MsgAcceptConf:
+		return sock, msg.ServerConfiguration, nil
+	case message.MsgRejectConf:
+		sock.Close()
+		return nil, message.ServerConfiguration{}, fmt.Errorf(
+			"connection rejected: %s",
+			string(msg.MsgPayload.Data),
+		)
+	}
+	sock.Close()
+	return nil, message.ServerConfiguration{}, fmt.Errorf(
+		"unexpected message type %d instead of server configuration",
+		msg.MsgType,
+	)
+}
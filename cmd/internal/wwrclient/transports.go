@@ -0,0 +1,124 @@
+// Package wwrclient provides the loopback client transports and the connection
+// setup shared by the webwire command-line tools
+package wwrclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/transport/memchan"
+)
+
+// TransportOptions represents the options of a client transport
+type TransportOptions struct {
+	// ClientHello is true if the client sends a client hello
+	ClientHello bool
+
+	// EchoSignals enables echoing the signals of the client as server
+	// signals by loopback servers
+	EchoSignals bool
+}
+
+// TransportFactory creates a client transport connecting to a loopback server
+// configured by the options
+type TransportFactory func(opts TransportOptions) (wwr.ClientTransport, error)
+
+// Transports maps the names of the supported transports to their factories
+var Transports = map[string]TransportFactory{
+	"memchan": newMemchanTransport,
+}
+
+// TransportNames returns the sorted names of the supported transports
+func TransportNames() []string {
+	names := make([]string, 0, len(Transports))
+	for name := range Transports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTransport creates a client transport of the given type
+func NewTransport(
+	name string,
+	opts TransportOptions,
+) (wwr.ClientTransport, error) {
+	factory, exists := Transports[name]
+	if !exists {
+		return nil, fmt.Errorf(
+			"unsupported transport %q (supported: %v)",
+			name,
+			TransportNames(),
+		)
+	}
+	return factory(opts)
+}
+
+// newMemchanTransport creates a memchan client transport connecting to an
+// in-process loopback server. The loopback server echoes requests and, if
+// enabled, signals as server signals and creates a session on "login"
+// requests. It expects a client hello if the client sends one
+func newMemchanTransport(opts TransportOptions) (wwr.ClientTransport, error) {
+	serverOptions := wwr.ServerOptions{Sessions: wwr.Enabled}
+	if opts.ClientHello {
+		serverOptions.ClientHello = wwr.Enabled
+	}
+
+	trans := &memchan.Transport{}
+	server, err := wwr.NewServer(
+		loopbackServer{echoSignals: opts.EchoSignals},
+		serverOptions,
+		trans,
+	)
+	if err != nil {
+		return nil, err
+	}
+	go server.Run()
+	return &memchan.ClientTransport{Server: trans}, nil
+}
+
+// loopbackServer implements the webwire.ServerImplementation interface
+// of the memchan loopback server
+type loopbackServer struct {
+	echoSignals bool
+}
+
+// OnClientConnected implements the webwire.ServerImplementation interface
+func (loopbackServer) OnClientConnected(wwr.ConnectionOptions, wwr.Connection) {}
+
+// OnClientDisconnected implements the webwire.ServerImplementation interface
+func (loopbackServer) OnClientDisconnected(wwr.Connection, error) {}
+
+// OnSignal implements the webwire.ServerImplementation interface
+func (srv loopbackServer) OnSignal(
+	_ context.Context,
+	conn wwr.Connection,
+	msg wwr.Message,
+) {
+	if !srv.echoSignals {
+		return
+	}
+	conn.Signal(msg.Name(), wwr.Payload{
+		Encoding: msg.PayloadEncoding(),
+		Data:     append([]byte(nil), msg.Payload()...),
+	})
+}
+
+// OnRequest implements the webwire.ServerImplementation interface
+func (loopbackServer) OnRequest(
+	_ context.Context,
+	conn wwr.Connection,
+	msg wwr.Message,
+) (wwr.Payload, error) {
+	if string(msg.Name()) == "login" {
+		if err := conn.CreateSession(nil); err != nil {
+			return wwr.Payload{}, err
+		}
+	}
+	return wwr.Payload{
+		Encoding: msg.PayloadEncoding(),
+		Data:     msg.Payload(),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package wwrclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTransport tests creating transports
+func TestNewTransport(t *testing.T) {
+	_, err := NewTransport("unknown", TransportOptions{})
+	require.Error(t, err)
+
+	trans, err := NewTransport("memchan", TransportOptions{})
+	require.NoError(t, err)
+	sock, conf, err := Dial(trans, time.Second, nil)
+	require.NoError(t, err)
+	require.True(t, conf.MessageBufferSize > 0)
+	require.NoError(t, sock.Close())
+}
+
+// TestDialClientHello tests dialing a server expecting a client hello
+func TestDialClientHello(t *testing.T) {
+	trans, err := NewTransport("memchan", TransportOptions{ClientHello: true})
+	require.NoError(t, err)
+	sock, _, err := Dial(trans, time.Second, &message.ClientHello{
+		MajorProtocolVersion:    2,
+		MaxMinorProtocolVersion: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, sock.Close())
+}
+
+// TestDialUnreachable tests dialing an unreachable server
+func TestDialUnreachable(t *testing.T) {
+	_, _, err := Dial(&memchan.ClientTransport{}, time.Second, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/cmd/internal/wwrclient"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// Benchmark modes
+const (
+	modeRequest = "request"
+	modeSignal  = "signal"
+)
+
+// benchmark represents a benchmark run
+type benchmark struct {
+	opts    options
+	name    []byte
+	payload pld.Payload
+
+	// interval defines the pause between two messages sent on a single
+	// connection, zero if the rate is unlimited
+	interval time.Duration
+	stats    *stats
+}
+
+// newBenchmark creates a new benchmark for the given options
+func newBenchmark(opts options) (*benchmark, error) {
+	if opts.connections < 1 {
+		return nil, errors.New("at least one connection is required")
+	}
+	if opts.inflight < 1 {
+		return nil, errors.New("at least one in-flight request is required")
+	}
+	if opts.mode != modeRequest && opts.mode != modeSignal {
+		return nil, fmt.Errorf("unsupported mode %q", opts.mode)
+	}
+
+	payload, err := newPayload(opts.encoding, opts.payloadSize)
+	if err != nil {
+		return nil, err
+	}
+
+	bench := &benchmark{
+		opts:    opts,
+		name:    []byte(opts.name),
+		payload: payload,
+		stats:   newStats(),
+	}
+	if opts.rate > 0 {
+		bench.interval = time.Duration(
+			float64(time.Second) * float64(opts.connections) / opts.rate,
+		)
+	}
+	return bench, nil
+}
+
+// newPayload creates a payload of the given encoding and size
+func newPayload(encoding string, size int) (pld.Payload, error) {
+	if size < 0 {
+		return pld.Payload{}, errors.New("negative payload size")
+	}
+	data := make([]byte, size)
+	switch encoding {
+	case "binary":
+		for i := range data {
+			data[i] = byte(i)
+		}
+		return pld.Payload{Encoding: pld.Binary, Data: data}, nil
+	case "utf8":
+		for i := range data {
+			data[i] = 'a' + byte(i%26)
+		}
+		return pld.Payload{Encoding: pld.Utf8, Data: data}, nil
+	case "utf16":
+		if size%2 != 0 {
+			return pld.Payload{}, errors.New(
+				"UTF16 payload size must be even",
+			)
+		}
+		for i := 0; i < len(data); i += 2 {
+			data[i] = 'a' + byte(i/2%26)
+		}
+		return pld.Payload{Encoding: pld.Utf16, Data: data}, nil
+	}
+	return pld.Payload{}, fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// run opens the connections and sends messages until the configured duration
+// elapsed returning the report
+func (bench *benchmark) run(trans wwr.ClientTransport) (*report, error) {
+	// Establish all connections before starting the clock
+	conns := make([]*connection, 0, bench.opts.connections)
+	var lock sync.Mutex
+	var dialers sync.WaitGroup
+	dialers.Add(bench.opts.connections)
+	for i := 0; i < bench.opts.connections; i++ {
+		go func() {
+			defer dialers.Done()
+			sock, conf, err := wwrclient.Dial(
+				trans,
+				bench.opts.timeout,
+				nil,
+			)
+			if err != nil {
+				bench.stats.connectionFailed(err)
+				return
+			}
+			bench.stats.connected()
+			lock.Lock()
+			conns = append(conns, newConnection(bench, sock, conf))
+			lock.Unlock()
+		}()
+	}
+	dialers.Wait()
+
+	if len(conns) < 1 {
+		return nil, fmt.Errorf(
+			"all %d connections failed: %v",
+			bench.opts.connections,
+			bench.stats.connectionFailures,
+		)
+	}
+
+	start := time.Now()
+	deadline := start.Add(bench.opts.duration)
+	var workers sync.WaitGroup
+	workers.Add(len(conns))
+	for _, conn := range conns {
+		go func(conn *connection) {
+			defer workers.Done()
+			conn.run(start, deadline)
+		}(conn)
+	}
+	workers.Wait()
+
+	return newReport(bench, time.Since(start)), nil
+}
+
+// connection represents a single benchmarking connection
+type connection struct {
+	bench *benchmark
+	sock  wwr.ClientSocket
+	conf  message.ServerConfiguration
+
+	// slots limits the number of in-flight requests
+	slots chan struct{}
+
+	// overflows is true when the messages exceed the message buffer size
+	// of the server, such messages are failed without being sent
+	overflows bool
+
+	lock      sync.Mutex
+	lastID    uint64
+	pending   map[[8]byte]time.Time
+	sent      uint64
+	latencies []time.Duration
+	errors    map[string]uint64
+	drained   chan struct{}
+	closed    bool
+}
+
+// newConnection creates a new benchmarking connection
+func newConnection(
+	bench *benchmark,
+	sock wwr.ClientSocket,
+	conf message.ServerConfiguration,
+) *connection {
+	msgLen := message.CalcMsgLenSignal(
+		bench.name,
+		bench.payload.Encoding,
+		bench.payload.Data,
+	)
+	if bench.opts.mode == modeRequest {
+		msgLen = message.CalcMsgLenRequest(
+			bench.name,
+			bench.payload.Encoding,
+			bench.payload.Data,
+		)
+	}
+
+	return &connection{
+		bench:     bench,
+		sock:      sock,
+		conf:      conf,
+		slots:     make(chan struct{}, bench.opts.inflight),
+		overflows: uint32(msgLen) > conf.MessageBufferSize,
+		pending:   make(map[[8]byte]time.Time),
+		errors:    make(map[string]uint64),
+		drained:   make(chan struct{}, 1),
+	}
+}
+
+// fail records an error of the given kind
+func (conn *connection) fail(kind string) {
+	conn.lock.Lock()
+	conn.errors[kind]++
+	conn.lock.Unlock()
+}
+
+// run sends messages until the deadline, waits for the outstanding replies
+// and merges the results into the benchmark stats
+func (conn *connection) run(start, deadline time.Time) {
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		conn.readLoop()
+	}()
+
+	conn.sendLoop(start, deadline)
+
+	// Wait for the outstanding replies
+	if conn.bench.opts.mode == modeRequest {
+		timer := time.NewTimer(conn.bench.opts.timeout)
+		for conn.outstanding() > 0 {
+			select {
+			case <-conn.drained:
+				continue
+			case <-readerDone:
+			case <-timer.C:
+			}
+			break
+		}
+		timer.Stop()
+	}
+
+	conn.lock.Lock()
+	conn.closed = true
+	conn.lock.Unlock()
+	conn.sock.Close()
+	<-readerDone
+
+	// Count unanswered requests
+	conn.lock.Lock()
+	if len(conn.pending) > 0 {
+		conn.errors[errKindTimeout] += uint64(len(conn.pending))
+	}
+	conn.lock.Unlock()
+
+	conn.bench.stats.add(conn.sent, conn.latencies, conn.errors)
+}
+
+// outstanding returns the number of unanswered requests
+func (conn *connection) outstanding() int {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return len(conn.pending)
+}
+
+// sendLoop sends messages at the configured rate until the deadline
+func (conn *connection) sendLoop(start, deadline time.Time) {
+	next := start
+	for {
+		if conn.bench.interval > 0 {
+			next = next.Add(conn.bench.interval)
+			if !next.Before(deadline) {
+				time.Sleep(time.Until(deadline))
+				return
+			}
+			time.Sleep(time.Until(next))
+		} else if !time.Now().Before(deadline) {
+			return
+		}
+
+		var err error
+		if conn.bench.opts.mode == modeRequest {
+			// Wait for a free slot
+			timer := time.NewTimer(time.Until(deadline))
+			select {
+			case conn.slots <- struct{}{}:
+				timer.Stop()
+			case <-timer.C:
+				return
+			}
+			err = conn.request()
+		} else {
+			err = conn.signal()
+		}
+		if err != nil {
+			kind := sendErrKind(err)
+			conn.fail(kind)
+			if kind == errKindConnectionLost {
+				return
+			}
+		}
+	}
+}
+
+// request sends a single request
+func (conn *connection) request() error {
+	if conn.overflows {
+		<-conn.slots
+		return wwr.ErrBufferOverflow{}
+	}
+
+	writer, err := conn.sock.GetWriter()
+	if err != nil {
+		<-conn.slots
+		return err
+	}
+
+	conn.lock.Lock()
+	conn.lastID++
+	var id [8]byte
+	binary.LittleEndian.PutUint64(id[:], conn.lastID)
+	conn.pending[id] = time.Now()
+	conn.sent++
+	conn.lock.Unlock()
+
+	if err := message.WriteMsgRequest(
+		writer,
+		id[:],
+		conn.bench.name,
+		conn.bench.payload.Encoding,
+		conn.bench.payload.Data,
+		false,
+	); err != nil {
+		conn.lock.Lock()
+		delete(conn.pending, id)
+		conn.lock.Unlock()
+		<-conn.slots
+		return err
+	}
+	return nil
+}
+
+// signal sends a single signal
+func (conn *connection) signal() error {
+	if conn.overflows {
+		return wwr.ErrBufferOverflow{}
+	}
+
+	writer, err := conn.sock.GetWriter()
+	if err != nil {
+		return err
+	}
+	if err := message.WriteMsgSignal(
+		writer,
+		conn.bench.name,
+		conn.bench.payload.Encoding,
+		conn.bench.payload.Data,
+		false,
+	); err != nil {
+		return err
+	}
+	conn.lock.Lock()
+	conn.sent++
+	conn.lock.Unlock()
+	return nil
+}
+
+// readLoop reads the replies until the socket is closed
+func (conn *connection) readLoop() {
+	msg := message.NewMessage(conn.conf.MessageBufferSize)
+	for {
+		if err := conn.sock.Read(msg, time.Time{}); err != nil {
+			conn.lock.Lock()
+			if !conn.closed && len(conn.pending) > 0 && !err.IsCloseErr() {
+				conn.errors[errKindConnectionLost] += uint64(len(conn.pending))
+				conn.pending = make(map[[8]byte]time.Time)
+			}
+			conn.lock.Unlock()
+			return
+		}
+
+		switch msg.MsgType {
+		case message.MsgReplyBinary,
+			message.MsgReplyUtf8,
+			message.MsgReplyUtf16,
+			message.MsgReplyError,
+			message.MsgReplyShutdown,
+			message.MsgReplyInternalError,
+			message.MsgReplySessionNotFound,
+			message.MsgReplyMaxSessConnsReached,
+			message.MsgReplySessionsDisabled:
+		default:
+			// Ignore heartbeats, server signals and notifications
+			continue
+		}
+
+		received := time.Now()
+		conn.lock.Lock()
+		sentAt, exists := conn.pending[msg.MsgIdentifier]
+		if exists {
+			delete(conn.pending, msg.MsgIdentifier)
+			if kind := replyErrKind(msg); kind != "" {
+				conn.errors[kind]++
+			} else {
+				conn.latencies = append(conn.latencies, received.Sub(sentAt))
+			}
+		}
+		conn.lock.Unlock()
+
+		if exists {
+			<-conn.slots
+			select {
+			case conn.drained <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
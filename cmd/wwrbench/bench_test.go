@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/require"
+)
+
+// testOptions returns the options of a short benchmark
+func testOptions() options {
+	return options{
+		transport:   "memchan",
+		connections: 3,
+		rate:        300,
+		duration:    200 * time.Millisecond,
+		mode:        modeRequest,
+		name:        "bench",
+		payloadSize: 16,
+		encoding:    "binary",
+		inflight:    2,
+		timeout:     time.Second,
+	}
+}
+
+// runTest runs a benchmark with the given options returning the report
+func runTest(t *testing.T, opts options) *report {
+	bench, err := newBenchmark(opts)
+	require.NoError(t, err)
+	trans, err := newTransport(opts)
+	require.NoError(t, err)
+	rep, err := bench.run(trans)
+	require.NoError(t, err)
+	return rep
+}
+
+// TestNewPayload tests generating payloads
+func TestNewPayload(t *testing.T) {
+	payload, err := newPayload("utf16", 6)
+	require.NoError(t, err)
+	require.Equal(t, pld.Utf16, payload.Encoding)
+	require.Equal(t, []byte{'a', 0, 'b', 0, 'c', 0}, payload.Data)
+	require.NoError(t, pld.ValidateUtf16(payload.Data))
+
+	payload, err = newPayload("utf8", 3)
+	require.NoError(t, err)
+	require.Equal(t, []byte("abc"), payload.Data)
+
+	_, err = newPayload("utf16", 3)
+	require.Error(t, err)
+	_, err = newPayload("latin1", 3)
+	require.Error(t, err)
+}
+
+// TestPercentile tests computing latency percentiles
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	require.Equal(t, time.Duration(50), percentile(sorted, 50))
+	require.Equal(t, time.Duration(99), percentile(sorted, 99))
+	require.Equal(t, time.Duration(100), percentile(sorted, 99.9))
+	require.Equal(t, time.Duration(1), percentile(sorted, 0))
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+// TestErrKinds tests classifying errors
+func TestErrKinds(t *testing.T) {
+	require.Equal(t, "", replyErrKind(&message.Message{
+		MsgType: message.MsgReplyUtf8,
+	}))
+	require.Equal(t, errKindShutdown, replyErrKind(&message.Message{
+		MsgType: message.MsgReplyShutdown,
+	}))
+	require.Equal(t, errKindInternal, replyErrKind(&message.Message{
+		MsgType: message.MsgReplyInternalError,
+	}))
+	require.Equal(t, "error RATE_LIMIT_EXCEEDED", replyErrKind(&message.Message{
+		MsgType: message.MsgReplyError,
+		MsgName: []byte("RATE_LIMIT_EXCEEDED"),
+	}))
+	require.Equal(t, errKindBufferOverflow, sendErrKind(wwr.ErrBufferOverflow{}))
+	require.Equal(t, errKindConnectionLost, sendErrKind(wwr.ErrDisconnected{}))
+}
+
+// TestBenchmarkRequests tests benchmarking requests at a limited rate
+func TestBenchmarkRequests(t *testing.T) {
+	rep := runTest(t, testOptions())
+	require.Equal(t, 3, rep.connections)
+	require.Zero(t, rep.failedConnections())
+	require.True(t, rep.sent > 0)
+	require.Equal(t, rep.sent, rep.succeeded)
+	require.Len(t, rep.latencies, int(rep.succeeded))
+	require.Empty(t, rep.errors)
+
+	// Expect the target rate to be roughly met
+	require.InDelta(t, 60, rep.sent, 15)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, rep.print(out))
+	require.Contains(t, out.String(), "latency p99.9")
+	require.Contains(t, out.String(), "errors shutdown")
+	require.Contains(t, out.String(), "errors internal")
+	require.Contains(t, out.String(), "errors buffer overflow")
+}
+
+// TestBenchmarkSignals tests benchmarking signals at an unlimited rate
+func TestBenchmarkSignals(t *testing.T) {
+	opts := testOptions()
+	opts.mode = modeSignal
+	opts.rate = 0
+	opts.encoding = "utf16"
+	rep := runTest(t, opts)
+	require.True(t, rep.sent > 0)
+	require.Empty(t, rep.latencies)
+	require.Empty(t, rep.errors)
+}
+
+// TestBenchmarkBufferOverflow tests reporting messages exceeding
+// the message buffer size of the server
+func TestBenchmarkBufferOverflow(t *testing.T) {
+	opts := testOptions()
+	opts.payloadSize = 8192 // Default message buffer size
+	rep := runTest(t, opts)
+	require.Zero(t, rep.sent)
+	require.True(t, rep.errors[errKindBufferOverflow] > 0)
+}
+
+// TestBenchmarkConnectionFailures tests reporting failed connections
+func TestBenchmarkConnectionFailures(t *testing.T) {
+	bench, err := newBenchmark(testOptions())
+	require.NoError(t, err)
+	_, err = bench.run(&memchan.ClientTransport{})
+	require.Error(t, err)
+	require.Equal(t, uint64(3), bench.stats.connectionFailures["server unreachable"])
+}
+
+// TestBenchmarkInvalidOptions tests rejecting invalid options
+func TestBenchmarkInvalidOptions(t *testing.T) {
+	for _, modify := range []func(*options){
+		func(opts *options) { opts.connections = 0 },
+		func(opts *options) { opts.inflight = 0 },
+		func(opts *options) { opts.mode = "stream" },
+		func(opts *options) { opts.payloadSize = -1 },
+	} {
+		opts := testOptions()
+		modify(&opts)
+		_, err := newBenchmark(opts)
+		require.Error(t, err)
+	}
+
+	opts := testOptions()
+	opts.transport = "unknown"
+	require.Error(t, run(opts, &bytes.Buffer{}))
+}
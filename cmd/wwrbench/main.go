@@ -0,0 +1,113 @@
+// Command wwrbench is an in-process load generator measuring the overhead of
+// the webwire server itself. It opens a number of connections to a loopback
+// server over the memchan transport and sends requests or signals with
+// payloads of a given size at a target rate. It reports the
+// throughput, the request latency percentiles, the errors by type (shutdown,
+// internal, buffer overflow, timeout and error replies by code) and
+// the connection failures.
+//
+// Usage:
+//
+//	wwrbench -transport memchan -connections 100 -rate 10000 -duration 30s \
+//		-mode request -payload-size 1024 -encoding binary
+//
+// The rate is the total number of messages per second across all
+// connections, zero means unlimited. Each connection keeps at most
+// -inflight requests unanswered. wwrbench doesn't ship any network transport
+// and thus can't benchmark remote servers or the network between them
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/cmd/internal/wwrclient"
+)
+
+// options represents the command-line options
+type options struct {
+	transport   string
+	connections int
+	rate        float64
+	duration    time.Duration
+	mode        string
+	name        string
+	payloadSize int
+	encoding    string
+	inflight    int
+	timeout     time.Duration
+}
+
+func main() {
+	var opts options
+	flag.StringVar(
+		&opts.transport,
+		"transport",
+		"memchan",
+		fmt.Sprintf(
+			"loopback transport implementation %v",
+			wwrclient.TransportNames(),
+		),
+	)
+	flag.IntVar(&opts.connections, "connections", 10, "number of connections")
+	flag.Float64Var(
+		&opts.rate,
+		"rate",
+		0,
+		"target messages per second across all connections (0 = unlimited)",
+	)
+	flag.DurationVar(&opts.duration, "duration", 10*time.Second, "duration")
+	flag.StringVar(&opts.mode, "mode", modeRequest, "request or signal")
+	flag.StringVar(&opts.name, "name", "bench", "request or signal name")
+	flag.IntVar(&opts.payloadSize, "payload-size", 16, "payload size in bytes")
+	flag.StringVar(
+		&opts.encoding,
+		"encoding",
+		"binary",
+		"payload encoding (binary, utf8 or utf16)",
+	)
+	flag.IntVar(
+		&opts.inflight,
+		"inflight",
+		1,
+		"maximum number of unanswered requests per connection",
+	)
+	flag.DurationVar(
+		&opts.timeout,
+		"timeout",
+		10*time.Second,
+		"dial and reply timeout",
+	)
+	flag.Parse()
+
+	if err := run(opts, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "wwrbench: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// newTransport creates the transport given by the options
+func newTransport(opts options) (wwr.ClientTransport, error) {
+	return wwrclient.NewTransport(opts.transport, wwrclient.TransportOptions{})
+}
+
+// run runs the benchmark described by the options and prints the report
+func run(opts options, out io.Writer) error {
+	bench, err := newBenchmark(opts)
+	if err != nil {
+		return err
+	}
+	trans, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+	rep, err := bench.run(trans)
+	if err != nil {
+		return err
+	}
+	return rep.print(out)
+}
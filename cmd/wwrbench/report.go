@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// percentiles lists the reported latency percentiles
+var percentiles = []float64{50, 90, 99, 99.9}
+
+// report represents the results of a benchmark
+type report struct {
+	opts               options
+	elapsed            time.Duration
+	connections        int
+	connectionFailures map[string]uint64
+	sent               uint64
+	succeeded          uint64
+	latencies          []time.Duration
+	errors             map[string]uint64
+}
+
+// newReport creates a report from the collected benchmark stats
+func newReport(bench *benchmark, elapsed time.Duration) *report {
+	st := bench.stats
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.sortLatencies()
+	return &report{
+		opts:               bench.opts,
+		elapsed:            elapsed,
+		connections:        st.connections,
+		connectionFailures: st.connectionFailures,
+		sent:               st.sent,
+		succeeded:          st.succeeded,
+		latencies:          st.latencies,
+		errors:             st.errors,
+	}
+}
+
+// throughput returns the number of successful operations per second.
+// Signals are considered successful when sent
+func (rep *report) throughput() float64 {
+	done := rep.succeeded
+	if rep.opts.mode == modeSignal {
+		done = rep.sent
+	}
+	return float64(done) / rep.elapsed.Seconds()
+}
+
+// failedConnections returns the number of failed connection attempts
+func (rep *report) failedConnections() uint64 {
+	var failed uint64
+	for _, count := range rep.connectionFailures {
+		failed += count
+	}
+	return failed
+}
+
+// sortedKeys returns the sorted keys of the given counters
+func sortedKeys(counters map[string]uint64) []string {
+	keys := make([]string, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// print writes the report in readable form
+func (rep *report) print(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	target := "unlimited"
+	if rep.opts.rate > 0 {
+		target = fmt.Sprintf("%.1f/s", rep.opts.rate)
+	}
+	fmt.Fprintf(w, "transport\t%s\n", rep.opts.transport)
+	fmt.Fprintf(
+		w,
+		"mode\t%s %q, %d byte %s payload\n",
+		rep.opts.mode,
+		rep.opts.name,
+		rep.opts.payloadSize,
+		rep.opts.encoding,
+	)
+	fmt.Fprintf(
+		w,
+		"connections\t%d established, %d failed\n",
+		rep.connections,
+		rep.failedConnections(),
+	)
+	fmt.Fprintf(w, "duration\t%s\n", rep.elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "sent\t%d\n", rep.sent)
+	if rep.opts.mode == modeRequest {
+		fmt.Fprintf(w, "succeeded\t%d\n", rep.succeeded)
+	}
+	fmt.Fprintf(
+		w,
+		"throughput\t%.1f/s (target %s)\n",
+		rep.throughput(),
+		target,
+	)
+
+	if rep.opts.mode == modeRequest && len(rep.latencies) > 0 {
+		fmt.Fprintf(w, "latency min\t%s\n", rep.latencies[0])
+		for _, p := range percentiles {
+			fmt.Fprintf(
+				w,
+				"latency p%g\t%s\n",
+				p,
+				percentile(rep.latencies, p),
+			)
+		}
+		fmt.Fprintf(
+			w,
+			"latency max\t%s\n",
+			rep.latencies[len(rep.latencies)-1],
+		)
+	}
+
+	// Always report the common error kinds
+	errors := make(map[string]uint64, len(rep.errors))
+	for _, kind := range reportedErrKinds {
+		errors[kind] = 0
+	}
+	for kind, count := range rep.errors {
+		errors[kind] = count
+	}
+	for _, kind := range sortedKeys(errors) {
+		fmt.Fprintf(w, "errors %s\t%d\n", kind, errors[kind])
+	}
+
+	for _, reason := range sortedKeys(rep.connectionFailures) {
+		fmt.Fprintf(
+			w,
+			"connection failures %q\t%d\n",
+			reason,
+			rep.connectionFailures[reason],
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Error kinds
+const (
+	errKindShutdown       = "shutdown"
+	errKindInternal       = "internal"
+	errKindBufferOverflow = "buffer overflow"
+	errKindTimeout        = "timeout"
+	errKindConnectionLost = "connection lost"
+	errKindSend           = "send"
+)
+
+// reportedErrKinds lists the error kinds that are always reported
+var reportedErrKinds = []string{
+	errKindShutdown,
+	errKindInternal,
+	errKindBufferOverflow,
+}
+
+// replyErrKind returns the error kind of the given reply
+// or an empty string if the reply is successful
+func replyErrKind(msg *message.Message) string {
+	switch msg.MsgType {
+	case message.MsgReplyBinary, message.MsgReplyUtf8, message.MsgReplyUtf16:
+		return ""
+	case message.MsgReplyShutdown:
+		return errKindShutdown
+	case message.MsgReplyInternalError:
+		// Replies exceeding the server's message buffer are failed
+		// with an internal error as well
+		return errKindInternal
+	case message.MsgReplyError:
+		return "error " + string(msg.MsgName)
+	case message.MsgReplySessionNotFound:
+		return "session not found"
+	case message.MsgReplyMaxSessConnsReached:
+		return "max session connections reached"
+	case message.MsgReplySessionsDisabled:
+		return "sessions disabled"
+	}
+	return "unexpected reply"
+}
+
+// sendErrKind returns the error kind of the given send error
+func sendErrKind(err error) string {
+	switch err.(type) {
+	case wwr.ErrBufferOverflow:
+		return errKindBufferOverflow
+	case wwr.ErrDisconnected:
+		return errKindConnectionLost
+	}
+	return errKindSend
+}
+
+// stats collects the results of a benchmark. It's safe for concurrent use
+type stats struct {
+	lock               sync.Mutex
+	connections        int
+	connectionFailures map[string]uint64
+	sent               uint64
+	succeeded          uint64
+	latencies          []time.Duration
+	errors             map[string]uint64
+}
+
+// newStats creates a new empty stats collector
+func newStats() *stats {
+	return &stats{
+		connectionFailures: make(map[string]uint64),
+		errors:             make(map[string]uint64),
+	}
+}
+
+// connected records an established connection
+func (st *stats) connected() {
+	st.lock.Lock()
+	st.connections++
+	st.lock.Unlock()
+}
+
+// connectionFailed records a failed connection attempt
+func (st *stats) connectionFailed(err error) {
+	st.lock.Lock()
+	st.connectionFailures[err.Error()]++
+	st.lock.Unlock()
+}
+
+// add merges the results of a single connection
+func (st *stats) add(
+	sent uint64,
+	latencies []time.Duration,
+	errors map[string]uint64,
+) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.sent += sent
+	st.succeeded += uint64(len(latencies))
+	st.latencies = append(st.latencies, latencies...)
+	for kind, count := range errors {
+		st.errors[kind] += count
+	}
+}
+
+// percentile returns the latency at the given percentile (0-100)
+// of the given sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) < 1 {
+		return 0
+	}
+	index := int(p/100*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// sortLatencies sorts the recorded latencies in ascending order
+func (st *stats) sortLatencies() {
+	sort.Slice(st.latencies, func(i, j int) bool {
+		return st.latencies[i] < st.latencies[j]
+	})
+}
@@ -0,0 +1,166 @@
+// Package cli implements the interactive command-line client of the wwrcli
+// command. It prints the server configuration and all incoming server signals
+// and session notifications and allows sending requests and signals with
+// binary, UTF8 or UTF16 encoded payloads as well as restoring and closing
+// sessions. The client isn't bound to any transport, Run connects over any
+// given webwire.ClientTransport implementation
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Options represents the options of the client
+type Options struct {
+	// Timeout defines the dial and request timeout
+	Timeout time.Duration
+
+	// Hello enables sending a client hello when connecting
+	Hello bool
+
+	// Request, Signal, Restore and CloseSession define the command to be
+	// executed non-interactively, the commands are read from stdin if none
+	// of them is set
+	Request      string
+	Signal       string
+	Restore      string
+	CloseSession bool
+
+	// Encoding defines the payload encoding of the request or signal
+	// (binary, utf8 or utf16)
+	Encoding string
+
+	// Payload defines the payload text of the request or signal
+	Payload string
+
+	// PayloadFile defines the file the payload of the request or signal is
+	// read from instead (- for stdin)
+	PayloadFile string
+
+	// Listen defines the duration incoming signals are printed for after
+	// the command was executed
+	Listen time.Duration
+}
+
+// Run connects to the server using the given transport and executes either
+// the command given by the options or the commands read from stdin
+func Run(
+	trans wwr.ClientTransport,
+	opts Options,
+	stdin io.Reader,
+	stdout io.Writer,
+) error {
+	cmd, oneShot, err := oneShotCommand(opts, stdin)
+	if err != nil {
+		return err
+	}
+
+	var hello *message.ClientHello
+	if opts.Hello {
+		hello = &message.ClientHello{
+			MajorProtocolVersion:    2,
+			MinMinorProtocolVersion: 0,
+			MaxMinorProtocolVersion: 2,
+			ClientName:              []byte("wwrcli"),
+		}
+	}
+
+	printer := &printer{out: stdout}
+	clt, err := dial(trans, opts.Timeout, hello, printer)
+	if err != nil {
+		return err
+	}
+	defer clt.close()
+	printer.serverConfiguration(clt.conf)
+
+	if oneShot {
+		if err := execute(clt, cmd); err != nil {
+			return err
+		}
+		time.Sleep(opts.Listen)
+		return nil
+	}
+
+	return interact(clt, stdin)
+}
+
+// readFileFrom returns a readFileFunc reading "-" from the given reader
+func readFileFrom(stdin io.Reader) readFileFunc {
+	return func(path string) ([]byte, error) {
+		if path == "-" {
+			return ioutil.ReadAll(stdin)
+		}
+		return ioutil.ReadFile(path)
+	}
+}
+
+// oneShotCommand returns the command given by the options
+// or false if none is given
+func oneShotCommand(opts Options, stdin io.Reader) (command, bool, error) {
+	line := ""
+	switch {
+	case opts.Request != "":
+		line = cmdRequest + " " + opts.Request
+	case opts.Signal != "":
+		line = cmdSignal + " " + opts.Signal
+	case opts.Restore != "":
+		return command{kind: cmdRestore, sessionKey: []byte(opts.Restore)},
+			true, nil
+	case opts.CloseSession:
+		return command{kind: cmdCloseSession}, true, nil
+	default:
+		return command{}, false, nil
+	}
+
+	if _, isEncoding := parseEncoding(opts.Encoding); !isEncoding {
+		return command{}, false, fmt.Errorf(
+			"unsupported encoding %q",
+			opts.Encoding,
+		)
+	}
+	line += " " + opts.Encoding
+	if opts.PayloadFile != "" {
+		line += " @" + opts.PayloadFile
+	} else {
+		line += " " + opts.Payload
+	}
+
+	cmd, err := parseCommand(line, readFileFrom(stdin))
+	return cmd, true, err
+}
+
+// interact executes the commands read line by line from the given reader
+// until quit or EOF
+func interact(clt *client, stdin io.Reader) error {
+	readFile := readFileFrom(stdin)
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		cmd, err := parseCommand(line, readFile)
+		if err != nil {
+			clt.printer.printf("error: %s", err)
+			continue
+		}
+		if cmd.kind == cmdQuit {
+			return nil
+		}
+		if err := execute(clt, cmd); err != nil {
+			if err == errConnectionClosed {
+				return err
+			}
+			clt.printer.printf("error: %s", err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/cmd/internal/wwrclient"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// errConnectionClosed is returned when the connection closed while waiting
+// for a reply
+var errConnectionClosed = errors.New("connection closed")
+
+// client represents a minimal webwire client printing all incoming messages
+type client struct {
+	sock    wwr.ClientSocket
+	conf    message.ServerConfiguration
+	timeout time.Duration
+	printer *printer
+
+	lock      sync.Mutex
+	lastID    uint64
+	pending   map[[8]byte]chan *message.Message
+	readerErr error
+	done      chan struct{}
+}
+
+// dial connects to the server using the given transport and reads the
+// server configuration. A client hello is sent before if hello isn't nil
+func dial(
+	trans wwr.ClientTransport,
+	timeout time.Duration,
+	hello *message.ClientHello,
+	printer *printer,
+) (*client, error) {
+	sock, conf, err := wwrclient.Dial(trans, timeout, hello)
+	if err != nil {
+		return nil, err
+	}
+
+	clt := &client{
+		sock:    sock,
+		conf:    conf,
+		timeout: timeout,
+		printer: printer,
+		pending: make(map[[8]byte]chan *message.Message),
+		done:    make(chan struct{}),
+	}
+	go clt.readLoop()
+	return clt, nil
+}
+
+// readLoop reads incoming messages dispatching replies to the pending
+// requests and printing all other messages until the socket is closed
+func (clt *client) readLoop() {
+	defer close(clt.done)
+	for {
+		msg := message.NewMessage(clt.conf.MessageBufferSize)
+		if err := clt.sock.Read(msg, time.Time{}); err != nil {
+			clt.lock.Lock()
+			if !err.IsCloseErr() {
+				clt.readerErr = err
+			}
+			for id, reply := range clt.pending {
+				close(reply)
+				delete(clt.pending, id)
+			}
+			clt.lock.Unlock()
+			return
+		}
+
+		if msg.MsgType == message.MsgHeartbeat {
+			continue
+		}
+
+		switch msg.MsgType {
+		case message.MsgSignalBinary,
+			message.MsgSignalUtf8,
+			message.MsgSignalUtf16,
+			message.MsgNotifySessionCreated,
+			message.MsgNotifySessionClosed:
+			clt.printer.message(msg)
+			continue
+		}
+
+		// Dispatch replies
+		clt.lock.Lock()
+		reply, exists := clt.pending[msg.MsgIdentifier]
+		delete(clt.pending, msg.MsgIdentifier)
+		clt.lock.Unlock()
+		if exists {
+			reply <- msg
+		} else {
+			clt.printer.message(msg)
+		}
+	}
+}
+
+// nextID registers a new pending request returning its identifier
+func (clt *client) nextID() ([8]byte, chan *message.Message) {
+	clt.lock.Lock()
+	defer clt.lock.Unlock()
+	clt.lastID++
+	var id [8]byte
+	binary.LittleEndian.PutUint64(id[:], clt.lastID)
+	reply := make(chan *message.Message, 1)
+	clt.pending[id] = reply
+	return id, reply
+}
+
+// awaitReply waits for the reply to the request of the given identifier
+func (clt *client) awaitReply(
+	id [8]byte,
+	reply chan *message.Message,
+) (*message.Message, error) {
+	timer := time.NewTimer(clt.timeout)
+	defer timer.Stop()
+	select {
+	case msg, ok := <-reply:
+		if !ok {
+			return nil, errConnectionClosed
+		}
+		return msg, nil
+	case <-timer.C:
+		clt.lock.Lock()
+		delete(clt.pending, id)
+		clt.lock.Unlock()
+		return nil, errors.New("request timed out")
+	}
+}
+
+// request sends a request and waits for the reply
+func (clt *client) request(
+	name []byte,
+	payload pld.Payload,
+) (*message.Message, error) {
+	writer, err := clt.sock.GetWriter()
+	if err != nil {
+		return nil, err
+	}
+	id, reply := clt.nextID()
+	if err := message.WriteMsgRequest(
+		writer,
+		id[:],
+		name,
+		payload.Encoding,
+		payload.Data,
+		true,
+	); err != nil {
+		clt.lock.Lock()
+		delete(clt.pending, id)
+		clt.lock.Unlock()
+		return nil, err
+	}
+	return clt.awaitReply(id, reply)
+}
+
+// signal sends a signal
+func (clt *client) signal(name []byte, payload pld.Payload) error {
+	writer, err := clt.sock.GetWriter()
+	if err != nil {
+		return err
+	}
+	return message.WriteMsgSignal(
+		writer,
+		name,
+		payload.Encoding,
+		payload.Data,
+		true,
+	)
+}
+
+// sessionRequest sends a session restoration or closure request
+// and waits for the reply
+func (clt *client) sessionRequest(
+	reqType byte,
+	key []byte,
+) (*message.Message, error) {
+	writer, err := clt.sock.GetWriter()
+	if err != nil {
+		return nil, err
+	}
+	id, reply := clt.nextID()
+	if err := message.WriteMsgNamelessRequest(
+		writer,
+		reqType,
+		id[:],
+		key,
+	); err != nil {
+		clt.lock.Lock()
+		delete(clt.pending, id)
+		clt.lock.Unlock()
+		return nil, err
+	}
+	return clt.awaitReply(id, reply)
+}
+
+// close closes the connection and waits for the read loop to return
+func (clt *client) close() error {
+	err := clt.sock.Close()
+	<-clt.done
+	return err
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// usage describes the interactive commands
+const usage = `commands:
+  request <name> [binary|utf8|utf16] [payload]  send a request
+  signal <name> [binary|utf8|utf16] [payload]   send a signal
+  restore <session key>                         restore a session
+  close-session                                 close the current session
+  help                                          print this help
+  quit                                          disconnect and exit
+the name "-" stands for no name, the payload is either the text following
+the encoding or the contents of a file if prefixed by @ (@- reads stdin).
+The payload encoding defaults to utf8`
+
+// Command kinds
+const (
+	cmdRequest      = "request"
+	cmdSignal       = "signal"
+	cmdRestore      = "restore"
+	cmdCloseSession = "close-session"
+	cmdHelp         = "help"
+	cmdQuit         = "quit"
+)
+
+// command represents a parsed command
+type command struct {
+	kind       string
+	name       []byte
+	payload    pld.Payload
+	sessionKey []byte
+}
+
+// readFileFunc reads the file at the given path, "-" stands for stdin
+type readFileFunc func(path string) ([]byte, error)
+
+// parseEncoding parses the name of a payload encoding
+func parseEncoding(name string) (pld.Encoding, bool) {
+	switch name {
+	case "binary":
+		return pld.Binary, true
+	case "utf8":
+		return pld.Utf8, true
+	case "utf16":
+		return pld.Utf16, true
+	}
+	return pld.Binary, false
+}
+
+// nextWord splits off the next space separated word
+func nextWord(line string) (word, rest string) {
+	line = strings.TrimLeft(line, " \t")
+	if end := strings.IndexAny(line, " \t"); end >= 0 {
+		return line[:end], strings.TrimLeft(line[end+1:], " \t")
+	}
+	return line, ""
+}
+
+// loadPayload creates a payload of the given encoding either from the given
+// text or, if prefixed by @, from the contents of the referenced file.
+// File contents are sent as is while text is encoded
+func loadPayload(
+	encoding pld.Encoding,
+	text string,
+	readFile readFileFunc,
+) (pld.Payload, error) {
+	if strings.HasPrefix(text, "@") {
+		data, err := readFile(text[1:])
+		if err != nil {
+			return pld.Payload{}, err
+		}
+		return pld.Payload{Encoding: encoding, Data: data}, nil
+	}
+	if text == "" {
+		return pld.Payload{Encoding: encoding}, nil
+	}
+	return pld.FromString(text, encoding)
+}
+
+// parseCommand parses an interactive command line
+func parseCommand(line string, readFile readFileFunc) (command, error) {
+	kind, rest := nextWord(line)
+	switch kind {
+	case cmdHelp, cmdQuit, cmdCloseSession:
+		if rest != "" {
+			return command{}, fmt.Errorf("%s takes no arguments", kind)
+		}
+		return command{kind: kind}, nil
+
+	case cmdRestore:
+		key, rest := nextWord(rest)
+		if key == "" || rest != "" {
+			return command{}, errors.New("restore takes exactly one session key")
+		}
+		return command{kind: kind, sessionKey: []byte(key)}, nil
+
+	case cmdRequest, cmdSignal:
+		name, rest := nextWord(rest)
+		if name == "" {
+			return command{}, fmt.Errorf("%s requires a name", kind)
+		}
+		cmd := command{kind: kind}
+		if name != "-" {
+			cmd.name = []byte(name)
+		}
+
+		encoding := pld.Utf8
+		if word, afterWord := nextWord(rest); word != "" {
+			if enc, isEncoding := parseEncoding(word); isEncoding {
+				encoding = enc
+				rest = afterWord
+			}
+		}
+
+		payload, err := loadPayload(encoding, rest, readFile)
+		if err != nil {
+			return command{}, err
+		}
+		cmd.payload = payload
+		return cmd, nil
+
+	case "":
+		return command{}, errors.New("empty command")
+	}
+	return command{}, fmt.Errorf("unknown command %q", kind)
+}
+
+// execute executes the given command printing the result
+func execute(clt *client, cmd command) error {
+	switch cmd.kind {
+	case cmdRequest:
+		reply, err := clt.request(cmd.name, cmd.payload)
+		if err != nil {
+			return err
+		}
+		clt.printer.message(reply)
+
+	case cmdSignal:
+		if err := clt.signal(cmd.name, cmd.payload); err != nil {
+			return err
+		}
+		clt.printer.printf("signal sent")
+
+	case cmdRestore:
+		reply, err := clt.sessionRequest(
+			message.MsgRequestRestoreSession,
+			cmd.sessionKey,
+		)
+		if err != nil {
+			return err
+		}
+		clt.printer.message(reply)
+
+	case cmdCloseSession:
+		reply, err := clt.sessionRequest(message.MsgRequestCloseSession, nil)
+		if err != nil {
+			return err
+		}
+		if reply.MsgType == message.MsgReplyBinary {
+			clt.printer.printf("session closed")
+			break
+		}
+		clt.printer.message(reply)
+
+	case cmdHelp:
+		clt.printer.printf("%s", usage)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/cmd/internal/wwrclient"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// readNoFile is a readFileFunc failing the test
+func readNoFile(t *testing.T) readFileFunc {
+	return func(path string) ([]byte, error) {
+		t.Fatalf("unexpected read of %q", path)
+		return nil, nil
+	}
+}
+
+// TestParseCommand tests parsing interactive commands
+func TestParseCommand(t *testing.T) {
+	cmd, err := parseCommand("request echo hello world", readNoFile(t))
+	require.NoError(t, err)
+	require.Equal(t, cmdRequest, cmd.kind)
+	require.Equal(t, []byte("echo"), cmd.name)
+	require.Equal(t, pld.Utf8, cmd.payload.Encoding)
+	require.Equal(t, []byte("hello world"), cmd.payload.Data)
+
+	cmd, err = parseCommand("signal - utf16 hi", readNoFile(t))
+	require.NoError(t, err)
+	require.Equal(t, cmdSignal, cmd.kind)
+	require.Nil(t, cmd.name)
+	require.Equal(t, pld.Utf16, cmd.payload.Encoding)
+	require.Equal(t, []byte{'h', 0, 'i', 0}, cmd.payload.Data)
+
+	cmd, err = parseCommand(
+		"request upload binary @data.bin",
+		func(path string) ([]byte, error) {
+			require.Equal(t, "data.bin", path)
+			return []byte{0, 1, 2}, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, pld.Binary, cmd.payload.Encoding)
+	require.Equal(t, []byte{0, 1, 2}, cmd.payload.Data)
+
+	cmd, err = parseCommand("restore abc", readNoFile(t))
+	require.NoError(t, err)
+	require.Equal(t, cmdRestore, cmd.kind)
+	require.Equal(t, []byte("abc"), cmd.sessionKey)
+
+	cmd, err = parseCommand("close-session", readNoFile(t))
+	require.NoError(t, err)
+	require.Equal(t, cmdCloseSession, cmd.kind)
+}
+
+// TestParseCommandInvalid tests parsing invalid commands
+func TestParseCommandInvalid(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"unknown",
+		"request",
+		"restore",
+		"restore a b",
+		"quit now",
+	} {
+		_, err := parseCommand(line, readNoFile(t))
+		require.Error(t, err, line)
+	}
+
+	_, err := parseCommand(
+		"signal s binary @missing",
+		func(string) ([]byte, error) { return nil, errors.New("missing") },
+	)
+	require.Error(t, err)
+}
+
+// newTransport creates a memchan loopback transport
+func newTransport(t *testing.T, hello bool) wwr.ClientTransport {
+	trans, err := wwrclient.NewTransport(
+		"memchan",
+		wwrclient.TransportOptions{ClientHello: hello, EchoSignals: true},
+	)
+	require.NoError(t, err)
+	return trans
+}
+
+// TestRunOneShot tests executing single commands given by the options
+func TestRunOneShot(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, Run(newTransport(t, false), Options{
+		Timeout:     time.Second,
+		Request:     "echo",
+		Encoding:    "utf8",
+		PayloadFile: "-",
+	}, strings.NewReader("from stdin"), out))
+	require.Regexp(t, `^connected: protocol version 2\.\d+, read timeout .+, `+
+		`message buffer size \d+ bytes, sub-protocol ""\n`+
+		`reply \(utf8\) from stdin\n$`, out.String())
+
+	out.Reset()
+	require.NoError(t, Run(newTransport(t, true), Options{
+		Timeout:  time.Second,
+		Hello:    true,
+		Request:  "echo",
+		Encoding: "utf16",
+		Payload:  "hi \U0001F600",
+	}, strings.NewReader(""), out))
+	require.Contains(t, out.String(), "reply (utf16) hi \U0001F600\n")
+}
+
+// TestRunOneShotInvalid tests rejecting invalid options
+func TestRunOneShotInvalid(t *testing.T) {
+	err := Run(newTransport(t, false), Options{
+		Timeout:  time.Second,
+		Signal:   "s",
+		Encoding: "latin1",
+	}, strings.NewReader(""), &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+// TestRunInteractive tests executing commands read from stdin
+func TestRunInteractive(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, Run(newTransport(t, false), Options{
+		Timeout: time.Second,
+	}, strings.NewReader(strings.Join([]string{
+		"request echo binary AB",
+		"bogus",
+		"request login",
+		"close-session",
+		"restore inexistent",
+		"quit",
+		"request never-sent",
+	}, "\n")), out))
+
+	output := out.String()
+	require.Contains(t, output, "reply (binary, 2 bytes)\n00000000  41 42")
+	require.Contains(t, output, `error: unknown command "bogus"`)
+	require.Contains(t, output, "session created: (utf8) ")
+	require.Contains(t, output, "session closed")
+	require.Contains(t, output, "error reply: session not found")
+	require.NotContains(t, output, "never-sent")
+}
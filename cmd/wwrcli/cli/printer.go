@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// printer prints messages in readable form. It's safe for concurrent use
+// since incoming server signals are printed by the read loop
+type printer struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+// printf prints a formatted line
+func (p *printer) printf(format string, args ...interface{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	fmt.Fprintf(p.out, format+"\n", args...)
+}
+
+// serverConfiguration prints the server configuration
+func (p *printer) serverConfiguration(conf message.ServerConfiguration) {
+	p.printf(
+		"connected: protocol version %d.%d, read timeout %s, "+
+			"message buffer size %d bytes, sub-protocol %q",
+		conf.MajorProtocolVersion,
+		conf.MinorProtocolVersion,
+		conf.ReadTimeout,
+		conf.MessageBufferSize,
+		string(conf.SubProtocolName),
+	)
+}
+
+// formatPayload formats the given payload in readable form. Binary payloads
+// are hex dumped while UTF16 payloads are converted to UTF8
+func formatPayload(payload pld.Payload) string {
+	if len(payload.Data) < 1 {
+		return "(" + payload.Encoding.String() + ", empty)"
+	}
+	switch payload.Encoding {
+	case pld.Utf8:
+		return "(utf8) " + string(payload.Data)
+	case pld.Utf16:
+		text, err := payload.Utf8()
+		if err != nil {
+			return fmt.Sprintf("(utf16, malformed: %s)\n%s",
+				err, hex.Dump(payload.Data))
+		}
+		return "(utf16) " + string(text)
+	}
+	return fmt.Sprintf("(binary, %d bytes)\n%s",
+		len(payload.Data), hex.Dump(payload.Data))
+}
+
+// message prints the given incoming message
+func (p *printer) message(msg *message.Message) {
+	switch msg.MsgType {
+	case message.MsgSignalBinary, message.MsgSignalUtf8, message.MsgSignalUtf16:
+		p.printf("signal %q %s", msg.MsgName, formatPayload(msg.MsgPayload))
+	case message.MsgNotifySessionCreated:
+		p.printf("session created: %s", formatPayload(pld.Payload{
+			Encoding: pld.Utf8,
+			Data:     msg.MsgPayload.Data,
+		}))
+	case message.MsgNotifySessionClosed:
+		p.printf("session closed (reason %d)", msg.SessionClosureReason)
+	case message.MsgReplyBinary, message.MsgReplyUtf8, message.MsgReplyUtf16:
+		p.printf("reply %s", formatPayload(msg.MsgPayload))
+	case message.MsgReplyError:
+		p.printf(
+			"error reply %s: %s",
+			string(msg.MsgName),
+			string(msg.MsgPayload.Data),
+		)
+	case message.MsgReplyShutdown:
+		p.printf("error reply: server is shutting down")
+	case message.MsgReplyInternalError:
+		p.printf("error reply: internal server error")
+	case message.MsgReplySessionNotFound:
+		p.printf("error reply: session not found")
+	case message.MsgReplyMaxSessConnsReached:
+		p.printf("error reply: maximum concurrent session connections reached")
+	case message.MsgReplySessionsDisabled:
+		p.printf("error reply: sessions are disabled")
+	default:
+		p.printf("message of type %d", msg.MsgType)
+	}
+}
@@ -0,0 +1,106 @@
+// Command wwrcli is an interactive command-line client for exploring the
+// webwire protocol. It connects to an in-process loopback server over the
+// memchan transport, which echoes requests and signals and creates a session
+// on "login" requests, prints the server configuration and all incoming
+// server signals and session notifications and allows sending requests and
+// signals with binary, UTF8 or UTF16 encoded payloads as well as restoring
+// and closing sessions.
+//
+// Usage:
+//
+//	wwrcli
+//
+// starts an interactive session reading commands from stdin (see "help").
+// A single command can be executed non-interactively:
+//
+//	wwrcli -request echo -encoding utf16 -payload hi
+//	wwrcli -signal event -payload-file event.bin
+//
+// wwrcli doesn't ship any network transport and thus can't connect to
+// remote servers. The client itself is implemented by the cmd/wwrcli/cli
+// package, which runs over any webwire.ClientTransport and can be embedded
+// into a program providing the transport of the server to debug
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/qbeon/webwire-go/cmd/internal/wwrclient"
+	"github.com/qbeon/webwire-go/cmd/wwrcli/cli"
+)
+
+func main() {
+	var transport string
+	var opts cli.Options
+	flag.StringVar(
+		&transport,
+		"transport",
+		"memchan",
+		fmt.Sprintf(
+			"loopback transport implementation %v",
+			wwrclient.TransportNames(),
+		),
+	)
+	flag.DurationVar(
+		&opts.Timeout,
+		"timeout",
+		10*time.Second,
+		"dial and request timeout",
+	)
+	flag.BoolVar(
+		&opts.Hello,
+		"hello",
+		false,
+		"send a client hello when connecting",
+	)
+	flag.StringVar(&opts.Request, "request", "", "send a request of this name")
+	flag.StringVar(&opts.Signal, "signal", "", "send a signal of this name")
+	flag.StringVar(&opts.Restore, "restore", "", "restore the session of this key")
+	flag.BoolVar(&opts.CloseSession, "close-session", false, "close the session")
+	flag.StringVar(
+		&opts.Encoding,
+		"encoding",
+		"utf8",
+		"payload encoding (binary, utf8 or utf16)",
+	)
+	flag.StringVar(&opts.Payload, "payload", "", "payload text")
+	flag.StringVar(
+		&opts.PayloadFile,
+		"payload-file",
+		"",
+		"read the payload from this file (- for stdin)",
+	)
+	flag.DurationVar(
+		&opts.Listen,
+		"listen",
+		0,
+		"keep printing incoming signals for this duration after the command",
+	)
+	flag.Parse()
+
+	if err := run(transport, opts, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "wwrcli: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run creates the transport of the given name and runs the client
+func run(
+	transport string,
+	opts cli.Options,
+	stdin io.Reader,
+	stdout io.Writer,
+) error {
+	trans, err := wwrclient.NewTransport(transport, wwrclient.TransportOptions{
+		ClientHello: opts.Hello,
+		EchoSignals: true,
+	})
+	if err != nil {
+		return err
+	}
+	return cli.Run(trans, opts, stdin, stdout)
+}
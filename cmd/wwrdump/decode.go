@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// decoder decodes capture records into human-readable output
+type decoder struct {
+	out        io.Writer
+	bufferSize uint32
+	preview    int
+}
+
+// newDecoder creates a new decoder decoding compressed messages of up to the
+// given decompressed size and previewing up to the given number
+// of payload bytes
+func newDecoder(out io.Writer, bufferSize uint32, preview int) *decoder {
+	return &decoder{
+		out:        out,
+		bufferSize: bufferSize,
+		preview:    preview,
+	}
+}
+
+// hasIdentifier returns true if messages of the given type
+// carry a message identifier
+func hasIdentifier(msgType byte) bool {
+	switch msgType {
+	case message.MsgReplyError,
+		message.MsgReplyShutdown,
+		message.MsgReplyInternalError,
+		message.MsgReplySessionNotFound,
+		message.MsgReplyMaxSessConnsReached,
+		message.MsgReplySessionsDisabled,
+		message.MsgRequestCloseSession,
+		message.MsgRequestRestoreSession,
+		message.MsgRequestBinary,
+		message.MsgRequestUtf8,
+		message.MsgRequestUtf16,
+		message.MsgReplyBinary,
+		message.MsgReplyUtf8,
+		message.MsgReplyUtf16:
+		return true
+	}
+	return false
+}
+
+// previewPayload formats a preview of the given payload. Text payloads are
+// quoted, UTF16 payloads converted to UTF8, binary payloads hex encoded
+func (dec *decoder) previewPayload(payload pld.Payload) string {
+	data := payload.Data
+	truncated := ""
+	if dec.preview >= 0 && len(data) > dec.preview {
+		data = data[:dec.preview]
+		if payload.Encoding == pld.Utf16 {
+			data = data[:len(data)/2*2]
+		}
+		truncated = "..."
+	}
+
+	switch payload.Encoding {
+	case pld.Utf8:
+		return strconv.Quote(string(data)) + truncated
+	case pld.Utf16:
+		truncatedPayload := pld.Payload{Encoding: pld.Utf16, Data: data}
+		text, err := truncatedPayload.Utf8()
+		if err == nil {
+			return strconv.Quote(string(text)) + truncated
+		}
+	}
+	return hex.EncodeToString(data) + truncated
+}
+
+// printf prints a formatted detail line
+func (dec *decoder) printf(format string, args ...interface{}) {
+	fmt.Fprintf(dec.out, "    "+format+"\n", args...)
+}
+
+// decode decodes and prints the given record
+func (dec *decoder) decode(rec capture.Record) {
+	// Compressed messages are decompressed into the message buffer
+	bufferSize := uint32(len(rec.Data))
+	if len(rec.Data) > 0 && rec.Data[0] == message.MsgCompressed {
+		bufferSize = dec.bufferSize
+	}
+	msg := message.NewMessage(bufferSize)
+
+	envelopes := ""
+	typeParsed, err := msg.ReadBytes(rec.Data)
+	if typeParsed && msg.Compressed {
+		envelopes += ", compressed"
+	}
+	if typeParsed && len(msg.Headers) > 0 {
+		envelopes += fmt.Sprintf(", %d headers", len(msg.Headers))
+	}
+
+	msgType := "empty"
+	if typeParsed {
+		msgType = message.TypeName(msg.MsgType)
+	} else if len(rec.Data) > 0 {
+		msgType = message.TypeName(rec.Data[0])
+	}
+	fmt.Fprintf(
+		dec.out,
+		"%s conn %d %s %s (%d bytes%s)\n",
+		rec.Time.UTC().Format(time.RFC3339Nano),
+		rec.ConnectionID,
+		rec.Direction,
+		msgType,
+		len(rec.Data),
+		envelopes,
+	)
+
+	if err != nil || !typeParsed {
+		if err == nil {
+			err = fmt.Errorf("unknown message type")
+		}
+		dec.printf("malformed: %s", err)
+		dec.printf("raw %s", dec.previewPayload(pld.Payload{Data: rec.Data}))
+		return
+	}
+
+	dec.details(msg)
+}
+
+// details prints the details of the given parsed message
+func (dec *decoder) details(msg *message.Message) {
+	for _, header := range msg.Headers {
+		dec.printf("header %s: %q", header.Key, header.Value)
+	}
+
+	switch msg.MsgType {
+	case message.MsgAcceptConf:
+		conf := msg.ServerConfiguration
+		dec.printf(
+			"protocol %d.%d, read timeout %s, buffer size %d, "+
+				"sub-protocol %q, session codec %d, capabilities %d",
+			conf.MajorProtocolVersion,
+			conf.MinorProtocolVersion,
+			conf.ReadTimeout,
+			conf.MessageBufferSize,
+			conf.SubProtocolName,
+			conf.SessionCodec,
+			conf.Capabilities,
+		)
+		return
+	case message.MsgClientHello:
+		hello := msg.ClientHello
+		dec.printf(
+			"protocol %d.%d-%d, capabilities %d, client %q, auth %d bytes",
+			hello.MajorProtocolVersion,
+			hello.MinMinorProtocolVersion,
+			hello.MaxMinorProtocolVersion,
+			hello.Capabilities,
+			hello.ClientName,
+			len(hello.AuthBlob),
+		)
+		return
+	case message.MsgNotifySessionClosed:
+		dec.printf("reason %d", msg.SessionClosureReason)
+		return
+	case message.MsgReplyError:
+		dec.printf("id %x", msg.MsgIdentifierBytes)
+		dec.printf("code %q", msg.MsgName)
+		dec.printf("message %q", msg.MsgPayload.Data)
+		return
+	}
+
+	if hasIdentifier(msg.MsgType) {
+		dec.printf("id %x", msg.MsgIdentifierBytes)
+	}
+	if len(msg.MsgName) > 0 {
+		dec.printf("name %q", msg.MsgName)
+	}
+	if len(msg.MsgPayload.Data) > 0 {
+		dec.printf(
+			"payload %s %d bytes: %s",
+			msg.MsgPayload.Encoding,
+			len(msg.MsgPayload.Data),
+			dec.previewPayload(msg.MsgPayload),
+		)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// recordWriter is an io.WriteCloser writing the message to a capture on Close
+type recordWriter struct {
+	bytes.Buffer
+	t       *testing.T
+	capture *capture.Writer
+	dir     capture.Direction
+	connID  uint64
+}
+
+// Close implements the io.Closer interface
+func (wr *recordWriter) Close() error {
+	return wr.capture.WriteRecord(capture.Record{
+		Time:         time.Unix(1500000000, 0),
+		Direction:    wr.dir,
+		ConnectionID: wr.connID,
+		Data:         append([]byte(nil), wr.Bytes()...),
+	})
+}
+
+// newTestCapture creates a capture of a sample session
+func newTestCapture(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+	record := func(dir capture.Direction, connID uint64) *recordWriter {
+		return &recordWriter{t: t, capture: writer, dir: dir, connID: connID}
+	}
+	ident := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	conf, err := message.NewAcceptConfMessage(message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: 0,
+		ReadTimeout:          time.Minute,
+		MessageBufferSize:    8192,
+	})
+	require.NoError(t, err)
+	wr := record(capture.ServerToClient, 1)
+	wr.Write(conf)
+	require.NoError(t, wr.Close())
+
+	wr = record(capture.ClientToServer, 1)
+	require.NoError(t, message.WriteMsgHeaders(wr, []message.Header{
+		{Key: []byte("content-type"), Value: []byte("text/plain")},
+	}))
+	require.NoError(t, message.WriteMsgRequest(
+		wr,
+		ident,
+		[]byte("echo"),
+		pld.Utf16,
+		[]byte{'h', 0, 'i', 0},
+		true,
+	))
+
+	require.NoError(t, message.WriteMsgSpecialRequestReply(
+		record(capture.ServerToClient, 1),
+		message.MsgReplyMaxSessConnsReached,
+		ident,
+	))
+
+	require.NoError(t, message.WriteMsgReplyError(
+		record(capture.ServerToClient, 2),
+		ident,
+		[]byte("NOT_FOUND"),
+		[]byte("no such thing"),
+		true,
+	))
+
+	require.NoError(t, message.WriteMsgSignal(
+		message.NewCompressingWriter(record(capture.ServerToClient, 2), 1),
+		[]byte("event"),
+		pld.Binary,
+		bytes.Repeat([]byte{0xAB}, 512),
+		true,
+	))
+
+	wr = record(capture.ClientToServer, 2)
+	wr.Write([]byte{message.MsgRequestUtf8, 1})
+	require.NoError(t, wr.Close())
+
+	return buf.Bytes()
+}
+
+// TestDump tests decoding a capture
+func TestDump(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, dump(
+		options{preview: 8, bufferSize: 1024},
+		bytes.NewReader(newTestCapture(t)),
+		out,
+	))
+
+	require.Equal(t, strings.Join([]string{
+		"2017-07-14T02:40:00Z conn 1 server->client MsgAcceptConf (11 bytes)",
+		`    protocol 2.0, read timeout 1m0s, buffer size 8192, ` +
+			`sub-protocol "", session codec 0, capabilities 0`,
+		"2017-07-14T02:40:00Z conn 1 client->server MsgRequestUtf16 " +
+			"(45 bytes, 1 headers)",
+		`    header content-type: "text/plain"`,
+		"    id 0102030405060708",
+		`    name "echo"`,
+		`    payload utf16 4 bytes: "hi"`,
+		"2017-07-14T02:40:00Z conn 1 server->client " +
+			"MsgReplyMaxSessConnsReached (9 bytes)",
+		"    id 0102030405060708",
+		"2017-07-14T02:40:00Z conn 2 server->client MsgReplyError (32 bytes)",
+		"    id 0102030405060708",
+		`    code "NOT_FOUND"`,
+		`    message "no such thing"`,
+		"2017-07-14T02:40:00Z conn 2 server->client MsgSignalBinary " +
+			"(16 bytes, compressed)",
+		`    name "event"`,
+		"    payload binary 512 bytes: abababababababab...",
+		"2017-07-14T02:40:00Z conn 2 client->server MsgRequestUtf8 (2 bytes)",
+		"    malformed: invalid request message, too short",
+		"    raw 8001",
+		"",
+	}, "\n"), out.String())
+}
+
+// TestDumpConnection tests filtering the messages of a single connection
+func TestDumpConnection(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, dump(
+		options{connectionID: 2, preview: -1, bufferSize: 1024},
+		bytes.NewReader(newTestCapture(t)),
+		out,
+	))
+	require.NotContains(t, out.String(), "conn 1")
+	require.Contains(t, out.String(), "conn 2")
+	require.Contains(t, out.String(), strings.Repeat("ab", 512)+"\n")
+}
+
+// TestDumpInvalid tests rejecting invalid captures
+func TestDumpInvalid(t *testing.T) {
+	err := dump(
+		options{preview: 8, bufferSize: 1024},
+		strings.NewReader("not a capture"),
+		&bytes.Buffer{},
+	)
+	require.Error(t, err)
+
+	data := newTestCapture(t)
+	err = dump(
+		options{preview: 8, bufferSize: 1024},
+		bytes.NewReader(data[:len(data)-1]),
+		&bytes.Buffer{},
+	)
+	require.Error(t, err)
+}
+
+// TestRunMissingFile tests returning the error of opening a missing capture
+func TestRunMissingFile(t *testing.T) {
+	require.Error(t, run(options{preview: 8, bufferSize: 1024}, "inexistent"))
+}
@@ -0,0 +1,97 @@
+// Command wwrdump decodes webwire traffic captures (see package capture) into
+// human-readable output showing the message types, identifiers, names,
+// payload encodings and payload previews of all captured messages.
+//
+// Usage:
+//
+//	wwrdump [-conn id] [-preview bytes] capture.wwrcap
+//
+// The capture is read from stdin if no file is given
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qbeon/webwire-go/capture"
+)
+
+// options represents the command-line options
+type options struct {
+	connectionID uint64
+	preview      int
+	bufferSize   uint
+}
+
+func main() {
+	var opts options
+	flag.Uint64Var(
+		&opts.connectionID,
+		"conn",
+		0,
+		"only dump the messages of this connection (0 = all)",
+	)
+	flag.IntVar(
+		&opts.preview,
+		"preview",
+		64,
+		"maximum number of payload bytes to preview (-1 = unlimited)",
+	)
+	flag.UintVar(
+		&opts.bufferSize,
+		"buffer-size",
+		1024*1024,
+		"maximum size of decompressed messages",
+	)
+	flag.Parse()
+
+	if err := run(opts, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "wwrdump: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dumps the capture read from the given file or stdin if no file is given
+func run(opts options, path string) error {
+	src := io.Reader(os.Stdin)
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		src = file
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	if err := dump(opts, bufio.NewReader(src), out); err != nil {
+		out.Flush()
+		return err
+	}
+	return out.Flush()
+}
+
+// dump decodes all records of the given capture
+func dump(opts options, src io.Reader, out io.Writer) error {
+	reader, err := capture.NewReader(src)
+	if err != nil {
+		return err
+	}
+	dec := newDecoder(out, uint32(opts.bufferSize), opts.preview)
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if opts.connectionID != 0 && rec.ConnectionID != opts.connectionID {
+			continue
+		}
+		dec.decode(rec)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// generator represents the state of the code generator
+type generator struct {
+	schema  *Schema
+	structs map[string]bool
+	buf     bytes.Buffer
+}
+
+// printf writes formatted code
+func (gen *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&gen.buf, format, args...)
+}
+
+// Generate generates the Go source code for the given schema
+func Generate(schema *Schema, source string) ([]byte, error) {
+	gen := &generator{
+		schema:  schema,
+		structs: make(map[string]bool, len(schema.Structs)),
+	}
+	for _, strct := range schema.Structs {
+		gen.structs[strct.Name] = true
+	}
+
+	gen.printf("// Code generated by wwrgen from %s. DO NOT EDIT.\n\n", source)
+	gen.printf("package %s\n\n", schema.Package)
+	gen.imports()
+	gen.messageNames()
+	gen.errorCodes()
+	for _, strct := range schema.Structs {
+		if err := gen.structDecl(strct); err != nil {
+			return nil, err
+		}
+	}
+	for _, ns := range schema.Namespaces {
+		gen.server(ns)
+		gen.client(ns)
+	}
+
+	formatted, err := format.Source(gen.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't format generated code: %s", err)
+	}
+	return formatted, nil
+}
+
+// usesTime returns true if any struct field is of the time type
+func (gen *generator) usesTime() bool {
+	for _, strct := range gen.schema.Structs {
+		for _, field := range strct.Fields {
+			typ, _ := goType(field.Type, gen.structs)
+			if strings.Contains(typ, "time.Time") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imports writes the import declarations
+func (gen *generator) imports() {
+	gen.printf("import (\n")
+	gen.printf("\t\"context\"\n")
+	if gen.usesTime() {
+		gen.printf("\t\"time\"\n")
+	}
+	gen.printf("\n")
+	gen.printf("\twwr \"github.com/qbeon/webwire-go\"\n")
+	gen.printf("\t\"github.com/qbeon/webwire-go/codec\"\n")
+	gen.printf("\t\"github.com/qbeon/webwire-go/router\"\n")
+	gen.printf(")\n\n")
+}
+
+// prefix returns the Go identifier prefix of the given namespace
+func prefix(ns *Namespace) string {
+	return exported(ns.Name)
+}
+
+// messageNames writes the message name constants
+func (gen *generator) messageNames() {
+	gen.printf("// Names of the requests and signals\n")
+	gen.printf("const (\n")
+	for _, ns := range gen.schema.Namespaces {
+		for _, req := range ns.Requests {
+			gen.printf(
+				"\tRequest%s%s = %q\n",
+				prefix(ns),
+				exported(req.Name),
+				messageName(ns, req.Name),
+			)
+		}
+		for _, sig := range ns.Signals {
+			gen.printf(
+				"\tSignal%s%s = %q\n",
+				prefix(ns),
+				exported(sig.Name),
+				messageName(ns, sig.Name),
+			)
+		}
+	}
+	gen.printf(")\n\n")
+}
+
+// errorCodes writes the error code constants
+func (gen *generator) errorCodes() {
+	var codes []string
+	declared := make(map[string]bool)
+	for _, ns := range gen.schema.Namespaces {
+		for _, req := range ns.Requests {
+			for _, code := range req.ErrorCodes {
+				if !declared[code] {
+					declared[code] = true
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	if len(codes) < 1 {
+		return
+	}
+
+	gen.printf("// Error codes of the request errors\n")
+	gen.printf("const (\n")
+	for _, code := range codes {
+		gen.printf(
+			"\tErrorCode%s = %q\n",
+			exported(strings.ToLower(code)),
+			code,
+		)
+	}
+	gen.printf(")\n\n")
+}
+
+// structDecl writes a struct declaration
+func (gen *generator) structDecl(strct *Struct) error {
+	gen.printf("// %s is a payload type declared in the schema\n", strct.Name)
+	gen.printf("type %s struct {\n", strct.Name)
+	for _, field := range strct.Fields {
+		typ, err := goType(field.Type, gen.structs)
+		if err != nil {
+			return err
+		}
+		gen.printf(
+			"\t%s %s `json:\"%s\"`\n",
+			exported(field.Name),
+			typ,
+			field.Name,
+		)
+	}
+	gen.printf("}\n\n")
+	return nil
+}
+
+// server writes the server handler interface of the given namespace
+// and the function registering it on a router
+func (gen *generator) server(ns *Namespace) {
+	name := prefix(ns) + "Server"
+	gen.printf(
+		"// %s defines the handlers of the %s\n",
+		name,
+		describe(ns),
+	)
+	gen.printf("type %s interface {\n", name)
+	for _, req := range ns.Requests {
+		gen.printf("\t// %s handles %q requests", exported(req.Name),
+			messageName(ns, req.Name))
+		if len(req.ErrorCodes) > 0 {
+			gen.printf(".\n\t// It may fail with the error codes: %s",
+				strings.Join(req.ErrorCodes, ", "))
+		}
+		gen.printf("\n")
+		gen.printf(
+			"\t%s(ctx context.Context, conn wwr.Connection, req *%s) "+
+				"(*%s, error)\n\n",
+			exported(req.Name),
+			exported(req.Input),
+			exported(req.Output),
+		)
+	}
+	for _, sig := range ns.Signals {
+		gen.printf("\t// %s handles %q signals\n", exported(sig.Name),
+			messageName(ns, sig.Name))
+		gen.printf(
+			"\t%s(ctx context.Context, conn wwr.Connection, sig *%s)\n\n",
+			exported(sig.Name),
+			exported(sig.Input),
+		)
+	}
+	gen.printf("}\n\n")
+
+	gen.printf(
+		"// Register%s registers the handlers of the given server\n"+
+			"// on the given router decoding the payloads using the given codecs\n",
+		name,
+	)
+	gen.printf(
+		"func Register%s(r *router.Router, codecs *codec.Registry, "+
+			"srv %s) {\n",
+		name,
+		name,
+	)
+	for _, req := range ns.Requests {
+		gen.printf(
+			"\tr.Request(Request%s%s, codec.Request(codecs, srv.%s))\n",
+			prefix(ns),
+			exported(req.Name),
+			exported(req.Name),
+		)
+	}
+	for _, sig := range ns.Signals {
+		gen.printf(
+			"\tr.Signal(Signal%s%s, codec.Signal(codecs, srv.%s))\n",
+			prefix(ns),
+			exported(sig.Name),
+			exported(sig.Name),
+		)
+	}
+	gen.printf("}\n\n")
+}
+
+// client writes the typed client stub of the given namespace
+func (gen *generator) client(ns *Namespace) {
+	name := prefix(ns) + "Client"
+	gen.printf(
+		"// %s is a typed client stub of the %s\n",
+		name,
+		describe(ns),
+	)
+	gen.printf("type %s struct {\n", name)
+	gen.printf("\tclient codec.Client\n")
+	gen.printf("\tcodec  codec.Codec\n")
+	gen.printf("}\n\n")
+
+	gen.printf(
+		"// New%s creates a new client stub sending messages through\n"+
+			"// the given client encoded with the given codec\n",
+		name,
+	)
+	gen.printf(
+		"func New%s(client codec.Client, cdc codec.Codec) *%s {\n",
+		name,
+		name,
+	)
+	gen.printf("\treturn &%s{client: client, codec: cdc}\n", name)
+	gen.printf("}\n\n")
+
+	for _, req := range ns.Requests {
+		gen.printf("// %s sends a %q request", exported(req.Name),
+			messageName(ns, req.Name))
+		if len(req.ErrorCodes) > 0 {
+			gen.printf(".\n// It may fail with the error codes: %s",
+				strings.Join(req.ErrorCodes, ", "))
+		}
+		gen.printf("\n")
+		gen.printf(
+			"func (c *%s) %s(ctx context.Context, req *%s) (*%s, error) {\n",
+			name,
+			exported(req.Name),
+			exported(req.Input),
+			exported(req.Output),
+		)
+		gen.printf("\treply := &%s{}\n", exported(req.Output))
+		gen.printf(
+			"\tif err := codec.Call(ctx, c.client, c.codec, "+
+				"Request%s%s, req, reply); err != nil {\n",
+			prefix(ns),
+			exported(req.Name),
+		)
+		gen.printf("\t\treturn nil, err\n")
+		gen.printf("\t}\n")
+		gen.printf("\treturn reply, nil\n")
+		gen.printf("}\n\n")
+	}
+	for _, sig := range ns.Signals {
+		gen.printf("// %s sends a %q signal\n", exported(sig.Name),
+			messageName(ns, sig.Name))
+		gen.printf(
+			"func (c *%s) %s(ctx context.Context, sig *%s) error {\n",
+			name,
+			exported(sig.Name),
+			exported(sig.Input),
+		)
+		gen.printf(
+			"\treturn codec.Notify(ctx, c.client, c.codec, Signal%s%s, sig)\n",
+			prefix(ns),
+			exported(sig.Name),
+		)
+		gen.printf("}\n\n")
+	}
+}
+
+// describe describes the given namespace in documentation comments
+func describe(ns *Namespace) string {
+	if ns.Name == "" {
+		return "top-level requests and signals"
+	}
+	return ns.Name + " namespace"
+}
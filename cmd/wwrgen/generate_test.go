@@ -0,0 +1,60 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate tests whether the code generated for the sample IDL file
+// type-checks and declares the expected identifiers
+func TestGenerate(t *testing.T) {
+	file, err := os.Open("testdata/users.wwr")
+	require.NoError(t, err)
+	defer file.Close()
+
+	schema, err := Parse(file)
+	require.NoError(t, err)
+
+	code, err := Generate(schema, "users.wwr")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "users.gen.go", code, 0)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("users", fset, []*ast.File{parsed}, nil)
+	require.NoError(t, err)
+
+	for _, name := range []string{
+		"RequestPing",
+		"RequestUsersGet",
+		"SignalUsersTyping",
+		"ErrorCodeNotFound",
+		"User",
+		"Server",
+		"RegisterServer",
+		"NewClient",
+		"UsersServer",
+		"RegisterUsersServer",
+		"UsersClient",
+		"NewUsersClient",
+	} {
+		require.NotNil(t, pkg.Scope().Lookup(name), name)
+	}
+
+	// Verify the generated field types
+	user := pkg.Scope().Lookup("User").Type().Underlying().(*types.Struct)
+	typing := pkg.Scope().Lookup("Typing").Type().Underlying().(*types.Struct)
+	require.Equal(t, "UserID", typing.Field(0).Name())
+	require.Equal(t, "time.Time", user.Field(3).Type().String())
+	require.Equal(t, "*[]byte", user.Field(4).Type().String())
+	require.Equal(t, `json:"created_at"`, user.Tag(3))
+}
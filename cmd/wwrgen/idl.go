@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Schema represents a parsed IDL file
+type Schema struct {
+	Package    string
+	Structs    []*Struct
+	Namespaces []*Namespace
+}
+
+// Struct represents a payload struct declaration
+type Struct struct {
+	Name   string
+	Fields []Field
+}
+
+// Field represents a payload struct field
+type Field struct {
+	Name string
+	Type string
+}
+
+// Namespace represents a group of requests and signals.
+// The top-level namespace has an empty name
+type Namespace struct {
+	Name     string
+	Requests []Request
+	Signals  []Signal
+}
+
+// Request represents a request declaration
+type Request struct {
+	Name       string
+	Input      string
+	Output     string
+	ErrorCodes []string
+}
+
+// Signal represents a signal declaration
+type Signal struct {
+	Name  string
+	Input string
+}
+
+var (
+	identPattern     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	msgNamePattern   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	errorCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	requestPattern   = regexp.MustCompile(
+		`^request\s+(\S+)\s*\(\s*(\S+)\s*\)\s*(\S+)(?:\s+errors\s+(.+))?$`,
+	)
+	signalPattern = regexp.MustCompile(`^signal\s+(\S+)\s*\(\s*(\S+)\s*\)$`)
+)
+
+// builtinTypes maps the builtin IDL types to Go types
+var builtinTypes = map[string]string{
+	"bool":    "bool",
+	"string":  "string",
+	"bytes":   "[]byte",
+	"time":    "time.Time",
+	"int":     "int",
+	"int8":    "int8",
+	"int16":   "int16",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint":    "uint",
+	"uint8":   "uint8",
+	"uint16":  "uint16",
+	"uint32":  "uint32",
+	"uint64":  "uint64",
+	"float32": "float32",
+	"float64": "float64",
+}
+
+// idlParser represents the state of the IDL parser
+type idlParser struct {
+	schema    *Schema
+	line      int
+	namespace *Namespace
+	strct     *Struct
+	names     map[string]bool
+}
+
+// errorf returns an error referring to the current line
+func (p *idlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// Parse parses the IDL read from the given reader
+func Parse(reader io.Reader) (*Schema, error) {
+	p := &idlParser{
+		schema: &Schema{},
+		names:  make(map[string]bool),
+	}
+	topLevel := &Namespace{}
+	p.schema.Namespaces = append(p.schema.Namespaces, topLevel)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		p.line++
+		line := scanner.Text()
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := p.parseLine(line, topLevel); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.strct != nil {
+		return nil, fmt.Errorf("unterminated struct %s", p.strct.Name)
+	}
+	if p.namespace != nil {
+		return nil, fmt.Errorf("unterminated namespace %s", p.namespace.Name)
+	}
+	if p.schema.Package == "" {
+		return nil, fmt.Errorf("missing package declaration")
+	}
+
+	// Drop the top-level namespace if it's empty
+	if len(topLevel.Requests) < 1 && len(topLevel.Signals) < 1 {
+		p.schema.Namespaces = p.schema.Namespaces[1:]
+	}
+
+	return p.schema, p.resolveTypes()
+}
+
+// parseLine parses a single non-empty line
+func (p *idlParser) parseLine(line string, topLevel *Namespace) error {
+	if line == "}" {
+		switch {
+		case p.strct != nil:
+			p.strct = nil
+		case p.namespace != nil:
+			p.namespace = nil
+		default:
+			return p.errorf("unexpected }")
+		}
+		return nil
+	}
+
+	if p.strct != nil {
+		return p.parseField(line)
+	}
+
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "package":
+		if len(fields) != 2 || !identPattern.MatchString(fields[1]) {
+			return p.errorf("invalid package declaration")
+		}
+		if p.schema.Package != "" {
+			return p.errorf("duplicate package declaration")
+		}
+		p.schema.Package = fields[1]
+
+	case "namespace":
+		if len(fields) != 3 || fields[2] != "{" ||
+			!identPattern.MatchString(fields[1]) {
+			return p.errorf("invalid namespace declaration")
+		}
+		if p.namespace != nil {
+			return p.errorf("nested namespaces aren't supported")
+		}
+		for _, ns := range p.schema.Namespaces {
+			if ns.Name == fields[1] {
+				return p.errorf("duplicate namespace %s", fields[1])
+			}
+		}
+		p.namespace = &Namespace{Name: fields[1]}
+		p.schema.Namespaces = append(p.schema.Namespaces, p.namespace)
+
+	case "struct":
+		if len(fields) != 3 || fields[2] != "{" ||
+			!identPattern.MatchString(fields[1]) {
+			return p.errorf("invalid struct declaration")
+		}
+		if p.namespace != nil {
+			return p.errorf("structs must be declared at the top level")
+		}
+		for _, strct := range p.schema.Structs {
+			if strct.Name == exported(fields[1]) {
+				return p.errorf("duplicate struct %s", fields[1])
+			}
+		}
+		p.strct = &Struct{Name: exported(fields[1])}
+		p.schema.Structs = append(p.schema.Structs, p.strct)
+
+	case "request":
+		return p.parseRequest(line, topLevel)
+
+	case "signal":
+		return p.parseSignal(line, topLevel)
+
+	default:
+		return p.errorf("unexpected %q", fields[0])
+	}
+	return nil
+}
+
+// currentNamespace returns the namespace declarations are added to
+func (p *idlParser) currentNamespace(topLevel *Namespace) *Namespace {
+	if p.namespace != nil {
+		return p.namespace
+	}
+	return topLevel
+}
+
+// registerName ensures message names are unique
+func (p *idlParser) registerName(kind string, ns *Namespace, name string) error {
+	if !msgNamePattern.MatchString(name) {
+		return p.errorf("invalid %s name %q", kind, name)
+	}
+	fullName := kind + " " + messageName(ns, name)
+	if p.names[fullName] {
+		return p.errorf("duplicate %s %s", kind, messageName(ns, name))
+	}
+	p.names[fullName] = true
+	return nil
+}
+
+// parseRequest parses a request declaration
+func (p *idlParser) parseRequest(line string, topLevel *Namespace) error {
+	match := requestPattern.FindStringSubmatch(line)
+	if match == nil {
+		return p.errorf("invalid request declaration")
+	}
+	ns := p.currentNamespace(topLevel)
+	if err := p.registerName("request", ns, match[1]); err != nil {
+		return err
+	}
+
+	req := Request{
+		Name:   match[1],
+		Input:  match[2],
+		Output: match[3],
+	}
+	if match[4] != "" {
+		for _, code := range strings.Split(match[4], ",") {
+			code = strings.TrimSpace(code)
+			if !errorCodePattern.MatchString(code) {
+				return p.errorf("invalid error code %q", code)
+			}
+			req.ErrorCodes = append(req.ErrorCodes, code)
+		}
+	}
+	ns.Requests = append(ns.Requests, req)
+	return nil
+}
+
+// parseSignal parses a signal declaration
+func (p *idlParser) parseSignal(line string, topLevel *Namespace) error {
+	match := signalPattern.FindStringSubmatch(line)
+	if match == nil {
+		return p.errorf("invalid signal declaration")
+	}
+	ns := p.currentNamespace(topLevel)
+	if err := p.registerName("signal", ns, match[1]); err != nil {
+		return err
+	}
+	ns.Signals = append(ns.Signals, Signal{Name: match[1], Input: match[2]})
+	return nil
+}
+
+// parseField parses a struct field declaration
+func (p *idlParser) parseField(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || !identPattern.MatchString(fields[0]) {
+		return p.errorf("invalid field declaration")
+	}
+	for _, field := range p.strct.Fields {
+		if field.Name == fields[0] {
+			return p.errorf("duplicate field %s", fields[0])
+		}
+	}
+	p.strct.Fields = append(p.strct.Fields, Field{
+		Name: fields[0],
+		Type: fields[1],
+	})
+	return nil
+}
+
+// resolveTypes verifies all referenced types are declared
+func (p *idlParser) resolveTypes() error {
+	structs := make(map[string]bool, len(p.schema.Structs))
+	for _, strct := range p.schema.Structs {
+		structs[strct.Name] = true
+	}
+
+	for _, strct := range p.schema.Structs {
+		for _, field := range strct.Fields {
+			if _, err := goType(field.Type, structs); err != nil {
+				return fmt.Errorf(
+					"struct %s field %s: %s",
+					strct.Name,
+					field.Name,
+					err,
+				)
+			}
+		}
+	}
+
+	for _, ns := range p.schema.Namespaces {
+		for _, req := range ns.Requests {
+			for _, typ := range []string{req.Input, req.Output} {
+				if !structs[exported(typ)] {
+					return fmt.Errorf(
+						"request %s: undeclared struct %s",
+						messageName(ns, req.Name),
+						typ,
+					)
+				}
+			}
+		}
+		for _, sig := range ns.Signals {
+			if !structs[exported(sig.Input)] {
+				return fmt.Errorf(
+					"signal %s: undeclared struct %s",
+					messageName(ns, sig.Name),
+					sig.Input,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// goType translates the given IDL type to a Go type
+func goType(typ string, structs map[string]bool) (string, error) {
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		elem, err := goType(typ[2:], structs)
+		return "[]" + elem, err
+	case strings.HasPrefix(typ, "map[string]"):
+		elem, err := goType(typ[len("map[string]"):], structs)
+		return "map[string]" + elem, err
+	case strings.HasPrefix(typ, "*"):
+		elem, err := goType(typ[1:], structs)
+		return "*" + elem, err
+	}
+	if builtin, isBuiltin := builtinTypes[typ]; isBuiltin {
+		return builtin, nil
+	}
+	if structs[exported(typ)] {
+		return exported(typ), nil
+	}
+	return "", fmt.Errorf("unknown type %q", typ)
+}
+
+// messageName returns the name of a message on the wire
+func messageName(ns *Namespace, name string) string {
+	if ns.Name == "" {
+		return name
+	}
+	return ns.Name + "." + name
+}
+
+// initialisms are the words exported in upper case
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "uri": true, "url": true, "uuid": true,
+}
+
+// exported returns the exported Go identifier for the given IDL name
+// converting snake-case and kebab-case names to camel-case
+func exported(name string) string {
+	var ident strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(char rune) bool {
+		return char == '_' || char == '-'
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			ident.WriteString(strings.ToUpper(word))
+			continue
+		}
+		ident.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return ident.String()
+}
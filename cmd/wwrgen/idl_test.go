@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests parsing the sample IDL file
+func TestParse(t *testing.T) {
+	file, err := os.Open("testdata/users.wwr")
+	require.NoError(t, err)
+	defer file.Close()
+
+	schema, err := Parse(file)
+	require.NoError(t, err)
+	require.Equal(t, "users", schema.Package)
+	require.Len(t, schema.Structs, 4)
+	require.Equal(t, "User", schema.Structs[1].Name)
+	require.Equal(t, Field{Name: "tags", Type: "[]string"}, schema.Structs[1].Fields[2])
+
+	require.Len(t, schema.Namespaces, 2)
+	require.Equal(t, "", schema.Namespaces[0].Name)
+	require.Equal(t, []Request{{
+		Name:   "ping",
+		Input:  "Ping",
+		Output: "Ping",
+	}}, schema.Namespaces[0].Requests)
+
+	users := schema.Namespaces[1]
+	require.Equal(t, "users", users.Name)
+	require.Equal(t, Request{
+		Name:       "get",
+		Input:      "GetUser",
+		Output:     "User",
+		ErrorCodes: []string{"NOT_FOUND", "FORBIDDEN"},
+	}, users.Requests[0])
+	require.Equal(t, []Signal{{Name: "typing", Input: "Typing"}}, users.Signals)
+}
+
+// TestParseErrors tests parsing invalid IDL files
+func TestParseErrors(t *testing.T) {
+	for name, idl := range map[string]string{
+		"missing package":      "struct A {\n}",
+		"duplicate package":    "package a\npackage b",
+		"unterminated struct":  "package a\nstruct A {\nx string",
+		"unexpected brace":     "package a\n}",
+		"unknown keyword":      "package a\nmessage A",
+		"unknown field type":   "package a\nstruct A {\nx complex128\n}",
+		"undeclared input":     "package a\nrequest r(A) A",
+		"invalid error code":   "package a\nstruct A {\n}\nrequest r(A) A errors bad",
+		"duplicate request":    "package a\nstruct A {\n}\nrequest r(A) A\nrequest r(A) A",
+		"duplicate struct":     "package a\nstruct A {\n}\nstruct A {\n}",
+		"nested namespace":     "package a\nnamespace a {\nnamespace b {\n}\n}",
+		"struct in namespace":  "package a\nnamespace a {\nstruct A {\n}\n}",
+		"invalid signal":       "package a\nsignal s",
+		"duplicate field":      "package a\nstruct A {\nx string\nx string\n}",
+		"unterminated namespa": "package a\nnamespace a {",
+	} {
+		_, err := Parse(strings.NewReader(idl))
+		require.Error(t, err, name)
+	}
+}
+
+// TestExported tests the translation of IDL names to Go identifiers
+func TestExported(t *testing.T) {
+	require.Equal(t, "User", exported("user"))
+	require.Equal(t, "UserID", exported("user_id"))
+	require.Equal(t, "CreatedAt", exported("created-at"))
+	require.Equal(t, "HTTPURL", exported("http_url"))
+}
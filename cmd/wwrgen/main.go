@@ -0,0 +1,86 @@
+// Command wwrgen generates typed Go server handler interfaces and client stubs
+// from an IDL file describing the requests and signals of a webwire API.
+//
+// Usage:
+//
+//	wwrgen -in api.wwr -out api.gen.go
+//
+// IDL files consist of a package declaration, payload structs and requests
+// and signals optionally grouped into namespaces:
+//
+//	package users
+//
+//	struct GetUser {
+//		id string
+//	}
+//
+//	struct User {
+//		id   string
+//		name string
+//		tags []string
+//	}
+//
+//	namespace users {
+//		request get(GetUser) User errors NOT_FOUND, FORBIDDEN
+//		signal  typing(GetUser)
+//	}
+//
+// Supported field types are bool, string, bytes, time, int, int8 to int64,
+// uint, uint8 to uint64, float32, float64, declared structs and slices ([]T),
+// maps (map[string]T) and pointers (*T) of them.
+// The messages of a namespace are named "namespace.name" on the wire
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	in := flag.String("in", "", "path to the IDL file")
+	out := flag.String(
+		"out",
+		"",
+		"path to the generated Go file (printed to stdout if empty)",
+	)
+	flag.Parse()
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "missing IDL file (-in)")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*in, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "wwrgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the Go code for the given IDL file
+func run(in, out string) error {
+	idl, err := ioutil.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	schema, err := Parse(bytes.NewReader(idl))
+	if err != nil {
+		return fmt.Errorf("%s: %s", in, err)
+	}
+
+	code, err := Generate(schema, filepath.Base(in))
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(out, code, 0644)
+}
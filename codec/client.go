@@ -0,0 +1,92 @@
+package codec
+
+import (
+	"context"
+	"fmt"
+
+	wwr "github.com/qbeon/webwire-go"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// Client defines the subset of the webwire client API
+// typed client stubs are built on
+type Client interface {
+	// Request sends a request and awaits the reply
+	Request(
+		ctx context.Context,
+		name []byte,
+		payload wwr.Payload,
+	) (wwr.Reply, error)
+
+	// Signal sends a signal
+	Signal(ctx context.Context, name []byte, payload wwr.Payload) error
+}
+
+// Call encodes the given request using the given codec, sends it and
+// decodes the reply into the value pointed to by reply.
+// Replies without a payload leave the reply value untouched, replies of
+// another content type than the one of the given codec are rejected
+func Call(
+	ctx context.Context,
+	client Client,
+	codec Codec,
+	name string,
+	request interface{},
+	reply interface{},
+) error {
+	data, err := codec.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("couldn't encode request: %s", err)
+	}
+
+	rep, err := client.Request(ctx, []byte(name), wwr.Payload{
+		Encoding: codec.Encoding(),
+		Data:     data,
+		Headers:  contentTypeHeaders(codec),
+	})
+	if err != nil {
+		return err
+	}
+	defer rep.Close()
+
+	if contentType := rep.Header(ContentTypeHeader); contentType != nil &&
+		string(contentType) != codec.ContentType() {
+		return fmt.Errorf(
+			"couldn't decode reply: unexpected content type: %s",
+			contentType,
+		)
+	}
+
+	data = rep.Payload()
+	if rep.PayloadEncoding() == pld.Utf16 {
+		if data, err = rep.PayloadUtf8(); err != nil {
+			return fmt.Errorf("couldn't decode reply: %s", err)
+		}
+	}
+	if len(data) < 1 {
+		return nil
+	}
+	if err := codec.Unmarshal(data, reply); err != nil {
+		return fmt.Errorf("couldn't decode reply: %s", err)
+	}
+	return nil
+}
+
+// Notify encodes the given signal using the given codec and sends it
+func Notify(
+	ctx context.Context,
+	client Client,
+	codec Codec,
+	name string,
+	signal interface{},
+) error {
+	data, err := codec.Marshal(signal)
+	if err != nil {
+		return fmt.Errorf("couldn't encode signal: %s", err)
+	}
+	return client.Signal(ctx, []byte(name), wwr.Payload{
+		Encoding: codec.Encoding(),
+		Data:     data,
+		Headers:  contentTypeHeaders(codec),
+	})
+}
@@ -0,0 +1,200 @@
+package codec_test
+
+import (
+	"context"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/codec"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testReply implements the webwire.Reply interface
+type testReply struct {
+	payload wwr.Payload
+	closed  bool
+}
+
+// PayloadEncoding implements the webwire.Reply interface
+func (rp *testReply) PayloadEncoding() wwr.PayloadEncoding {
+	return rp.payload.Encoding
+}
+
+// Payload implements the webwire.Reply interface
+func (rp *testReply) Payload() []byte { return rp.payload.Data }
+
+// PayloadUtf8 implements the webwire.Reply interface
+func (rp *testReply) PayloadUtf8() ([]byte, error) {
+	payload := pld.Payload{
+		Encoding: rp.payload.Encoding,
+		Data:     rp.payload.Data,
+	}
+	return payload.Utf8()
+}
+
+// Header implements the webwire.Reply interface
+func (rp *testReply) Header(key string) []byte {
+	for _, header := range rp.payload.Headers {
+		if string(header.Key) == key {
+			return header.Value
+		}
+	}
+	return nil
+}
+
+// Close implements the webwire.Reply interface
+func (rp *testReply) Close() { rp.closed = true }
+
+// testClient implements the codec.Client interface echoing requests
+type testClient struct {
+	name    string
+	payload wwr.Payload
+	reply   *testReply
+}
+
+// Request implements the codec.Client interface
+func (clt *testClient) Request(
+	_ context.Context,
+	name []byte,
+	payload wwr.Payload,
+) (wwr.Reply, error) {
+	clt.name = string(name)
+	clt.payload = payload
+	clt.reply = &testReply{payload: payload}
+	return clt.reply, nil
+}
+
+// handlerClient implements the codec.Client interface
+// passing requests to a request handler
+type handlerClient struct {
+	t       *testing.T
+	handler codec.RequestHandler
+}
+
+// Request implements the codec.Client interface
+func (clt *handlerClient) Request(
+	ctx context.Context,
+	_ []byte,
+	payload wwr.Payload,
+) (wwr.Reply, error) {
+	reply, err := clt.handler(ctx, nil, newMessage(
+		clt.t,
+		payload.Headers,
+		payload.Encoding,
+		payload.Data,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return &testReply{payload: reply}, nil
+}
+
+// Signal implements the codec.Client interface
+func (clt *handlerClient) Signal(context.Context, []byte, wwr.Payload) error {
+	return nil
+}
+
+// contentType returns the content type headers of the given codec
+func contentType(cdc codec.Codec) []wwr.Header {
+	return []wwr.Header{{
+		Key:   []byte(codec.ContentTypeHeader),
+		Value: []byte(cdc.ContentType()),
+	}}
+}
+
+// Signal implements the codec.Client interface
+func (clt *testClient) Signal(
+	_ context.Context,
+	name []byte,
+	payload wwr.Payload,
+) error {
+	clt.name = string(name)
+	clt.payload = payload
+	return nil
+}
+
+// TestCall tests sending typed requests
+func TestCall(t *testing.T) {
+	clt := &testClient{}
+	var reply sample
+	require.NoError(t, codec.Call(
+		context.Background(),
+		clt,
+		codec.JSON{},
+		"r",
+		sample{Name: "a", Count: 1},
+		&reply,
+	))
+	require.Equal(t, "r", clt.name)
+	require.Equal(t, pld.Utf8, clt.payload.Encoding)
+	require.Equal(t, contentType(codec.JSON{}), clt.payload.Headers)
+	require.Equal(t, sample{Name: "a", Count: 1}, reply)
+	require.True(t, clt.reply.closed)
+
+	// Expect replies of another content type to be rejected
+	require.Error(t, codec.Call(
+		context.Background(),
+		&handlerClient{t: t, handler: func(
+			context.Context,
+			wwr.Connection,
+			wwr.Message,
+		) (wwr.Payload, error) {
+			return wwr.Payload{
+				Encoding: pld.Utf8,
+				Data:     []byte(`{"name":"a"}`),
+				Headers:  contentType(codec.Compact{}),
+			}, nil
+		}},
+		codec.JSON{},
+		"r",
+		sample{Name: "a"},
+		&reply,
+	))
+}
+
+// TestCallHandler tests sending typed requests to a typed request handler
+// selecting the codec by the content type of the request
+func TestCallHandler(t *testing.T) {
+	handler := codec.Request(
+		codec.NewDefaultRegistry(),
+		func(
+			_ context.Context,
+			_ wwr.Connection,
+			req *protoSample,
+		) (*protoSample, error) {
+			req.Value += "-reply"
+			return req, nil
+		},
+	)
+
+	reply := &protoSample{}
+	require.NoError(t, codec.Call(
+		context.Background(),
+		&handlerClient{t: t, handler: handler},
+		codec.Proto{},
+		"r",
+		&protoSample{Value: "request"},
+		reply,
+	))
+	require.Equal(t, "request-reply", reply.Value)
+}
+
+// TestNotify tests sending typed signals
+func TestNotify(t *testing.T) {
+	clt := &testClient{}
+	require.NoError(t, codec.Notify(
+		context.Background(),
+		clt,
+		codec.Compact{},
+		"s",
+		sample{Name: "a"},
+	))
+	require.Equal(t, "s", clt.name)
+	require.Equal(t, pld.Binary, clt.payload.Encoding)
+	require.Equal(t, contentType(codec.Compact{}), clt.payload.Headers)
+
+	var decoded sample
+	require.NoError(t, codec.Compact{}.Unmarshal(clt.payload.Data, &decoded))
+	require.Equal(t, sample{Name: "a"}, decoded)
+}
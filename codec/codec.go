@@ -0,0 +1,43 @@
+// Package codec provides typed payload codecs and helpers wrapping typed
+// request and signal handlers into regular webwire handlers decoding the
+// message payloads and encoding the replies.
+//
+// The codec of a message is selected by the ContentTypeHeader message header
+// if the client provided one, otherwise by the payload encoding of the message.
+// Messages sent by Call and Notify as well as the replies of Request handlers
+// always specify their content type
+package codec
+
+import (
+	wwr "github.com/qbeon/webwire-go"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// ContentTypeHeader is the name of the message header identifying the codec
+// the message payload is encoded with (see Codec.ContentType)
+const ContentTypeHeader = "content-type"
+
+// Codec defines the interface of a typed payload codec
+type Codec interface {
+	// ContentType returns the content type identifying the codec
+	// in the ContentTypeHeader message header
+	ContentType() string
+
+	// Encoding returns the payload encoding of encoded payloads.
+	// Codecs of the Utf8 encoding also decode UTF16 encoded payloads
+	Encoding() pld.Encoding
+
+	// Marshal encodes the given value
+	Marshal(value interface{}) ([]byte, error)
+
+	// Unmarshal decodes the given data into the value pointed to by target
+	Unmarshal(data []byte, target interface{}) error
+}
+
+// contentTypeHeaders returns the headers identifying the given codec
+func contentTypeHeaders(codec Codec) []wwr.Header {
+	return []wwr.Header{{
+		Key:   []byte(ContentTypeHeader),
+		Value: []byte(codec.ContentType()),
+	}}
+}
@@ -0,0 +1,143 @@
+package codec_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qbeon/webwire-go/codec"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// sample represents a sample typed payload
+type sample struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+	Blob  []byte `json:"blob"`
+}
+
+// protoSample represents a sample Protocol Buffers style message
+type protoSample struct {
+	Value string
+}
+
+// Marshal implements the codec.ProtoMessage interface
+func (msg *protoSample) Marshal() ([]byte, error) {
+	return []byte(msg.Value), nil
+}
+
+// Unmarshal implements the codec.ProtoMessage interface
+func (msg *protoSample) Unmarshal(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("empty")
+	}
+	msg.Value = string(data)
+	return nil
+}
+
+// newMessage creates a parsed request message with the given headers
+func newMessage(
+	t *testing.T,
+	headers []message.Header,
+	encoding pld.Encoding,
+	payload []byte,
+) *message.Message {
+	writer := &testWriter{}
+	if len(headers) > 0 {
+		require.NoError(t, message.WriteMsgHeaders(writer, headers))
+	}
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{0, 0, 0, 0, 0, 0, 0, 1},
+		[]byte("r"),
+		encoding,
+		payload,
+		true,
+	))
+
+	msg := message.NewMessage(uint32(len(writer.buf)))
+	_, err := msg.ReadBytes(writer.buf)
+	require.NoError(t, err)
+	return msg
+}
+
+// testWriter is an io.WriteCloser implementation for testing purposes
+type testWriter struct {
+	buf []byte
+}
+
+// Write implements the io.WriteCloser interface
+func (tw *testWriter) Write(p []byte) (int, error) {
+	tw.buf = append(tw.buf, p...)
+	return len(p), nil
+}
+
+// Close implements the io.WriteCloser interface
+func (tw *testWriter) Close() error {
+	return nil
+}
+
+// TestCodecsRoundTrip tests encoding and decoding values
+// using the builtin codecs
+func TestCodecsRoundTrip(t *testing.T) {
+	original := sample{Name: "sample", Count: 42, Blob: []byte{0, 1, 255}}
+
+	for _, cdc := range []codec.Codec{codec.JSON{}, codec.Compact{}} {
+		encoded, err := cdc.Marshal(original)
+		require.NoError(t, err, cdc.ContentType())
+
+		var decoded sample
+		require.NoError(t, cdc.Unmarshal(encoded, &decoded), cdc.ContentType())
+		require.Equal(t, original, decoded, cdc.ContentType())
+	}
+
+	encoded, err := codec.Proto{}.Marshal(&protoSample{Value: "proto"})
+	require.NoError(t, err)
+	var decoded protoSample
+	require.NoError(t, codec.Proto{}.Unmarshal(encoded, &decoded))
+	require.Equal(t, "proto", decoded.Value)
+
+	// Expect values not implementing ProtoMessage to be rejected
+	_, err = codec.Proto{}.Marshal(original)
+	require.Error(t, err)
+	require.Error(t, codec.Proto{}.Unmarshal(encoded, &original))
+}
+
+// TestRegistryForMessage tests the selection of codecs
+func TestRegistryForMessage(t *testing.T) {
+	reg := codec.NewDefaultRegistry()
+
+	// Select by payload encoding
+	cdc, err := reg.ForMessage(newMessage(t, nil, pld.Utf8, []byte("{}")))
+	require.NoError(t, err)
+	require.Equal(t, codec.JSON{}, cdc)
+
+	cdc, err = reg.ForMessage(newMessage(t, nil, pld.Utf16, []byte("{\x00}\x00")))
+	require.NoError(t, err)
+	require.Equal(t, codec.JSON{}, cdc)
+
+	cdc, err = reg.ForMessage(newMessage(t, nil, pld.Binary, []byte{0}))
+	require.NoError(t, err)
+	require.Equal(t, codec.Compact{}, cdc)
+
+	// Select by content type header
+	cdc, err = reg.ForMessage(newMessage(t, []message.Header{{
+		Key:   []byte(codec.ContentTypeHeader),
+		Value: []byte(codec.Proto{}.ContentType()),
+	}}, pld.Binary, []byte{0}))
+	require.NoError(t, err)
+	require.Equal(t, codec.Proto{}, cdc)
+
+	_, err = reg.ForMessage(newMessage(t, []message.Header{{
+		Key:   []byte(codec.ContentTypeHeader),
+		Value: []byte("application/unknown"),
+	}}, pld.Binary, []byte{0}))
+	require.Error(t, err)
+
+	// Expect an error for encodings without a registered codec
+	_, err = codec.NewRegistry(codec.JSON{}).ForMessage(
+		newMessage(t, nil, pld.Binary, []byte{0}),
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package codec
+
+import (
+	"github.com/qbeon/webwire-go/compact"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// Compact implements the Codec interface encoding values using the compact,
+// self-describing binary encoding (see package compact)
+type Compact struct{}
+
+// ContentType implements the Codec interface
+func (Compact) ContentType() string {
+	return "application/x-webwire-compact"
+}
+
+// Encoding implements the Codec interface
+func (Compact) Encoding() pld.Encoding {
+	return pld.Binary
+}
+
+// Marshal implements the Codec interface
+func (Compact) Marshal(value interface{}) ([]byte, error) {
+	return compact.Marshal(value)
+}
+
+// Unmarshal implements the Codec interface
+func (Compact) Unmarshal(data []byte, target interface{}) error {
+	return compact.Unmarshal(data, target)
+}
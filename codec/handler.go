@@ -0,0 +1,173 @@
+package codec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	wwr "github.com/qbeon/webwire-go"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// DecodeErrorCode defines the error code of the error replies to requests
+// carrying payloads that couldn't be decoded
+const DecodeErrorCode = "DECODE_ERROR"
+
+// RequestHandler represents a webwire request handler
+// (see webwire.ServerImplementation.OnRequest)
+type RequestHandler func(
+	ctx context.Context,
+	connection wwr.Connection,
+	message wwr.Message,
+) (wwr.Payload, error)
+
+// SignalHandler represents a webwire signal handler
+// (see webwire.ServerImplementation.OnSignal)
+type SignalHandler func(
+	ctx context.Context,
+	connection wwr.Connection,
+	message wwr.Message,
+)
+
+var (
+	typeContext    = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeConnection = reflect.TypeOf((*wwr.Connection)(nil)).Elem()
+	typeError      = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// handlerFunc represents a reflected typed handler function
+type handlerFunc struct {
+	fn      reflect.Value
+	reqType reflect.Type
+}
+
+// reflectHandler reflects the given typed handler function panicking if its
+// signature doesn't start with (context.Context, webwire.Connection, Req)
+// or doesn't return the given number of values
+func reflectHandler(handler interface{}, numOut int) handlerFunc {
+	fn := reflect.ValueOf(handler)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func ||
+		typ.NumIn() != 3 ||
+		typ.In(0) != typeContext ||
+		typ.In(1) != typeConnection ||
+		typ.NumOut() != numOut ||
+		(numOut == 2 && typ.Out(1) != typeError) {
+		panic(fmt.Errorf("unsupported typed handler signature: %s", typ))
+	}
+	return handlerFunc{fn: fn, reqType: typ.In(2)}
+}
+
+// decode decodes the payload of the given message into a new value of the
+// request type. Messages without a payload are decoded to the zero value
+func (hf handlerFunc) decode(
+	codec Codec,
+	msg wwr.Message,
+) (reflect.Value, error) {
+	target := hf.reqType
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	req := reflect.New(target)
+
+	data := msg.Payload()
+	if codec.Encoding() == pld.Utf8 {
+		utf8, err := msg.PayloadUtf8()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		data = utf8
+	}
+	if len(data) > 0 {
+		if err := codec.Unmarshal(data, req.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if hf.reqType.Kind() == reflect.Ptr {
+		return req, nil
+	}
+	return req.Elem(), nil
+}
+
+// Request wraps the given typed request handler of signature
+// func(context.Context, webwire.Connection, Req) (Resp, error)
+// into a webwire request handler. The request payload is decoded into a new
+// Req using the codec selected by the given registry and the returned Resp
+// is encoded into the reply using the same codec identified by the
+// ContentTypeHeader reply header. Payloads that can't be
+// decoded are replied to with a DecodeErrorCode request error.
+// Panics if the handler signature is unsupported
+func Request(codecs *Registry, handler interface{}) RequestHandler {
+	hf := reflectHandler(handler, 2)
+	return func(
+		ctx context.Context,
+		connection wwr.Connection,
+		message wwr.Message,
+	) (wwr.Payload, error) {
+		codec, err := codecs.ForMessage(message)
+		if err != nil {
+			return wwr.Payload{}, err
+		}
+
+		req, err := hf.decode(codec, message)
+		if err != nil {
+			return wwr.Payload{}, wwr.ErrRequest{
+				Code:    DecodeErrorCode,
+				Message: err.Error(),
+			}
+		}
+
+		out := hf.fn.Call([]reflect.Value{
+			reflect.ValueOf(&ctx).Elem(),
+			reflect.ValueOf(&connection).Elem(),
+			req,
+		})
+		if errVal := out[1].Interface(); errVal != nil {
+			return wwr.Payload{}, errVal.(error)
+		}
+
+		data, err := codec.Marshal(out[0].Interface())
+		if err != nil {
+			return wwr.Payload{}, fmt.Errorf("couldn't encode reply: %s", err)
+		}
+		return wwr.Payload{
+			Encoding: codec.Encoding(),
+			Data:     data,
+			Headers:  contentTypeHeaders(codec),
+		}, nil
+	}
+}
+
+// Signal wraps the given typed signal handler of signature
+// func(context.Context, webwire.Connection, Req) into a webwire signal
+// handler. The signal payload is decoded into a new Req using the codec
+// selected by the given registry. Signals that can't be decoded are dropped
+// and logged to the logger of the registry.
+// Panics if the handler signature is unsupported
+func Signal(codecs *Registry, handler interface{}) SignalHandler {
+	hf := reflectHandler(handler, 0)
+	return func(
+		ctx context.Context,
+		connection wwr.Connection,
+		message wwr.Message,
+	) {
+		codec, err := codecs.ForMessage(message)
+		if err != nil {
+			codecs.logDroppedSignal(connection, message, err)
+			return
+		}
+
+		req, err := hf.decode(codec, message)
+		if err != nil {
+			codecs.logDroppedSignal(connection, message, err)
+			return
+		}
+
+		hf.fn.Call([]reflect.Value{
+			reflect.ValueOf(&ctx).Elem(),
+			reflect.ValueOf(&connection).Elem(),
+			req,
+		})
+	}
+}
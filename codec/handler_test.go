@@ -0,0 +1,149 @@
+package codec_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/codec"
+	"github.com/qbeon/webwire-go/compact"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequest tests wrapping typed request handlers
+func TestRequest(t *testing.T) {
+	handler := codec.Request(
+		codec.NewDefaultRegistry(),
+		func(
+			_ context.Context,
+			_ wwr.Connection,
+			req *sample,
+		) (*sample, error) {
+			if req.Name == "fail" {
+				return nil, errors.New("failure")
+			}
+			req.Count++
+			return req, nil
+		},
+	)
+
+	// JSON encoded request
+	reply, err := handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Utf8, []byte(`{"name":"a","count":1}`)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, pld.Utf8, reply.Encoding)
+	require.Equal(t, contentType(codec.JSON{}), reply.Headers)
+	var decoded sample
+	require.NoError(t, json.Unmarshal(reply.Data, &decoded))
+	require.Equal(t, sample{Name: "a", Count: 2}, decoded)
+
+	// Compact encoded request
+	encoded, err := compact.Marshal(sample{Name: "b", Count: 2})
+	require.NoError(t, err)
+	reply, err = handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Binary, encoded),
+	)
+	require.NoError(t, err)
+	require.Equal(t, pld.Binary, reply.Encoding)
+	require.Equal(t, contentType(codec.Compact{}), reply.Headers)
+	require.NoError(t, compact.Unmarshal(reply.Data, &decoded))
+	require.Equal(t, sample{Name: "b", Count: 3}, decoded)
+
+	// Expect decode errors to be turned into request errors
+	_, err = handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Utf8, []byte(`{"name":`)),
+	)
+	require.IsType(t, wwr.ErrRequest{}, err)
+	require.Equal(t, codec.DecodeErrorCode, err.(wwr.ErrRequest).Code)
+
+	// Expect handler errors to be passed through
+	_, err = handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Utf8, []byte(`{"name":"fail"}`)),
+	)
+	require.EqualError(t, err, "failure")
+}
+
+// TestSignal tests wrapping typed signal handlers
+func TestSignal(t *testing.T) {
+	var received []string
+	logger := &testLogger{}
+	reg := codec.NewDefaultRegistry()
+	reg.Logger = logger
+	handler := codec.Signal(
+		reg,
+		func(_ context.Context, _ wwr.Connection, req sample) {
+			received = append(received, req.Name)
+		},
+	)
+
+	handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Utf8, []byte(`{"name":"a"}`)),
+	)
+
+	// Expect signals that can't be decoded to be dropped and logged
+	handler(
+		context.Background(),
+		nil,
+		newMessage(t, nil, pld.Utf8, []byte(`{"name":`)),
+	)
+	handler(
+		context.Background(),
+		nil,
+		newMessage(t, []message.Header{{
+			Key:   []byte(codec.ContentTypeHeader),
+			Value: []byte("unsupported"),
+		}}, pld.Utf8, []byte(`{"name":"b"}`)),
+	)
+
+	require.Equal(t, []string{"a"}, received)
+	require.Equal(t, 2, logger.entries)
+}
+
+// testLogger implements the webwire.Logger interface
+// counting the log entries
+type testLogger struct {
+	entries int
+}
+
+// Log implements the webwire.Logger interface
+func (lg *testLogger) Log(wwr.LogLevel, string, ...wwr.LogField) {
+	lg.entries++
+}
+
+// TestUnsupportedHandlerSignature tests wrapping handlers
+// of unsupported signatures
+func TestUnsupportedHandlerSignature(t *testing.T) {
+	reg := codec.NewDefaultRegistry()
+	require.Panics(t, func() {
+		codec.Request(reg, func(context.Context, *sample) (*sample, error) {
+			return nil, nil
+		})
+	})
+	require.Panics(t, func() {
+		codec.Request(reg, func(
+			context.Context,
+			wwr.Connection,
+			*sample,
+		) *sample {
+			return nil
+		})
+	})
+	require.Panics(t, func() {
+		codec.Signal(reg, "not a function")
+	})
+}
@@ -0,0 +1,30 @@
+package codec
+
+import (
+	"encoding/json"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// JSON implements the Codec interface encoding values as JSON
+type JSON struct{}
+
+// ContentType implements the Codec interface
+func (JSON) ContentType() string {
+	return "application/json"
+}
+
+// Encoding implements the Codec interface
+func (JSON) Encoding() pld.Encoding {
+	return pld.Utf8
+}
+
+// Marshal implements the Codec interface
+func (JSON) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal implements the Codec interface
+func (JSON) Unmarshal(data []byte, target interface{}) error {
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"fmt"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// ProtoMessage defines the interface of Protocol Buffers style messages
+// marshaling themselves, such as messages generated by gogo/protobuf
+type ProtoMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+// Proto implements the Codec interface for values implementing
+// the ProtoMessage interface
+type Proto struct{}
+
+// ContentType implements the Codec interface
+func (Proto) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// Encoding implements the Codec interface
+func (Proto) Encoding() pld.Encoding {
+	return pld.Binary
+}
+
+// Marshal implements the Codec interface
+func (Proto) Marshal(value interface{}) ([]byte, error) {
+	msg, ok := value.(ProtoMessage)
+	if !ok {
+		return nil, fmt.Errorf("%T doesn't implement codec.ProtoMessage", value)
+	}
+	return msg.Marshal()
+}
+
+// Unmarshal implements the Codec interface
+func (Proto) Unmarshal(data []byte, target interface{}) error {
+	msg, ok := target.(ProtoMessage)
+	if !ok {
+		return fmt.Errorf("%T doesn't implement codec.ProtoMessage", target)
+	}
+	return msg.Unmarshal(data)
+}
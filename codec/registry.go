@@ -0,0 +1,106 @@
+package codec
+
+import (
+	"log"
+	"os"
+
+	wwr "github.com/qbeon/webwire-go"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// UnsupportedContentTypeCode defines the error code of the error replies to
+// requests of a content type no codec is registered for
+const UnsupportedContentTypeCode = "UNSUPPORTED_CONTENT_TYPE"
+
+// Registry selects the codecs of incoming messages
+type Registry struct {
+	// Logger logs the signals dropped because they couldn't be decoded.
+	// By default they're logged to std-out
+	Logger wwr.Logger
+
+	byContentType map[string]Codec
+	byEncoding    map[pld.Encoding]Codec
+}
+
+// NewRegistry creates a new codec registry. The first given codec of each
+// payload encoding is used for messages not specifying a content type
+func NewRegistry(codecs ...Codec) *Registry {
+	reg := &Registry{
+		Logger: wwr.NewStdLogger(
+			log.New(os.Stdout, "WWR_WARN: ", log.Ldate|log.Ltime|log.Lshortfile),
+			nil,
+		),
+		byContentType: make(map[string]Codec, len(codecs)),
+		byEncoding:    make(map[pld.Encoding]Codec, 2),
+	}
+	for _, codec := range codecs {
+		reg.byContentType[codec.ContentType()] = codec
+		if _, exists := reg.byEncoding[codec.Encoding()]; !exists {
+			reg.byEncoding[codec.Encoding()] = codec
+		}
+	}
+	return reg
+}
+
+// NewDefaultRegistry creates a new codec registry using JSON for UTF8
+// and UTF16 encoded payloads and Compact for binary payloads.
+// Proto is only used for messages specifying its content type
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(JSON{}, Compact{}, Proto{})
+}
+
+// Lookup returns the codec registered for the given content type
+func (reg *Registry) Lookup(contentType string) (Codec, bool) {
+	codec, exists := reg.byContentType[contentType]
+	return codec, exists
+}
+
+// ForMessage selects the codec of the given message by its content type
+// header or its payload encoding if no content type is specified.
+// Returns an UnsupportedContentTypeCode request error if there's no suitable
+// codec registered
+func (reg *Registry) ForMessage(msg wwr.Message) (Codec, error) {
+	if contentType := msg.Header(ContentTypeHeader); contentType != nil {
+		if codec, exists := reg.byContentType[string(contentType)]; exists {
+			return codec, nil
+		}
+		return nil, wwr.ErrRequest{
+			Code:    UnsupportedContentTypeCode,
+			Message: "unsupported content type: " + string(contentType),
+		}
+	}
+
+	encoding := msg.PayloadEncoding()
+	if encoding == pld.Utf16 {
+		encoding = pld.Utf8
+	}
+	if codec, exists := reg.byEncoding[encoding]; exists {
+		return codec, nil
+	}
+	return nil, wwr.ErrRequest{
+		Code:    UnsupportedContentTypeCode,
+		Message: "unsupported payload encoding: " + encoding.String(),
+	}
+}
+
+// logDroppedSignal logs a signal dropped due to the given error
+func (reg *Registry) logDroppedSignal(
+	connection wwr.Connection,
+	msg wwr.Message,
+	err error,
+) {
+	if reg.Logger == nil {
+		return
+	}
+	fields := []wwr.LogField{
+		{Key: "signal", Value: string(msg.Name())},
+		{Key: wwr.LogKeyError, Value: err},
+	}
+	if connection != nil {
+		fields = append(fields, wwr.LogField{
+			Key:   wwr.LogKeyConnectionID,
+			Value: connection.ID(),
+		})
+	}
+	reg.Logger.Log(wwr.LogLevelWarn, "dropped undecodable signal", fields...)
+}
@@ -0,0 +1,189 @@
+package compact
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Assign converts the given decoded variant value to the type of the settable
+// target value and assigns it
+func Assign(target reflect.Value, variant interface{}) error {
+	if variant == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	value := reflect.ValueOf(variant)
+
+	if target.Kind() == reflect.Interface {
+		if !value.Type().AssignableTo(target.Type()) {
+			return typeMismatch(target, variant)
+		}
+		target.Set(value)
+		return nil
+	}
+
+	if target.Type() == timeType {
+		tm, isTime := variant.(time.Time)
+		if !isTime {
+			return typeMismatch(target, variant)
+		}
+		target.Set(reflect.ValueOf(tm))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		ptr := reflect.New(target.Type().Elem())
+		if err := Assign(ptr.Elem(), variant); err != nil {
+			return err
+		}
+		target.Set(ptr)
+
+	case reflect.Bool:
+		b, isBool := variant.(bool)
+		if !isBool {
+			return typeMismatch(target, variant)
+		}
+		target.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var i int64
+		switch v := variant.(type) {
+		case int64:
+			i = v
+		case uint64:
+			if int64(v) < 0 {
+				return overflow(target, variant)
+			}
+			i = int64(v)
+		default:
+			return typeMismatch(target, variant)
+		}
+		if target.OverflowInt(i) {
+			return overflow(target, variant)
+		}
+		target.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch v := variant.(type) {
+		case uint64:
+			u = v
+		case int64:
+			if v < 0 {
+				return overflow(target, variant)
+			}
+			u = uint64(v)
+		default:
+			return typeMismatch(target, variant)
+		}
+		if target.OverflowUint(u) {
+			return overflow(target, variant)
+		}
+		target.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch v := variant.(type) {
+		case float64:
+			f = v
+		case int64:
+			f = float64(v)
+		case uint64:
+			f = float64(v)
+		default:
+			return typeMismatch(target, variant)
+		}
+		target.SetFloat(f)
+
+	case reflect.String:
+		str, isString := variant.(string)
+		if !isString {
+			return typeMismatch(target, variant)
+		}
+		target.SetString(str)
+
+	case reflect.Slice:
+		if blob, isBytes := variant.([]byte); isBytes &&
+			target.Type().Elem().Kind() == reflect.Uint8 {
+			cpy := reflect.MakeSlice(target.Type(), len(blob), len(blob))
+			reflect.Copy(cpy, reflect.ValueOf(blob))
+			target.Set(cpy)
+			return nil
+		}
+		list, isList := variant.([]interface{})
+		if !isList {
+			return typeMismatch(target, variant)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := Assign(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+
+	case reflect.Array:
+		list, isList := variant.([]interface{})
+		if !isList || len(list) != target.Len() {
+			return typeMismatch(target, variant)
+		}
+		for i, item := range list {
+			if err := Assign(target.Index(i), item); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		mp, isMap := variant.(map[string]interface{})
+		if !isMap || target.Type().Key().Kind() != reflect.String {
+			return typeMismatch(target, variant)
+		}
+		newMap := reflect.MakeMapWithSize(target.Type(), len(mp))
+		for key, item := range mp {
+			val := reflect.New(target.Type().Elem()).Elem()
+			if err := Assign(val, item); err != nil {
+				return err
+			}
+			newMap.SetMapIndex(
+				reflect.ValueOf(key).Convert(target.Type().Key()),
+				val,
+			)
+		}
+		target.Set(newMap)
+
+	case reflect.Struct:
+		mp, isMap := variant.(map[string]interface{})
+		if !isMap {
+			return typeMismatch(target, variant)
+		}
+		for _, field := range structFields(target.Type()) {
+			item, exists := mp[field.name]
+			if !exists {
+				continue
+			}
+			if err := Assign(target.Field(field.index), item); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported type: %s", target.Type())
+	}
+	return nil
+}
+
+func typeMismatch(target reflect.Value, variant interface{}) error {
+	return fmt.Errorf(
+		"cannot assign %T to %s",
+		variant,
+		target.Type(),
+	)
+}
+
+func overflow(target reflect.Value, variant interface{}) error {
+	return fmt.Errorf("value %v overflows %s", variant, target.Type())
+}
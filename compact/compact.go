@@ -0,0 +1,55 @@
+// Package compact implements a compact, self-describing binary encoding of
+// variant values. Unlike JSON it preserves binary blobs, precise integer types
+// and timestamps.
+//
+// Every encoded value starts with a single type tag byte:
+//   - nil:    tag
+//   - bool:   tag (TagFalse or TagTrue)
+//   - int:    tag + zig-zag encoded varint
+//   - uint:   tag + varint
+//   - float:  tag + 8 byte little endian IEEE 754 binary64
+//   - string: tag + varint length + UTF8 encoded bytes
+//   - bytes:  tag + varint length + bytes
+//   - list:   tag + varint item count + items
+//   - map:    tag + varint entry count + entries (varint key length + key
+//     bytes + value)
+//   - time:   tag + zig-zag encoded varint unix nanoseconds (UTC)
+package compact
+
+const (
+	// TagNil represents a nil value
+	TagNil = byte(0)
+
+	// TagFalse represents a false boolean value
+	TagFalse = byte(1)
+
+	// TagTrue represents a true boolean value
+	TagTrue = byte(2)
+
+	// TagInt represents a signed integer decoded as int64
+	TagInt = byte(3)
+
+	// TagUint represents an unsigned integer decoded as uint64
+	TagUint = byte(4)
+
+	// TagFloat represents a floating point number decoded as float64
+	TagFloat = byte(5)
+
+	// TagString represents a UTF8 encoded string
+	TagString = byte(6)
+
+	// TagBytes represents a binary blob decoded as []byte
+	TagBytes = byte(7)
+
+	// TagList represents a list decoded as []interface{}
+	TagList = byte(8)
+
+	// TagMap represents a string-keyed map decoded as map[string]interface{}
+	TagMap = byte(9)
+
+	// TagTime represents a timestamp decoded as time.Time in UTC
+	TagTime = byte(10)
+)
+
+// maxDepth defines the maximum nesting depth of lists and maps
+const maxDepth = 64
@@ -0,0 +1,175 @@
+package compact_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/qbeon/webwire-go/compact"
+	"github.com/stretchr/testify/require"
+)
+
+type testNested struct {
+	Blob []byte `json:"blob"`
+}
+
+type testStruct struct {
+	Name    string                 `json:"name"`
+	Count   int32                  `json:"count"`
+	Big     uint64                 `json:"big"`
+	Ratio   float64                `json:"ratio"`
+	Enabled bool                   `json:"enabled"`
+	Created time.Time              `json:"created"`
+	Tags    []string               `json:"tags"`
+	Pair    [2]int                 `json:"pair"`
+	Nested  *testNested            `json:"nested"`
+	Extra   map[string]interface{} `json:"extra"`
+	Ignored string                 `json:"-"`
+}
+
+// TestRoundTripStruct tests encoding and decoding a struct
+func TestRoundTripStruct(t *testing.T) {
+	original := testStruct{
+		Name:    "sample",
+		Count:   -42,
+		Big:     math.MaxUint64,
+		Ratio:   0.5,
+		Enabled: true,
+		Created: time.Date(2018, 10, 1, 12, 30, 0, 123, time.UTC),
+		Tags:    []string{"a", "b"},
+		Pair:    [2]int{1, -1},
+		Nested:  &testNested{Blob: []byte{0, 1, 255}},
+		Extra: map[string]interface{}{
+			"str":  "value",
+			"int":  int64(-7),
+			"list": []interface{}{true, nil},
+		},
+		Ignored: "ignored",
+	}
+
+	encoded, err := compact.Marshal(original)
+	require.NoError(t, err)
+
+	var decoded testStruct
+	require.NoError(t, compact.Unmarshal(encoded, &decoded))
+
+	original.Ignored = ""
+	require.Equal(t, original, decoded)
+}
+
+// TestRoundTripVariant tests decoding into an empty interface
+func TestRoundTripVariant(t *testing.T) {
+	created := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	encoded, err := compact.Marshal(map[string]interface{}{
+		"int":     -1,
+		"uint":    uint8(1),
+		"float":   float32(1.5),
+		"bytes":   []byte("blob"),
+		"created": created,
+		"nil":     nil,
+	})
+	require.NoError(t, err)
+
+	var decoded interface{}
+	require.NoError(t, compact.Unmarshal(encoded, &decoded))
+	require.Equal(t, map[string]interface{}{
+		"int":     int64(-1),
+		"uint":    uint64(1),
+		"float":   float64(1.5),
+		"bytes":   []byte("blob"),
+		"created": created,
+		"nil":     nil,
+	}, decoded)
+}
+
+// TestMarshalDeterministic tests whether map keys are encoded in sorted order
+func TestMarshalDeterministic(t *testing.T) {
+	first, err := compact.Marshal(map[string]int{"b": 2, "a": 1, "c": 3})
+	require.NoError(t, err)
+	second, err := compact.Marshal(map[string]int{"c": 3, "a": 1, "b": 2})
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+}
+
+// TestMarshalUnsupported tests encoding unsupported types
+func TestMarshalUnsupported(t *testing.T) {
+	_, err := compact.Marshal(make(chan int))
+	require.Error(t, err)
+
+	_, err = compact.Marshal(map[int]string{1: "a"})
+	require.Error(t, err)
+}
+
+// TestUnmarshalOverflow tests decoding integers that overflow the target type
+func TestUnmarshalOverflow(t *testing.T) {
+	encoded, err := compact.Marshal(300)
+	require.NoError(t, err)
+
+	var small int8
+	require.Error(t, compact.Unmarshal(encoded, &small))
+
+	encoded, err = compact.Marshal(-1)
+	require.NoError(t, err)
+
+	var unsigned uint
+	require.Error(t, compact.Unmarshal(encoded, &unsigned))
+}
+
+// TestUnmarshalTypeMismatch tests decoding into incompatible types
+func TestUnmarshalTypeMismatch(t *testing.T) {
+	encoded, err := compact.Marshal("text")
+	require.NoError(t, err)
+
+	var number int
+	require.Error(t, compact.Unmarshal(encoded, &number))
+}
+
+// TestUnmarshalCorrupt tests decoding corrupt data
+func TestUnmarshalCorrupt(t *testing.T) {
+	var decoded interface{}
+
+	// Invalid non-pointer target
+	require.Error(t, compact.Unmarshal([]byte{compact.TagNil}, decoded))
+
+	// Empty data
+	require.Error(t, compact.Unmarshal(nil, &decoded))
+
+	// Unknown tag
+	require.Error(t, compact.Unmarshal([]byte{255}, &decoded))
+
+	// String length exceeding the data
+	require.Error(t, compact.Unmarshal(
+		[]byte{compact.TagString, 10, 'a'},
+		&decoded,
+	))
+
+	// Huge list length
+	require.Error(t, compact.Unmarshal(
+		[]byte{compact.TagList, 0xff, 0xff, 0xff, 0xff, 0x0f},
+		&decoded,
+	))
+
+	// Truncated float
+	require.Error(t, compact.Unmarshal(
+		[]byte{compact.TagFloat, 0, 0},
+		&decoded,
+	))
+
+	// Trailing data
+	require.Error(t, compact.Unmarshal(
+		[]byte{compact.TagNil, compact.TagNil},
+		&decoded,
+	))
+}
+
+// TestUnmarshalMaxDepth tests decoding excessively nested data
+func TestUnmarshalMaxDepth(t *testing.T) {
+	data := make([]byte, 0, 200)
+	for i := 0; i < 100; i++ {
+		data = append(data, compact.TagList, 1)
+	}
+	data = append(data, compact.TagNil)
+
+	var decoded interface{}
+	require.Error(t, compact.Unmarshal(data, &decoded))
+}
@@ -0,0 +1,177 @@
+package compact
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Marshal encodes the given value.
+//
+// Booleans, integers, floats, strings, byte slices, time.Time values, slices,
+// arrays, string-keyed maps and pointers are supported. Structs are encoded as
+// maps of their exported fields named after their json-style tags
+// (`json:"name"`) or field names. Fields tagged `json:"-"` are skipped
+func Marshal(value interface{}) ([]byte, error) {
+	enc := encoder{buf: make([]byte, 0, 64)}
+	if err := enc.encode(reflect.ValueOf(value), 0); err != nil {
+		return nil, err
+	}
+	return enc.buf, nil
+}
+
+// encoder represents a compact binary encoder
+type encoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (enc *encoder) writeUvarint(v uint64) {
+	n := binary.PutUvarint(enc.scratch[:], v)
+	enc.buf = append(enc.buf, enc.scratch[:n]...)
+}
+
+func (enc *encoder) writeVarint(v int64) {
+	n := binary.PutVarint(enc.scratch[:], v)
+	enc.buf = append(enc.buf, enc.scratch[:n]...)
+}
+
+func (enc *encoder) writeBytes(tag byte, data []byte) {
+	enc.buf = append(enc.buf, tag)
+	enc.writeUvarint(uint64(len(data)))
+	enc.buf = append(enc.buf, data...)
+}
+
+func (enc *encoder) encode(value reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("maximum nesting depth (%d) exceeded", maxDepth)
+	}
+
+	if !value.IsValid() {
+		enc.buf = append(enc.buf, TagNil)
+		return nil
+	}
+
+	if value.Type() == timeType {
+		enc.buf = append(enc.buf, TagTime)
+		enc.writeVarint(value.Interface().(time.Time).UnixNano())
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			enc.buf = append(enc.buf, TagNil)
+			return nil
+		}
+		return enc.encode(value.Elem(), depth)
+
+	case reflect.Bool:
+		if value.Bool() {
+			enc.buf = append(enc.buf, TagTrue)
+		} else {
+			enc.buf = append(enc.buf, TagFalse)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		enc.buf = append(enc.buf, TagInt)
+		enc.writeVarint(value.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		enc.buf = append(enc.buf, TagUint)
+		enc.writeUvarint(value.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		enc.buf = append(enc.buf, TagFloat)
+		var bits [8]byte
+		binary.LittleEndian.PutUint64(bits[:], math.Float64bits(value.Float()))
+		enc.buf = append(enc.buf, bits[:]...)
+
+	case reflect.String:
+		enc.writeBytes(TagString, []byte(value.String()))
+
+	case reflect.Slice:
+		if value.IsNil() {
+			enc.buf = append(enc.buf, TagNil)
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			enc.writeBytes(TagBytes, value.Bytes())
+			return nil
+		}
+		return enc.encodeList(value, depth)
+
+	case reflect.Array:
+		return enc.encodeList(value, depth)
+
+	case reflect.Map:
+		if value.IsNil() {
+			enc.buf = append(enc.buf, TagNil)
+			return nil
+		}
+		return enc.encodeMap(value, depth)
+
+	case reflect.Struct:
+		return enc.encodeStruct(value, depth)
+
+	default:
+		return fmt.Errorf("unsupported type: %s", value.Type())
+	}
+	return nil
+}
+
+func (enc *encoder) encodeList(value reflect.Value, depth int) error {
+	enc.buf = append(enc.buf, TagList)
+	enc.writeUvarint(uint64(value.Len()))
+	for i := 0; i < value.Len(); i++ {
+		if err := enc.encode(value.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (enc *encoder) encodeMap(value reflect.Value, depth int) error {
+	if value.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map key type: %s", value.Type().Key())
+	}
+
+	// Sort the keys to keep the encoding deterministic
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	enc.buf = append(enc.buf, TagMap)
+	enc.writeUvarint(uint64(len(keys)))
+	for _, key := range keys {
+		keyStr := key.String()
+		enc.writeUvarint(uint64(len(keyStr)))
+		enc.buf = append(enc.buf, keyStr...)
+		if err := enc.encode(value.MapIndex(key), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (enc *encoder) encodeStruct(value reflect.Value, depth int) error {
+	fields := structFields(value.Type())
+	enc.buf = append(enc.buf, TagMap)
+	enc.writeUvarint(uint64(len(fields)))
+	for _, field := range fields {
+		enc.writeUvarint(uint64(len(field.name)))
+		enc.buf = append(enc.buf, field.name...)
+		if err := enc.encode(value.Field(field.index), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
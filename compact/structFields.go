@@ -0,0 +1,46 @@
+package compact
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field represents an encodable struct field
+type field struct {
+	name  string
+	index int
+}
+
+// fieldsCache caches the reflected fields of struct types
+var fieldsCache sync.Map
+
+// structFields returns the encodable fields of the given struct type
+func structFields(typ reflect.Type) []field {
+	if cached, exists := fieldsCache.Load(typ); exists {
+		return cached.([]field)
+	}
+
+	fields := make([]field, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		if structField.PkgPath != "" {
+			// Ignore unexported fields
+			continue
+		}
+		name := structField.Name
+		if tag, hasTag := structField.Tag.Lookup("json"); hasTag {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields = append(fields, field{name: name, index: i})
+	}
+
+	fieldsCache.Store(typ, fields)
+	return fields
+}
@@ -0,0 +1,189 @@
+package compact
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Unmarshal decodes the given data into the value pointed to by target.
+//
+// If target is a pointer to an empty interface then the decoded variant is
+// stored as is: nil, bool, int64, uint64, float64, string, []byte,
+// time.Time, []interface{} or map[string]interface{}. Otherwise the variant
+// is converted to the type of the target value, see Marshal for the supported
+// types
+func Unmarshal(data []byte, target interface{}) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return errors.New("unmarshal target must be a non-nil pointer")
+	}
+
+	dec := decoder{data: data}
+	variant, err := dec.decode(0)
+	if err != nil {
+		return err
+	}
+	if dec.offset != len(data) {
+		return fmt.Errorf(
+			"unexpected trailing data (%d bytes)",
+			len(data)-dec.offset,
+		)
+	}
+
+	return Assign(ptr.Elem(), variant)
+}
+
+// decoder represents a compact binary decoder
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of data")
+
+func (dec *decoder) readByte() (byte, error) {
+	if dec.offset >= len(dec.data) {
+		return 0, errUnexpectedEnd
+	}
+	b := dec.data[dec.offset]
+	dec.offset++
+	return b, nil
+}
+
+func (dec *decoder) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(dec.data[dec.offset:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	dec.offset += n
+	return v, nil
+}
+
+func (dec *decoder) readVarint() (int64, error) {
+	v, n := binary.Varint(dec.data[dec.offset:])
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	dec.offset += n
+	return v, nil
+}
+
+// readLen reads a length prefix verifying that at least minItemSize bytes per
+// item are remaining to prevent excessive allocations on corrupt data
+func (dec *decoder) readLen(minItemSize int) (int, error) {
+	length, err := dec.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	remaining := uint64(len(dec.data) - dec.offset)
+	if length*uint64(minItemSize) > remaining ||
+		length > uint64(math.MaxInt32) {
+		return 0, errUnexpectedEnd
+	}
+	return int(length), nil
+}
+
+func (dec *decoder) readRaw() ([]byte, error) {
+	length, err := dec.readLen(1)
+	if err != nil {
+		return nil, err
+	}
+	raw := dec.data[dec.offset : dec.offset+length]
+	dec.offset += length
+	return raw, nil
+}
+
+func (dec *decoder) decode(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("maximum nesting depth (%d) exceeded", maxDepth)
+	}
+
+	tag, err := dec.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case TagNil:
+		return nil, nil
+
+	case TagFalse:
+		return false, nil
+
+	case TagTrue:
+		return true, nil
+
+	case TagInt:
+		return dec.readVarint()
+
+	case TagUint:
+		return dec.readUvarint()
+
+	case TagFloat:
+		if len(dec.data)-dec.offset < 8 {
+			return nil, errUnexpectedEnd
+		}
+		bits := binary.LittleEndian.Uint64(dec.data[dec.offset:])
+		dec.offset += 8
+		return math.Float64frombits(bits), nil
+
+	case TagString:
+		raw, err := dec.readRaw()
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+
+	case TagBytes:
+		raw, err := dec.readRaw()
+		if err != nil {
+			return nil, err
+		}
+		blob := make([]byte, len(raw))
+		copy(blob, raw)
+		return blob, nil
+
+	case TagList:
+		length, err := dec.readLen(1)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, length)
+		for i := range list {
+			if list[i], err = dec.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+
+	case TagMap:
+		length, err := dec.readLen(2)
+		if err != nil {
+			return nil, err
+		}
+		mp := make(map[string]interface{}, length)
+		for i := 0; i < length; i++ {
+			key, err := dec.readRaw()
+			if err != nil {
+				return nil, err
+			}
+			if mp[string(key)], err = dec.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return mp, nil
+
+	case TagTime:
+		nanos, err := dec.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(0, nanos).UTC(), nil
+	}
+
+	return nil, fmt.Errorf("invalid type tag: %d", tag)
+}
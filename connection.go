@@ -1,17 +1,22 @@
 package webwire
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
 	"golang.org/x/sync/semaphore"
 )
 
+// lastConnectionID represents the last assigned connection identifier.
+// It must be accessed atomically
+var lastConnectionID uint64
+
 // info represents basic information about a client connection
 type info struct {
 	Options    ConnectionOptions
@@ -21,6 +26,18 @@ type info struct {
 
 // connection represents a connected client connected to the server
 type connection struct {
+	// lastActivity represents the time (in unix nanoseconds) the last message
+	// was received on this connection. It must be accessed atomically and is
+	// kept at the top of the struct to guarantee 64-bit alignment
+	lastActivity int64
+
+	// stats represents the statistics counters of the connection, it's kept
+	// at the top of the struct to guarantee 64-bit alignment as well
+	stats connectionStats
+
+	// id represents the process-wide unique identifier of the connection
+	id uint64
+
 	// options represents the options defined during the connection upgrade
 	options ConnectionOptions
 
@@ -48,6 +65,12 @@ type connection struct {
 
 	// info represents overall connection information
 	info info
+
+	// rateLimits represents the rate limits state of the connection
+	rateLimits connectionRateLimits
+
+	// capabilities represents the capabilities negotiated with the client
+	capabilities message.Capabilities
 }
 
 // newConnection creates and returns a new client connection instance
@@ -65,7 +88,11 @@ func newConnection(
 		remoteAddr = socket.RemoteAddr()
 	}
 
+	creation := time.Now()
+
 	return &connection{
+		lastActivity: creation.UnixNano(),
+		id:           atomic.AddUint64(&lastConnectionID, 1),
 		options:      options,
 		stateLock:    sync.RWMutex{},
 		isActive:     isActive,
@@ -77,12 +104,17 @@ func newConnection(
 		session:      nil,
 		info: info{
 			Options:    options,
-			Creation:   time.Now(),
+			Creation:   creation,
 			RemoteAddr: remoteAddr,
 		},
 	}
 }
 
+// ID implements the Connection interface
+func (con *connection) ID() uint64 {
+	return con.id
+}
+
 // IsActive implements the Connection interface
 func (con *connection) IsActive() bool {
 	con.stateLock.RLock()
@@ -117,6 +149,16 @@ func (con *connection) deregisterTask() {
 	}
 }
 
+// touch updates the time of the last activity on this connection
+func (con *connection) touch() {
+	atomic.StoreInt64(&con.lastActivity, time.Now().UnixNano())
+}
+
+// lastActive returns the time of the last activity on this connection
+func (con *connection) lastActive() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&con.lastActivity))
+}
+
 // setSession sets a new session for this client
 func (con *connection) setSession(newSess *Session) {
 	con.sessionLock.Lock()
@@ -128,12 +170,32 @@ func (con *connection) setSession(newSess *Session) {
 // preparing it for garbage collection
 func (con *connection) unlink() {
 	// Deregister session from active sessions registry, but don't destroy it
-	con.srv.sessionRegistry.deregister(con, false)
-
 	con.sessionLock.Lock()
+	var sessionKey string
+	connsLeft := -1
+	if con.session != nil {
+		sessionKey = con.session.Key
+		connsLeft = con.srv.sessionRegistry.deregister(con, false)
+	}
 	con.session = nil
 	con.sessionLock.Unlock()
 
+	// Drop the rate limit bucket of the session
+	// once its last connection disconnected
+	if connsLeft == 0 {
+		con.srv.rateLimiter.forgetSession(sessionKey)
+	}
+
+	if connsLeft >= 0 {
+		con.srv.sessionEvents.publish(
+			SessionEventDetached,
+			con,
+			sessionKey,
+			SessionOutcomeDisconnected,
+			nil,
+		)
+	}
+
 	// Close connection
 	con.sock.Close()
 }
@@ -163,24 +225,58 @@ func (con *connection) Signal(name []byte, payload Payload) (err error) {
 		return ErrProtocol{Cause: errors.New("missing both name and payload")}
 	}
 
-	// Ensure the message won't exceed the buffer size
-	if uint32(message.CalcMsgLenSignal(name, payload.Encoding, payload.Data)) >
-		con.srv.options.MessageBufferSize {
+	// Ensure the message won't exceed the buffer size. Compressed messages
+	// are inflated into a buffer of the same size by the receiver, the length
+	// of the compressed message is additionally checked by the writer
+	msgLen := message.CalcMsgLenSignal(name, payload.Encoding, payload.Data)
+	if len(payload.Headers) > 0 {
+		msgLen += message.CalcMsgLenHeaders(payload.Headers)
+	}
+	if uint32(msgLen) > con.srv.options.MessageBufferSize {
+		con.srv.metrics.BufferOverflow()
 		return ErrBufferOverflow{}
 	}
 
-	writer, err := con.sock.GetWriter()
+	// Validate the message before acquiring the writer, UTF8 encoded payloads
+	// are only validated if ServerOptions.ValidateUtf8 is enabled
+	if err := message.ValidateSignalName(name); err != nil {
+		return err
+	}
+	if len(payload.Headers) > 0 {
+		if err := message.ValidateHeaders(payload.Headers); err != nil {
+			return err
+		}
+	}
+	if con.srv.options.ValidateUtf8 == Enabled &&
+		payload.Encoding == pld.Utf8 {
+		if err := pld.ValidateUtf8(payload.Data); err != nil {
+			return fmt.Errorf("invalid signal payload: %s", err)
+		}
+	}
+
+	writer, err := con.getWriter()
 	if err != nil {
 		return err
 	}
 
-	return message.WriteMsgSignal(
+	if len(payload.Headers) > 0 {
+		if err := message.WriteMsgHeaders(writer, payload.Headers); err != nil {
+			return err
+		}
+	}
+
+	if err := message.WriteMsgSignal(
 		writer,
 		name,
 		payload.Encoding,
 		payload.Data,
-		true,
-	)
+		false, // Already validated
+	); err != nil {
+		return err
+	}
+
+	atomic.AddUint64(&con.stats.signalsSent, 1)
+	return nil
 }
 
 // CreateSession implements the Connection interface
@@ -226,36 +322,46 @@ func (con *connection) CreateSession(attachment SessionInfo) error {
 	con.srv.sessionRegistry.register(con)
 	con.sessionLock.Unlock()
 
+	con.srv.sessionEvents.publish(
+		SessionEventCreated,
+		con,
+		newSession.Key,
+		SessionOutcomeSuccess,
+		nil,
+	)
+	con.srv.sessionEvents.publish(
+		SessionEventAttached,
+		con,
+		newSession.Key,
+		SessionOutcomeSuccess,
+		nil,
+	)
+
 	// Call session creation hook
 	if err := con.srv.sessionManager.OnSessionCreated(con); err != nil {
-		con.srv.errorLog.Printf("OnSessionCreated hook failed: %s", err)
+		con.srv.logger.Log(
+			LogLevelError,
+			"OnSessionCreated hook failed",
+			con.logFields(logErr(err))...,
+		)
 	}
 
 	return nil
 }
 
 func (con *connection) notifySessionCreated(newSession *Session) error {
-	// Serialize session info
-	var sessionInfo map[string]interface{}
-	if newSession.Info != nil {
-		sessionInfo = make(map[string]interface{})
-		for _, field := range newSession.Info.Fields() {
-			sessionInfo[field] = newSession.Info.Value(field)
-		}
-	}
-
-	encodedSessionInfo, err := json.Marshal(JSONEncodedSession{
-		newSession.Key,
-		newSession.Creation,
-		newSession.LastLookup,
-		sessionInfo,
+	encodedSessionInfo, err := con.srv.sessionCodec.Encode(EncodedSession{
+		Key:        newSession.Key,
+		Creation:   newSession.Creation,
+		LastLookup: newSession.LastLookup,
+		Info:       SessionInfoToVarMap(newSession.Info),
 	})
 	if err != nil {
-		return fmt.Errorf("Couldn't marshal session object: %s", err)
+		return fmt.Errorf("Couldn't encode session object: %s", err)
 	}
 
 	// Notify client about the session creation
-	writer, err := con.sock.GetWriter()
+	writer, err := con.getWriter()
 	if err != nil {
 		return err
 	}
@@ -267,13 +373,50 @@ func (con *connection) notifySessionCreated(newSession *Session) error {
 
 // notifySessionClosed notifies the client about the session destruction
 func (con *connection) notifySessionClosed() error {
-	writer, err := con.sock.GetWriter()
+	writer, err := con.getWriter()
 	if err != nil {
 		return err
 	}
 	return message.WriteMsgNotifySessionClosed(writer)
 }
 
+// evictSession resets the session of this connection if it's still the one
+// identified by the given key and notifies the client about the eviction.
+// The connection must already be deregistered from the session registry
+func (con *connection) evictSession(sessionKey string) error {
+	con.sessionLock.Lock()
+	if con.session == nil || con.session.Key != sessionKey {
+		con.sessionLock.Unlock()
+		return nil
+	}
+	con.session = nil
+	con.sessionLock.Unlock()
+
+	con.srv.sessionEvents.publish(
+		SessionEventEvicted,
+		con,
+		sessionKey,
+		SessionOutcomeEvicted,
+		nil,
+	)
+	con.srv.sessionEvents.publish(
+		SessionEventDetached,
+		con,
+		sessionKey,
+		SessionOutcomeEvicted,
+		nil,
+	)
+
+	writer, err := con.getWriter()
+	if err != nil {
+		return err
+	}
+	return message.WriteMsgNotifySessionClosedReason(
+		writer,
+		message.SessionClosureEvicted,
+	)
+}
+
 // CloseSession implements the Connection interface
 func (con *connection) CloseSession() error {
 	if !con.srv.sessionsEnabled {
@@ -288,10 +431,13 @@ func (con *connection) CloseSession() error {
 
 	// Deregister session from active sessions registry destroying it if it's
 	// the last connection left
-	con.srv.sessionRegistry.deregister(con, true)
+	sessionKey := con.session.Key
+	connsLeft := con.srv.sessionRegistry.deregister(con, true)
 	con.session = nil
 	con.sessionLock.Unlock()
 
+	con.srv.publishSessionClosed(con, sessionKey, connsLeft)
+
 	return con.notifySessionClosed()
 }
 
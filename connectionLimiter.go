@@ -0,0 +1,157 @@
+package webwire
+
+import (
+	"net"
+	"sync"
+)
+
+// ConnectionRefusalReason defines why an incoming connection was refused
+type ConnectionRefusalReason byte
+
+const (
+	// RefusedMaxConnections represents a connection refused due to the server
+	// reaching ServerOptions.MaxConnections
+	RefusedMaxConnections ConnectionRefusalReason = iota + 1
+
+	// RefusedMaxConnectionsPerIP represents a connection refused due to the
+	// remote IP address reaching ServerOptions.MaxConnectionsPerIP
+	RefusedMaxConnectionsPerIP
+)
+
+// String stringifies the refusal reason
+func (reason ConnectionRefusalReason) String() string {
+	switch reason {
+	case RefusedMaxConnections:
+		return "max-connections"
+	case RefusedMaxConnectionsPerIP:
+		return "max-connections-per-ip"
+	}
+	return ""
+}
+
+// OnConnectionRefused is called when an incoming connection was refused due
+// to exceeding the connection limits
+type OnConnectionRefused func(
+	remoteAddr net.Addr,
+	reason ConnectionRefusalReason,
+)
+
+// connectionLimiter keeps track of the number of connections
+// enforcing the connection limits
+type connectionLimiter struct {
+	lock     sync.Mutex
+	max      uint
+	maxPerIP uint
+	total    uint
+	perIP    map[string]uint
+}
+
+// newConnectionLimiter creates a new connection limiter.
+// Zero stands for unlimited
+func newConnectionLimiter(max, maxPerIP uint) *connectionLimiter {
+	return &connectionLimiter{
+		max:      max,
+		maxPerIP: maxPerIP,
+		perIP:    make(map[string]uint),
+	}
+}
+
+// admit reserves a connection slot for the given remote IP address
+// returning false and the refusal reason if any of the limits was reached.
+// Admitted connections must be released when they're closed
+func (limiter *connectionLimiter) admit(ip string) (
+	ConnectionRefusalReason,
+	bool,
+) {
+	if limiter.max < 1 && limiter.maxPerIP < 1 {
+		return 0, true
+	}
+
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+
+	if limiter.max > 0 && limiter.total >= limiter.max {
+		return RefusedMaxConnections, false
+	}
+	if limiter.maxPerIP > 0 && ip != "" &&
+		limiter.perIP[ip] >= limiter.maxPerIP {
+		return RefusedMaxConnectionsPerIP, false
+	}
+
+	limiter.total++
+	if ip != "" {
+		limiter.perIP[ip]++
+	}
+	return 0, true
+}
+
+// release releases a connection slot of the given remote IP address
+func (limiter *connectionLimiter) release(ip string) {
+	if limiter.max < 1 && limiter.maxPerIP < 1 {
+		return
+	}
+
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+
+	limiter.total--
+	if ip == "" {
+		return
+	}
+	if limiter.perIP[ip] <= 1 {
+		delete(limiter.perIP, ip)
+		return
+	}
+	limiter.perIP[ip]--
+}
+
+// remoteIP returns the IP address of the given remote address.
+// The entire address is returned if it doesn't contain a port
+// and an empty string is returned if the address is unknown
+func remoteIP(addr net.Addr) string {
+	switch addr := addr.(type) {
+	case nil:
+		return ""
+	case *net.TCPAddr:
+		return addr.IP.String()
+	case *net.UDPAddr:
+		return addr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// refuseConnection closes the socket of a connection that was refused
+// due to exceeding the connection limits
+func (srv *server) refuseConnection(
+	sock Socket,
+	remoteAddr net.Addr,
+	reason ConnectionRefusalReason,
+) {
+	var addr string
+	if remoteAddr != nil {
+		addr = remoteAddr.String()
+	}
+	srv.logger.Log(
+		LogLevelDebug,
+		"connection refused",
+		LogField{LogKeyRemoteAddr, addr},
+		LogField{LogKeyReason, reason.String()},
+	)
+
+	if err := sock.Close(); err != nil {
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't close socket",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
+	}
+
+	if srv.options.OnConnectionRefused != nil {
+		srv.options.OnConnectionRefused(remoteAddr, reason)
+	}
+}
@@ -0,0 +1,74 @@
+package webwire
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnectionLimiter tests enforcing the global connection limit
+func TestConnectionLimiter(t *testing.T) {
+	limiter := newConnectionLimiter(2, 0)
+
+	_, admitted := limiter.admit("10.0.0.1")
+	require.True(t, admitted)
+	_, admitted = limiter.admit("10.0.0.2")
+	require.True(t, admitted)
+
+	reason, admitted := limiter.admit("10.0.0.3")
+	require.False(t, admitted)
+	require.Equal(t, RefusedMaxConnections, reason)
+
+	limiter.release("10.0.0.1")
+	_, admitted = limiter.admit("10.0.0.3")
+	require.True(t, admitted)
+}
+
+// TestConnectionLimiterPerIP tests enforcing the per-IP connection limit
+func TestConnectionLimiterPerIP(t *testing.T) {
+	limiter := newConnectionLimiter(0, 2)
+
+	for i := 0; i < 2; i++ {
+		_, admitted := limiter.admit("10.0.0.1")
+		require.True(t, admitted)
+	}
+	reason, admitted := limiter.admit("10.0.0.1")
+	require.False(t, admitted)
+	require.Equal(t, RefusedMaxConnectionsPerIP, reason)
+
+	// Expect other addresses not to be affected
+	_, admitted = limiter.admit("10.0.0.2")
+	require.True(t, admitted)
+
+	// Expect unknown addresses not to be limited
+	for i := 0; i < 5; i++ {
+		_, admitted = limiter.admit("")
+		require.True(t, admitted)
+	}
+
+	limiter.release("10.0.0.1")
+	_, admitted = limiter.admit("10.0.0.1")
+	require.True(t, admitted)
+
+	// Expect released addresses to be forgotten
+	limiter.release("10.0.0.2")
+	require.NotContains(t, limiter.perIP, "10.0.0.2")
+}
+
+// TestRemoteIP tests extracting IP addresses from remote addresses
+func TestRemoteIP(t *testing.T) {
+	require.Equal(t, "", remoteIP(nil))
+	require.Equal(t, "10.0.0.1", remoteIP(&net.TCPAddr{
+		IP:   net.ParseIP("10.0.0.1"),
+		Port: 8080,
+	}))
+	require.Equal(t, "::1", remoteIP(&net.TCPAddr{
+		IP:   net.ParseIP("::1"),
+		Port: 8080,
+	}))
+	require.Equal(t, "/tmp/sock", remoteIP(&net.UnixAddr{
+		Name: "/tmp/sock",
+		Net:  "unix",
+	}))
+}
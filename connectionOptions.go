@@ -1,5 +1,10 @@
 package webwire
 
+import (
+	"crypto/tls"
+	"net/http"
+)
+
 // ConnectionAcceptance defines whether a connection is to be accepted
 type ConnectionAcceptance byte
 
@@ -18,6 +23,19 @@ type ConnectionOptions struct {
 	// layer implementation
 	Info map[int]interface{}
 
+	// Header stores the headers of the HTTP request the connection was
+	// established with. Whether it's provided depends on the transport layer
+	// implementation, it's nil if the transport doesn't provide it.
+	// The memchan transport provides the headers returned by its
+	// OnBeforeCreation hook
+	Header http.Header
+
+	// TLS stores the state of the TLS connection. Whether it's provided
+	// depends on the transport layer implementation, it's nil if the transport
+	// doesn't provide it. The memchan transport provides the state returned
+	// by its OnBeforeCreation hook
+	TLS *tls.ConnectionState
+
 	// Connection refuses the incoming connection when explicitly set to
 	// wwr.Refuse. It's set to wwr.Accept by default.
 	Connection ConnectionAcceptance
@@ -0,0 +1,164 @@
+package webwire
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/qbeon/webwire-go/message"
+)
+
+// ConnectionStats represents a snapshot of the statistics of a connection
+type ConnectionStats struct {
+	// BytesReceived is the total size of all received messages in bytes
+	BytesReceived uint64
+
+	// BytesSent is the total size of all sent messages in bytes
+	BytesSent uint64
+
+	// MessagesReceived is the number of received messages
+	MessagesReceived uint64
+
+	// MessagesSent is the number of sent messages
+	MessagesSent uint64
+
+	// RequestsServed is the number of requests replied to successfully
+	RequestsServed uint64
+
+	// RequestsFailed is the number of requests the handler returned
+	// an error for
+	RequestsFailed uint64
+
+	// SignalsReceived is the number of signals received from the client
+	SignalsReceived uint64
+
+	// SignalsSent is the number of signals sent to the client
+	SignalsSent uint64
+
+	// LastActivity is the time the last message was received at
+	LastActivity time.Time
+
+	// InFlightHandlers is the number of currently executed handlers
+	InFlightHandlers int32
+
+	// RequestLatency is the exponentially weighted moving average
+	// of the request handler latency
+	RequestLatency time.Duration
+}
+
+// requestLatencyWeight defines the weight of the latest sample in the
+// exponentially weighted moving average of the request latency
+const requestLatencyWeight = 0.2
+
+// connectionStats represents the statistics counters of a connection.
+// All fields must be accessed atomically
+type connectionStats struct {
+	bytesReceived    uint64
+	bytesSent        uint64
+	messagesReceived uint64
+	messagesSent     uint64
+	requestsServed   uint64
+	requestsFailed   uint64
+	signalsReceived  uint64
+	signalsSent      uint64
+	requestLatency   int64
+}
+
+// messageReceived records a received message of the given size
+func (stats *connectionStats) messageReceived(size int) {
+	atomic.AddUint64(&stats.messagesReceived, 1)
+	atomic.AddUint64(&stats.bytesReceived, uint64(size))
+}
+
+// requestHandled records a handled request
+func (stats *connectionStats) requestHandled(
+	latency time.Duration,
+	failed bool,
+) {
+	if failed {
+		atomic.AddUint64(&stats.requestsFailed, 1)
+	} else {
+		atomic.AddUint64(&stats.requestsServed, 1)
+	}
+
+	for {
+		current := atomic.LoadInt64(&stats.requestLatency)
+		next := int64(latency)
+		if current != 0 {
+			next = int64(requestLatencyWeight*float64(latency) +
+				(1-requestLatencyWeight)*float64(current))
+		}
+		if atomic.CompareAndSwapInt64(&stats.requestLatency, current, next) {
+			return
+		}
+	}
+}
+
+// statsWriter wraps a socket writer counting the sent bytes and messages
+type statsWriter struct {
+	writer  io.WriteCloser
+	stats   *connectionStats
+	written uint64
+}
+
+// Write implements the io.Writer interface
+func (wr *statsWriter) Write(data []byte) (int, error) {
+	n, err := wr.writer.Write(data)
+	wr.written += uint64(n)
+	return n, err
+}
+
+// Close implements the io.Closer interface
+func (wr *statsWriter) Close() error {
+	if err := wr.writer.Close(); err != nil {
+		return err
+	}
+	atomic.AddUint64(&wr.stats.messagesSent, 1)
+	atomic.AddUint64(&wr.stats.bytesSent, wr.written)
+	return nil
+}
+
+// getWriter returns a writer for the next message to send
+// recording the statistics of the sent message. Messages are compressed
+// if compression was negotiated with the client, in which case both the
+// uncompressed and the compressed length of the message are checked against
+// the message buffer size
+func (con *connection) getWriter() (io.WriteCloser, error) {
+	if con.capabilities.Has(message.CapabilityCompression) {
+		return &limitWriter{
+			con: con,
+			writer: message.NewCompressingWriter(
+				&lazyWriter{con: con},
+				int(con.srv.options.CompressionThreshold),
+			),
+		}, nil
+	}
+	writer, err := con.sock.GetWriter()
+	if err != nil {
+		return nil, err
+	}
+	return &statsWriter{writer: writer, stats: &con.stats}, nil
+}
+
+// Stats implements the Connection interface
+func (con *connection) Stats() ConnectionStats {
+	con.stateLock.RLock()
+	inFlight := con.tasks
+	con.stateLock.RUnlock()
+
+	return ConnectionStats{
+		BytesReceived:    atomic.LoadUint64(&con.stats.bytesReceived),
+		BytesSent:        atomic.LoadUint64(&con.stats.bytesSent),
+		MessagesReceived: atomic.LoadUint64(&con.stats.messagesReceived),
+		MessagesSent:     atomic.LoadUint64(&con.stats.messagesSent),
+		RequestsServed:   atomic.LoadUint64(&con.stats.requestsServed),
+		RequestsFailed:   atomic.LoadUint64(&con.stats.requestsFailed),
+		SignalsReceived:  atomic.LoadUint64(&con.stats.signalsReceived),
+		SignalsSent:      atomic.LoadUint64(&con.stats.signalsSent),
+		LastActivity:     con.lastActive(),
+		InFlightHandlers: inFlight,
+		RequestLatency: time.Duration(
+			atomic.LoadInt64(&con.stats.requestLatency),
+		),
+	}
+}
@@ -12,6 +12,17 @@ func (err ErrBufferOverflow) Error() string {
 	return "message buffer overflow"
 }
 
+// ErrConnectionNotFound represents an error indicating that no active
+// connection with the given identifier was found
+type ErrConnectionNotFound struct {
+	ConnectionID uint64
+}
+
+// Error implements the error interface
+func (err ErrConnectionNotFound) Error() string {
+	return fmt.Sprintf("connection %d not found", err.ConnectionID)
+}
+
 // ErrDeadlineExceeded represents a failure due to an excess of a user-defined
 // deadline
 type ErrDeadlineExceeded struct {
@@ -106,6 +117,15 @@ func (err ErrRequest) Error() string {
 	return err.Message
 }
 
+// ErrRateLimitExceeded represents a request error indicating that the request
+// was rejected due to exceeding a rate limit (see ServerOptions.RateLimits)
+type ErrRateLimitExceeded struct{}
+
+// Error implements the error interface
+func (err ErrRateLimitExceeded) Error() string {
+	return "rate limit exceeded"
+}
+
 // ErrServerShutdown represents a request error indicating that the request
 // cannot be processed due to the server currently being shut down
 type ErrServerShutdown struct{}
@@ -193,3 +213,14 @@ type ErrSockRead interface {
 	// IsCloseErr must return true if the error represents a closure error
 	IsCloseErr() bool
 }
+
+// ErrSockReadParse can optionally be implemented by webwire.Socket.Read errors
+// to distinguish corrupt incoming messages from other read failures
+// such as deadline expiry or network errors
+type ErrSockReadParse interface {
+	ErrSockRead
+
+	// IsParseErr must return true if the error was caused by
+	// a message that couldn't be parsed
+	IsParseErr() bool
+}
@@ -16,12 +16,12 @@ func (srv *server) failMsg(
 		return
 	}
 
-	writer, err := con.sock.GetWriter()
+	writer, err := con.getWriter()
 	if err != nil {
-		srv.errorLog.Printf(
-			"couldn't get writer for connection %p: %s",
-			con,
-			err,
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't get writer",
+			con.logFields(logErr(err))...,
 		)
 		return
 	}
@@ -35,7 +35,7 @@ func (srv *server) failMsg(
 			[]byte(err.Message),
 			true,
 		); err != nil {
-			srv.errorLog.Println("couldn't write error reply message: ", err)
+			srv.logErrorReplyFailure(con, "error reply", err)
 			return
 		}
 	case *ErrRequest:
@@ -46,7 +46,29 @@ func (srv *server) failMsg(
 			[]byte(err.Message),
 			true,
 		); err != nil {
-			srv.errorLog.Println("couldn't write error reply message: ", err)
+			srv.logErrorReplyFailure(con, "error reply", err)
+			return
+		}
+	case ErrRateLimitExceeded:
+		if err := message.WriteMsgReplyError(
+			writer,
+			msg.MsgIdentifierBytes,
+			[]byte(RateLimitExceededCode),
+			[]byte(err.Error()),
+			true,
+		); err != nil {
+			srv.logErrorReplyFailure(con, "rate limit exceeded reply", err)
+			return
+		}
+	case ErrProtocol:
+		if err := message.WriteMsgReplyError(
+			writer,
+			msg.MsgIdentifierBytes,
+			[]byte(ProtocolErrorCode),
+			[]byte(err.Error()),
+			true,
+		); err != nil {
+			srv.logErrorReplyFailure(con, "protocol error reply", err)
 			return
 		}
 	case ErrMaxSessConnsReached:
@@ -55,10 +77,7 @@ func (srv *server) failMsg(
 			message.MsgReplyMaxSessConnsReached,
 			msg.MsgIdentifierBytes,
 		); err != nil {
-			srv.errorLog.Println(
-				"couldn't write max sessions reached message: ",
-				err,
-			)
+			srv.logErrorReplyFailure(con, "max sessions reached reply", err)
 			return
 		}
 	case ErrSessionNotFound:
@@ -67,10 +86,7 @@ func (srv *server) failMsg(
 			message.MsgReplySessionNotFound,
 			msg.MsgIdentifierBytes,
 		); err != nil {
-			srv.errorLog.Println(
-				"couldn't write session not found message: ",
-				err,
-			)
+			srv.logErrorReplyFailure(con, "session not found reply", err)
 			return
 		}
 	case ErrSessionsDisabled:
@@ -79,10 +95,7 @@ func (srv *server) failMsg(
 			message.MsgReplySessionsDisabled,
 			msg.MsgIdentifierBytes,
 		); err != nil {
-			srv.errorLog.Println(
-				"couldn't write sessions disabled message: ",
-				err,
-			)
+			srv.logErrorReplyFailure(con, "sessions disabled reply", err)
 			return
 		}
 	default:
@@ -91,24 +104,24 @@ func (srv *server) failMsg(
 			message.MsgReplyInternalError,
 			msg.MsgIdentifierBytes,
 		); err != nil {
-			srv.errorLog.Println(
-				"couldn't write internal error message: ",
-				err,
-			)
+			srv.logErrorReplyFailure(con, "internal error reply", err)
 			return
 		}
 	}
+
+	srv.metrics.ErrorReplySent(errorReplyReason(reqErr))
 }
 
 // failMsgShutdown sends request failure reply due to current server shutdown
 func (srv *server) failMsgShutdown(con *connection, msg *message.Message) {
-	writer, err := con.sock.GetWriter()
+	writer, err := con.getWriter()
 	if err != nil {
-		srv.errorLog.Printf(
-			"couldn't get writer for connection %p: %s",
-			con,
-			err,
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't get writer",
+			con.logFields(logErr(err))...,
 		)
+		return
 	}
 
 	if err := message.WriteMsgSpecialRequestReply(
@@ -116,7 +129,45 @@ func (srv *server) failMsgShutdown(con *connection, msg *message.Message) {
 		message.MsgReplyShutdown,
 		msg.MsgIdentifierBytes,
 	); err != nil {
-		srv.errorLog.Println("failed writing shutdown reply message: ", err)
+		srv.logErrorReplyFailure(con, "shutdown reply", err)
 		return
 	}
+
+	srv.metrics.ErrorReplySent(ErrorReplyShutdown)
+}
+
+// errorReplyReason returns the reason of the error reply
+// for the given request error (see ErrorReply* constants)
+func errorReplyReason(reqErr error) string {
+	switch reqErr.(type) {
+	case ErrRequest, *ErrRequest:
+		return ErrorReplyRequest
+	case ErrMaxSessConnsReached:
+		return ErrorReplyMaxSessConnsReached
+	case ErrSessionNotFound:
+		return ErrorReplySessionNotFound
+	case ErrSessionsDisabled:
+		return ErrorReplySessionsDisabled
+	case ErrServerShutdown:
+		return ErrorReplyShutdown
+	case ErrRateLimitExceeded:
+		return ErrorReplyRateLimitExceeded
+	case ErrProtocol:
+		return ErrorReplyProtocolError
+	}
+	return ErrorReplyInternal
+}
+
+// logErrorReplyFailure logs the failure to write an error reply
+// of the given kind
+func (srv *server) logErrorReplyFailure(
+	con *connection,
+	replyKind string,
+	err error,
+) {
+	srv.logger.Log(
+		LogLevelError,
+		"couldn't write "+replyKind+" message",
+		con.logFields(logErr(err))...,
+	)
 }
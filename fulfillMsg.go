@@ -8,26 +8,56 @@ func (srv *server) fulfillMsg(
 	msg *message.Message,
 	replyPayload Payload,
 ) {
-	writer, err := con.sock.GetWriter()
+	// Validate the reply headers before acquiring the writer
+	if len(replyPayload.Headers) > 0 {
+		if err := message.ValidateHeaders(replyPayload.Headers); err != nil {
+			srv.logger.Log(
+				LogLevelError,
+				"invalid reply headers",
+				con.logFields(logErr(err))...,
+			)
+			srv.failMsg(con, msg, nil)
+			return
+		}
+	}
+
+	writer, err := con.getWriter()
 	if err != nil {
-		srv.errorLog.Printf(
-			"couldn't get writer for connection %p: %s",
-			con,
-			err,
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't get writer",
+			con.logFields(logErr(err))...,
 		)
 		return
 	}
 
+	if len(replyPayload.Headers) > 0 {
+		if err := message.WriteMsgHeaders(
+			writer,
+			replyPayload.Headers,
+		); err != nil {
+			srv.logger.Log(
+				LogLevelError,
+				"couldn't write reply headers",
+				con.logFields(logErr(err))...,
+			)
+			return
+		}
+	}
+
 	if err := message.WriteMsgReply(
 		writer,
 		msg.MsgIdentifierBytes,
 		replyPayload.Encoding,
 		replyPayload.Data,
 	); err != nil {
-		srv.errorLog.Printf(
-			"couldn't write reply message for connection %p: %s",
-			con,
-			err,
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't write reply message",
+			con.logFields(
+				LogField{LogKeyMessageType, msg.MsgType},
+				logErr(err),
+			)...,
 		)
 	}
 }
@@ -5,7 +5,7 @@ import (
 	"time"
 )
 
-func (srv *server) writeConfMessage(sock Socket) error {
+func (srv *server) writeConfMessage(sock Socket, confMsg []byte) error {
 	writer, err := sock.GetWriter()
 	if err != nil {
 		return fmt.Errorf(
@@ -14,7 +14,7 @@ func (srv *server) writeConfMessage(sock Socket) error {
 		)
 	}
 
-	if _, err := writer.Write(srv.configMsg); err != nil {
+	if _, err := writer.Write(confMsg); err != nil {
 		if closeErr := writer.Close(); closeErr != nil {
 			return fmt.Errorf(
 				"couldn't close writer after failed conf message write: %s: %s",
@@ -36,11 +36,70 @@ func (srv *server) handleConnection(
 	connectionOptions ConnectionOptions,
 	sock Socket,
 ) {
+	// Enforce the connection limits before accepting the connection
+	remoteAddr := sock.RemoteAddr()
+	ip := remoteIP(remoteAddr)
+	if reason, admitted := srv.connectionLimiter.admit(ip); !admitted {
+		srv.refuseConnection(sock, remoteAddr, reason)
+		return
+	}
+	defer srv.connectionLimiter.release(ip)
+
+	var addr string
+	if remoteAddr != nil {
+		addr = remoteAddr.String()
+	}
+
+	// Negotiate the protocol version and capabilities
+	negotiated, rejection, err := srv.negotiate(sock)
+	if err != nil {
+		srv.logger.Log(
+			LogLevelWarn,
+			"client hello negotiation failed",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
+		if closeErr := sock.Close(); closeErr != nil {
+			srv.logger.Log(
+				LogLevelError,
+				"couldn't close socket",
+				LogField{LogKeyRemoteAddr, addr},
+				logErr(closeErr),
+			)
+		}
+		return
+	}
+	if rejection != "" {
+		srv.rejectConnection(sock, remoteAddr, rejection)
+		return
+	}
+
+	// Authenticate the connection before accepting it
+	handshake := srv.handshake(
+		connectionOptions,
+		remoteAddr,
+		negotiated.hello,
+	)
+	if handshake.Reject {
+		srv.rejectConnection(sock, remoteAddr, handshake.Reason)
+		return
+	}
+
 	// Send server configuration message
-	if err := srv.writeConfMessage(sock); err != nil {
-		srv.errorLog.Println("couldn't write config message: ", err)
+	if err := srv.writeConfMessage(sock, negotiated.confMsg); err != nil {
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't write config message",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
 		if closeErr := sock.Close(); closeErr != nil {
-			srv.errorLog.Println("couldn't close socket: ", closeErr)
+			srv.logger.Log(
+				LogLevelError,
+				"couldn't close socket",
+				LogField{LogKeyRemoteAddr, addr},
+				logErr(closeErr),
+			)
 		}
 		return
 	}
@@ -51,11 +110,28 @@ func (srv *server) handleConnection(
 		srv,
 		connectionOptions,
 	)
+	connection.rateLimits = srv.rateLimiter.newConnectionRateLimits()
+	connection.capabilities = negotiated.capabilities
 
 	srv.connectionsLock.Lock()
-	srv.connections = append(srv.connections, connection)
+	srv.connections[connection.ID()] = connection
 	srv.connectionsLock.Unlock()
 
+	srv.metrics.ConnectionOpened()
+
+	// Create the session requested by the handshake hook
+	if handshake.SessionInfo != nil {
+		if err := connection.CreateSession(
+			handshake.SessionInfo,
+		); err != nil {
+			srv.logger.Log(
+				LogLevelError,
+				"couldn't create handshake session",
+				connection.logFields(logErr(err))...,
+			)
+		}
+	}
+
 	// Call hook on successful connection
 	srv.impl.OnClientConnected(connectionOptions, connection)
 
@@ -70,18 +146,45 @@ func (srv *server) handleConnection(
 		); err != nil {
 			msg.Close()
 
+			if parseErr, ok := err.(ErrSockReadParse); ok &&
+				parseErr.IsParseErr() {
+				srv.metrics.ProtocolViolation()
+			}
 			if !err.IsCloseErr() {
-				srv.warnLog.Printf("abnormal closure error: %s", err)
+				srv.logger.Log(
+					LogLevelWarn,
+					"abnormal closure error",
+					connection.logFields(logErr(err))...,
+				)
 			}
 
 			connection.Close()
+
+			srv.connectionsLock.Lock()
+			delete(srv.connections, connection.ID())
+			srv.connectionsLock.Unlock()
+
+			srv.metrics.ConnectionClosed()
+
 			srv.impl.OnClientDisconnected(connection, err)
 			break
 		}
 
+		connection.touch()
+		connection.stats.messageReceived(len(msg.MsgBuffer.Data()))
+		srv.metrics.MessageReceived()
+
 		// Parse & handle the message
+		msgType := msg.MsgType
 		if err := srv.handleMessage(connection, msg); err != nil {
-			srv.errorLog.Print("message handler failed: ", err)
+			srv.logger.Log(
+				LogLevelError,
+				"message handler failed",
+				connection.logFields(
+					LogField{LogKeyMessageType, msgType},
+					logErr(err),
+				)...,
+			)
 		}
 	}
 }
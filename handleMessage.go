@@ -17,6 +17,40 @@ func (srv *server) handleMessage(
 		return nil
 	}
 
+	// Drop compressed messages unless compression was negotiated
+	if msg.Compressed &&
+		!con.capabilities.Has(message.CapabilityCompression) {
+		srv.metrics.ProtocolViolation()
+		srv.logger.Log(
+			LogLevelWarn,
+			"received compressed message without negotiated compression",
+			con.logFields(LogField{LogKeyMessageType, msg.MsgType})...,
+		)
+
+		// Release message buffer
+		msg.Close()
+		return nil
+	}
+
+	switch msg.MsgType {
+	case message.MsgSignalBinary,
+		message.MsgSignalUtf8,
+		message.MsgSignalUtf16,
+		message.MsgRequestBinary,
+		message.MsgRequestUtf8,
+		message.MsgRequestUtf16,
+		message.MsgRequestRestoreSession,
+		message.MsgRequestCloseSession:
+		if !srv.rateLimiter.allow(con, msg) {
+			srv.rejectRateLimited(con, msg)
+			return nil
+		}
+	}
+
+	if !srv.validatePayload(con, msg) {
+		return nil
+	}
+
 	if !srv.registerHandler(con, msg) {
 		// Release message buffer
 		msg.Close()
@@ -51,6 +85,8 @@ func (srv *server) handleMessage(
 		srv.handleSessionClosure(con, msg)
 
 	default:
+		srv.metrics.ProtocolViolation()
+
 		// Immediately deregister handlers for unexpected message types
 		srv.deregisterHandler(con)
 
@@ -1,7 +1,7 @@
 package webwire
 
 import (
-	"context"
+	"time"
 
 	"github.com/qbeon/webwire-go/message"
 )
@@ -9,27 +9,34 @@ import (
 // handleRequest handles incoming requests
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleRequest(con *connection, msg *message.Message) {
+	ctx, span := srv.startSpan(SpanRequest, con, msg)
+
 	// Execute user-space hook
-	replyPayload, returnedErr := srv.impl.OnRequest(
-		context.Background(),
-		con,
-		msg,
-	)
+	start := time.Now()
+	replyPayload, returnedErr := srv.impl.OnRequest(ctx, con, msg)
+	latency := time.Since(start)
+	srv.metrics.RequestHandled(latency, returnedErr != nil)
+	con.stats.requestHandled(latency, returnedErr != nil)
 
 	// Handle returned error
 	switch returnedErr.(type) {
 	case nil:
 		srv.fulfillMsg(con, msg, replyPayload)
+		endSpan(span, nil)
 	case ErrRequest:
 		srv.failMsg(con, msg, returnedErr)
+		endSpan(span, returnedErr)
 	case *ErrRequest:
 		srv.failMsg(con, msg, returnedErr)
+		endSpan(span, returnedErr)
 	default:
-		srv.errorLog.Printf(
-			"request handler internal error: %v",
-			returnedErr,
+		srv.logger.Log(
+			LogLevelError,
+			"request handler internal error",
+			con.logFields(logErr(returnedErr))...,
 		)
 		srv.failMsg(con, msg, nil)
+		endSpan(span, ErrInternal{})
 	}
 
 	srv.deregisterHandler(con)
@@ -10,6 +10,8 @@ func (srv *server) handleSessionClosure(
 	con *connection,
 	msg *message.Message,
 ) {
+	_, span := srv.startSpan(SpanSessionClosure, con, msg)
+
 	finalize := func() {
 		srv.deregisterHandler(con)
 
@@ -20,24 +22,30 @@ func (srv *server) handleSessionClosure(
 	if !srv.sessionsEnabled {
 		srv.failMsg(con, msg, ErrSessionsDisabled{})
 		finalize()
+		endSpan(span, ErrSessionsDisabled{})
 		return
 	}
 
-	if !con.HasSession() {
+	sessionKey := con.SessionKey()
+	if sessionKey == "" {
 		// Send confirmation even though no session was closed
 		srv.fulfillMsg(con, msg, Payload{})
 		finalize()
+		endSpan(span, nil)
 		return
 	}
 
 	// Deregister session from active sessions registry destroying it if it's
 	// the last connection left
-	srv.sessionRegistry.deregister(con, true)
+	connsLeft := srv.sessionRegistry.deregister(con, true)
 
 	// Reset the session on the connection
 	con.setSession(nil)
 
+	srv.publishSessionClosed(con, sessionKey, connsLeft)
+
 	// Send confirmation
 	srv.fulfillMsg(con, msg, Payload{})
 	finalize()
+	endSpan(span, nil)
 }
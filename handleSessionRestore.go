@@ -1,7 +1,6 @@
 package webwire
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/qbeon/webwire-go/message"
@@ -13,6 +12,18 @@ func (srv *server) handleSessionRestore(
 	con *connection,
 	msg *message.Message,
 ) {
+	_, span := srv.startSpan(SpanSessionRestore, con, msg)
+	var replyErr error
+	defer func() { endSpan(span, replyErr) }()
+
+	fail := func(reqErr error) {
+		srv.failMsg(con, msg, reqErr)
+		if reqErr == nil {
+			reqErr = ErrInternal{}
+		}
+		replyErr = reqErr
+	}
+
 	finalize := func() {
 		srv.deregisterHandler(con)
 
@@ -20,19 +31,36 @@ func (srv *server) handleSessionRestore(
 		msg.Close()
 	}
 
+	key := string(msg.MsgPayload.Data)
+
 	if !srv.sessionsEnabled {
-		srv.failMsg(con, msg, ErrSessionsDisabled{})
+		fail(ErrSessionsDisabled{})
 		finalize()
+		srv.sessionEvents.publish(
+			SessionEventRestoreFailed,
+			con,
+			key,
+			SessionOutcomeSessionsDisabled,
+			ErrSessionsDisabled{},
+		)
 		return
 	}
 
-	key := string(msg.MsgPayload.Data)
-
+	// Reject the restoration early if the session already reached the maximum
+	// number of concurrent connections, unless a connection is to be evicted
 	sessConsNum := srv.sessionRegistry.sessionConnectionsNum(key)
-	if sessConsNum >= 0 && srv.sessionRegistry.maxConns > 0 &&
+	if srv.options.SessionEvictionPolicy == EvictionReject &&
+		sessConsNum >= 0 && srv.sessionRegistry.maxConns > 0 &&
 		uint(sessConsNum+1) > srv.sessionRegistry.maxConns {
-		srv.failMsg(con, msg, ErrMaxSessConnsReached{})
+		fail(ErrMaxSessConnsReached{})
 		finalize()
+		srv.sessionEvents.publish(
+			SessionEventMaxConnsRejected,
+			con,
+			key,
+			SessionOutcomeMaxConnsReached,
+			ErrMaxSessConnsReached{},
+		)
 		return
 	}
 
@@ -41,16 +69,37 @@ func (srv *server) handleSessionRestore(
 
 	if err != nil {
 		// Fail message with internal error and log it in case the handler fails
-		srv.failMsg(con, msg, nil)
+		fail(nil)
 		finalize()
-		srv.errorLog.Printf("session search handler failed: %s", err)
+		srv.logger.Log(
+			LogLevelError,
+			"session search handler failed",
+			con.logFields(
+				LogField{LogKeySessionKeyHash, HashSessionKey(key)},
+				logErr(err),
+			)...,
+		)
+		srv.sessionEvents.publish(
+			SessionEventRestoreFailed,
+			con,
+			key,
+			SessionOutcomeLookupFailed,
+			err,
+		)
 		return
 	}
 
 	if result == nil {
 		// Fail message with special error if the session wasn't found
-		srv.failMsg(con, msg, ErrSessionNotFound{})
+		fail(ErrSessionNotFound{})
 		finalize()
+		srv.sessionEvents.publish(
+			SessionEventRestoreFailed,
+			con,
+			key,
+			SessionOutcomeNotFound,
+			ErrSessionNotFound{},
+		)
 		return
 	}
 
@@ -58,20 +107,30 @@ func (srv *server) handleSessionRestore(
 	sessionLastLookup := result.LastLookup()
 	sessionInfo := result.Info()
 
-	// JSON encode the session
-	encodedSessionObj := JSONEncodedSession{
+	// Encode the session
+	encodedSessionObj := EncodedSession{
 		Key:        key,
 		Creation:   sessionCreation,
 		LastLookup: sessionLastLookup,
 		Info:       sessionInfo,
 	}
-	encodedSession, err := json.Marshal(&encodedSessionObj)
+	encodedSession, err := srv.sessionCodec.Encode(encodedSessionObj)
 	if err != nil {
-		srv.failMsg(con, msg, nil)
+		fail(nil)
 		finalize()
-		srv.errorLog.Printf(
-			"couldn't encode session object (%v): %s",
-			encodedSessionObj,
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't encode session object",
+			con.logFields(
+				LogField{LogKeySessionKeyHash, HashSessionKey(key)},
+				logErr(err),
+			)...,
+		)
+		srv.sessionEvents.publish(
+			SessionEventRestoreFailed,
+			con,
+			key,
+			SessionOutcomeEncodingFailed,
 			err,
 		)
 		return
@@ -89,7 +148,11 @@ func (srv *server) handleSessionRestore(
 		LastLookup: sessionLastLookup,
 		Info:       parsedSessInfo,
 	})
-	if err := srv.sessionRegistry.register(con); err != nil {
+	evicted, err := srv.sessionRegistry.registerEvicting(
+		con,
+		srv.options.SessionEvictionPolicy,
+	)
+	if err != nil {
 		panic(fmt.Errorf("the number of concurrent session connections was " +
 			"unexpectedly exceeded",
 		))
@@ -99,9 +162,38 @@ func (srv *server) handleSessionRestore(
 		con,
 		msg,
 		Payload{
-			Encoding: EncodingUtf8,
+			Encoding: sessionPayloadEncoding(srv.sessionCodec),
 			Data:     encodedSession,
 		},
 	)
 	finalize()
+
+	srv.sessionEvents.publish(
+		SessionEventRestored,
+		con,
+		key,
+		SessionOutcomeSuccess,
+		nil,
+	)
+	srv.sessionEvents.publish(
+		SessionEventAttached,
+		con,
+		key,
+		SessionOutcomeSuccess,
+		nil,
+	)
+
+	// Notify the evicted connection after the restoration is confirmed
+	if evicted != nil {
+		if err := evicted.evictSession(key); err != nil {
+			srv.logger.Log(
+				LogLevelError,
+				"couldn't notify connection about session eviction",
+				evicted.logFields(
+					LogField{LogKeySessionKeyHash, HashSessionKey(key)},
+					logErr(err),
+				)...,
+			)
+		}
+	}
 }
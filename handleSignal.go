@@ -1,7 +1,8 @@
 package webwire
 
 import (
-	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/qbeon/webwire-go/message"
 )
@@ -9,7 +10,14 @@ import (
 // handleSignal handles incoming signals
 // and returns an error if the ongoing connection cannot be proceeded
 func (srv *server) handleSignal(con *connection, msg *message.Message) {
-	srv.impl.OnSignal(context.Background(), con, msg)
+	ctx, span := srv.startSpan(SpanSignal, con, msg)
+	atomic.AddUint64(&con.stats.signalsReceived, 1)
+
+	start := time.Now()
+	srv.impl.OnSignal(ctx, con, msg)
+	srv.metrics.SignalHandled(time.Since(start))
+
+	span.End()
 
 	srv.deregisterHandler(con)
 
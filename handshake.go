@@ -0,0 +1,118 @@
+package webwire
+
+import (
+	"net"
+	"unicode/utf8"
+
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Handshake represents the credentials of an incoming connection
+// available during the handshake
+type Handshake struct {
+	// ConnectionOptions represents the options provided by the transport
+	// layer including the HTTP headers and the TLS connection state if the
+	// transport layer provides them (see ConnectionOptions)
+	ConnectionOptions ConnectionOptions
+
+	// RemoteAddr represents the address of the client
+	RemoteAddr net.Addr
+
+	// Hello represents the client hello message including the client name
+	// and the authentication blob. It's nil unless ServerOptions.ClientHello
+	// is enabled
+	Hello *message.ClientHello
+}
+
+// HandshakeResult represents the result of the OnHandshake hook
+type HandshakeResult struct {
+	// Reject rejects the connection when set to true
+	Reject bool
+
+	// Reason defines the rejection reason sent to the client.
+	// It's truncated to message.MaxLenRejectReason bytes at a UTF-8
+	// character boundary
+	Reason string
+
+	// SessionInfo creates a new session for the accepted connection
+	// with the given session info attached when it's not nil.
+	// The session is created before OnClientConnected is called
+	SessionInfo SessionInfo
+}
+
+// OnHandshake is called for every incoming connection before the server
+// configuration is sent to the client
+type OnHandshake func(handshake Handshake) HandshakeResult
+
+// handshake calls the OnHandshake hook (if any) for the given connection
+func (srv *server) handshake(
+	connectionOptions ConnectionOptions,
+	remoteAddr net.Addr,
+	hello *message.ClientHello,
+) HandshakeResult {
+	if srv.options.OnHandshake == nil {
+		return HandshakeResult{}
+	}
+	return srv.options.OnHandshake(Handshake{
+		ConnectionOptions: connectionOptions,
+		RemoteAddr:        remoteAddr,
+		Hello:             hello,
+	})
+}
+
+// rejectConnection sends the rejection reason to a connection rejected
+// during the handshake and closes its socket
+func (srv *server) rejectConnection(
+	sock Socket,
+	remoteAddr net.Addr,
+	reason string,
+) {
+	var addr string
+	if remoteAddr != nil {
+		addr = remoteAddr.String()
+	}
+	srv.logger.Log(
+		LogLevelDebug,
+		"connection rejected during handshake",
+		LogField{LogKeyRemoteAddr, addr},
+		LogField{LogKeyReason, reason},
+	)
+
+	if len(reason) > message.MaxLenRejectReason {
+		// Don't split multi-byte characters
+		end := message.MaxLenRejectReason
+		for end > 0 && !utf8.RuneStart(reason[end]) {
+			end--
+		}
+		reason = reason[:end]
+	}
+
+	writer, err := sock.GetWriter()
+	if err != nil {
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't get writer",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
+	} else if err := message.WriteMsgRejectConf(
+		writer,
+		[]byte(reason),
+	); err != nil {
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't write rejection message",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
+	}
+
+	if err := sock.Close(); err != nil {
+		srv.logger.Log(
+			LogLevelError,
+			"couldn't close socket",
+			LogField{LogKeyRemoteAddr, addr},
+			logErr(err),
+		)
+	}
+}
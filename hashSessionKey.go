@@ -0,0 +1,14 @@
+package webwire
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashSessionKey returns the hex encoded SHA-256 hash of the given session key.
+// Session keys are bearer credentials, the hash identifies a session in logs,
+// session events and administrative views without revealing its key
+func HashSessionKey(sessionKey string) string {
+	hash := sha256.Sum256([]byte(sessionKey))
+	return hex.EncodeToString(hash[:])
+}
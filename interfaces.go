@@ -5,6 +5,8 @@ import (
 	"net"
 	"net/url"
 	"time"
+
+	"github.com/qbeon/webwire-go/message"
 )
 
 // HeadlessServer defines the interface of a headless webwire server instance
@@ -37,6 +39,52 @@ type HeadlessServer interface {
 		closeErrors []error,
 		err error,
 	)
+
+	// ActiveSessions returns a summary of all currently active sessions
+	ActiveSessions() []SessionSummary
+
+	// CloseSessions closes all active sessions the given predicate returns
+	// true for. The predicate is passed a copy of each session object.
+	// The affected connections, the errors of each session closure attempt
+	// and a general error are returned just like CloseSession does
+	CloseSessions(predicate func(*Session) bool) (
+		affectedConnections []Connection,
+		closeErrors []error,
+		err error,
+	)
+
+	// CloseConnectionSession closes the session of the connection identified
+	// by the given connection ID (see Connection.ID) without affecting other
+	// connections of the session. Returns an ErrConnectionNotFound error if
+	// there's no active connection with the given ID.
+	// Does nothing if the connection has no session
+	CloseConnectionSession(connectionID uint64) error
+
+	// SubscribeSessionEvents registers a subscriber receiving all session
+	// lifecycle events and returns a function canceling the subscription
+	SubscribeSessionEvents(
+		subscriber SessionEventSubscriber,
+	) (unsubscribe func())
+
+	// Options returns a copy of the prepared server options
+	Options() ServerOptions
+
+	// Connections returns all currently active connections
+	Connections() []Connection
+
+	// CloseConnection closes the connection identified by the given
+	// connection ID (see Connection.ID). Returns an ErrConnectionNotFound
+	// error if there's no active connection with the given ID
+	CloseConnection(connectionID uint64) error
+
+	// CurrentOperations returns the number of currently processed
+	// signal and request handlers
+	CurrentOperations() uint32
+
+	// MessagePoolStats returns the usage statistics of the message buffer
+	// pool. Zero statistics are returned if the pool doesn't implement
+	// the message.StatsPool interface
+	MessagePoolStats() message.PoolStats
 }
 
 // Server defines the interface of a headed webwire server instance
@@ -116,6 +164,10 @@ type ServerImplementation interface {
 
 // Connection represents a connected client
 type Connection interface {
+	// ID returns the identifier of the connection which is unique
+	// within the process
+	ID() uint64
+
 	// IsActive returns true if this connection is in active state
 	// ready to accept incoming messages, otherwise returns false
 	IsActive() bool
@@ -132,7 +184,8 @@ type Connection interface {
 	Info(key int) interface{}
 
 	// Signal sends a named signal containing the given payload to the client.
-	// The name is optional
+	// The name is optional. The headers of the payload, if any, are attached
+	// to the signal
 	Signal(name []byte, payload Payload) error
 
 	// CreateSession creates a new session for this connection and
@@ -171,6 +224,9 @@ type Connection interface {
 	// in the form of an empty interface to be casted to either concrete type
 	SessionInfo(name string) interface{}
 
+	// Stats returns a snapshot of the statistics of this connection
+	Stats() ConnectionStats
+
 	// Close marks this connection for shutdown.
 	// It defers closing the connection until all work on it is done
 	// and removes it from the session registry.
@@ -297,6 +353,10 @@ type Message interface {
 	// PayloadUtf8 returns the message payload in textual UTF8 format
 	PayloadUtf8() ([]byte, error)
 
+	// Header returns the value of the message header identified by the
+	// given key or nil if the message carries no such header
+	Header(key string) []byte
+
 	// Close closes the message releasing the underlying buffer
 	Close()
 }
@@ -315,6 +375,10 @@ type Reply interface {
 	// PayloadUtf8 returns the message payload in textual UTF8 format
 	PayloadUtf8() ([]byte, error)
 
+	// Header returns the value of the reply header identified by the
+	// given key or nil if the reply carries no such header
+	Header(key string) []byte
+
 	// Close closes the reply message releasing the underlying buffer
 	Close()
 }
@@ -0,0 +1,47 @@
+package webwire
+
+import "io"
+
+// lazyWriter limits the length of an outgoing message to the message buffer
+// size and only acquires the socket writer once the first chunk of the message
+// is written. It's used beneath the compressing writer which writes the entire
+// (compressed) message at once, which is why messages exceeding the buffer
+// after compression are rejected before the socket writer is even acquired
+type lazyWriter struct {
+	con     *connection
+	writer  io.WriteCloser
+	written uint64
+}
+
+// Write implements the io.Writer interface
+func (wr *lazyWriter) Write(data []byte) (int, error) {
+	if wr.written+uint64(len(data)) >
+		uint64(wr.con.srv.options.MessageBufferSize) {
+		wr.con.srv.metrics.BufferOverflow()
+		return 0, ErrBufferOverflow{}
+	}
+
+	if wr.writer == nil {
+		if len(data) < 1 {
+			return 0, nil
+		}
+		writer, err := wr.con.sock.GetWriter()
+		if err != nil {
+			return 0, err
+		}
+		wr.writer = &statsWriter{writer: writer, stats: &wr.con.stats}
+	}
+
+	written, err := wr.writer.Write(data)
+	wr.written += uint64(written)
+	return written, err
+}
+
+// Close implements the io.Closer interface
+func (wr *lazyWriter) Close() error {
+	if wr.writer == nil {
+		// Nothing was written, the socket writer was never acquired
+		return nil
+	}
+	return wr.writer.Close()
+}
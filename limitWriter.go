@@ -0,0 +1,42 @@
+package webwire
+
+import "io"
+
+// limitWriter limits the uncompressed length of an outgoing message to the
+// message buffer size. It's used above the compressing writer because the
+// receiver inflates compressed messages into a buffer of the same size, so the
+// compressed length being within the limit isn't sufficient. Messages
+// exceeding the limit are discarded on Close instead of being compressed and
+// sent partially
+type limitWriter struct {
+	con        *connection
+	writer     io.WriteCloser
+	written    uint64
+	overflowed bool
+}
+
+// Write implements the io.Writer interface
+func (wr *limitWriter) Write(data []byte) (int, error) {
+	if wr.overflowed || wr.written+uint64(len(data)) >
+		uint64(wr.con.srv.options.MessageBufferSize) {
+		if !wr.overflowed {
+			wr.overflowed = true
+			wr.con.srv.metrics.BufferOverflow()
+		}
+		return 0, ErrBufferOverflow{}
+	}
+
+	written, err := wr.writer.Write(data)
+	wr.written += uint64(written)
+	return written, err
+}
+
+// Close implements the io.Closer interface
+func (wr *limitWriter) Close() error {
+	if wr.overflowed {
+		// Discard the message, the compressing writer never acquired the
+		// socket writer since it only writes the message on Close
+		return nil
+	}
+	return wr.writer.Close()
+}
@@ -0,0 +1,138 @@
+package webwire
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// LogLevel represents the severity of a log entry
+type LogLevel int8
+
+const (
+	// LogLevelDebug represents verbose diagnostic information
+	LogLevelDebug LogLevel = iota - 1
+
+	// LogLevelInfo represents informational events
+	LogLevelInfo
+
+	// LogLevelWarn represents unexpected but recoverable situations
+	LogLevelWarn
+
+	// LogLevelError represents failures
+	LogLevelError
+)
+
+// String stringifies the log level
+func (lvl LogLevel) String() string {
+	switch lvl {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", lvl)
+}
+
+// Keys of the log fields attached by the server
+const (
+	// LogKeyConnectionID is the identifier of the connection (uint64)
+	LogKeyConnectionID = "connection_id"
+
+	// LogKeyRemoteAddr is the remote address of the connection (string)
+	LogKeyRemoteAddr = "remote_addr"
+
+	// LogKeySessionKeyHash is the hex encoded SHA-256 hash
+	// of the session key (string)
+	LogKeySessionKeyHash = "session_key_hash"
+
+	// LogKeyMessageType is the type of the message (byte)
+	LogKeyMessageType = "message_type"
+
+	// LogKeyError is the error that caused the log entry (error)
+	LogKeyError = "error"
+
+	// LogKeyReason is the reason of a refusal (string)
+	LogKeyReason = "reason"
+)
+
+// LogField represents a key/value field of a structured log entry
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger defines the interface of a structured, leveled logger
+type Logger interface {
+	// Log writes a log entry with the given level, message and fields
+	Log(level LogLevel, message string, fields ...LogField)
+}
+
+// stdLogger implements the webwire.Logger interface
+// writing to standard library loggers
+type stdLogger struct {
+	warnLog  *log.Logger
+	errorLog *log.Logger
+}
+
+// NewStdLogger creates a new webwire.Logger writing entries of the error level
+// to errorLog and entries of the warn and info levels to warnLog. Debug entries
+// are discarded. The fields are appended to the message as key=value pairs
+func NewStdLogger(warnLog, errorLog *log.Logger) Logger {
+	return &stdLogger{
+		warnLog:  warnLog,
+		errorLog: errorLog,
+	}
+}
+
+// Log implements the webwire.Logger interface
+func (lg *stdLogger) Log(level LogLevel, message string, fields ...LogField) {
+	var target *log.Logger
+	switch {
+	case level >= LogLevelError:
+		target = lg.errorLog
+	case level >= LogLevelInfo:
+		target = lg.warnLog
+	}
+	if target == nil {
+		return
+	}
+
+	if len(fields) < 1 {
+		target.Output(2, message)
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString(message)
+	for _, field := range fields {
+		fmt.Fprintf(&builder, " %s=%v", field.Key, field.Value)
+	}
+	target.Output(2, builder.String())
+}
+
+// logFields returns the log fields identifying the connection
+// and its current session if any
+func (con *connection) logFields(fields ...LogField) []LogField {
+	all := make([]LogField, 0, 3+len(fields))
+	all = append(all, LogField{LogKeyConnectionID, con.ID()})
+	if remoteAddr := con.RemoteAddr(); remoteAddr != nil {
+		all = append(all, LogField{LogKeyRemoteAddr, remoteAddr.String()})
+	}
+	if sessionKey := con.SessionKey(); sessionKey != "" {
+		all = append(all, LogField{
+			LogKeySessionKeyHash,
+			HashSessionKey(sessionKey),
+		})
+	}
+	return append(all, fields...)
+}
+
+// logErr returns an error log field
+func logErr(err error) LogField {
+	return LogField{LogKeyError, err}
+}
@@ -0,0 +1,27 @@
+package webwire
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestStdLogger tests the standard library logger adapter
+func TestStdLogger(t *testing.T) {
+	warnBuf := &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	logger := NewStdLogger(log.New(warnBuf, "", 0), log.New(errBuf, "", 0))
+
+	logger.Log(LogLevelDebug, "discarded")
+	require.Equal(t, 0, warnBuf.Len())
+	require.Equal(t, 0, errBuf.Len())
+
+	logger.Log(LogLevelWarn, "warning", LogField{LogKeyConnectionID, 42})
+	require.Equal(t, "warning connection_id=42\n", warnBuf.String())
+
+	logger.Log(LogLevelError, "failure", logErr(errors.New("sample")))
+	require.Equal(t, "failure error=sample\n", errBuf.String())
+}
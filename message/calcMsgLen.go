@@ -27,3 +27,11 @@ func CalcMsgLenRequest(
 	}
 	return 10 + len(name) + len(payload)
 }
+
+// CalcMsgLenReply returns the size of a reply message with the given payload
+func CalcMsgLenReply(encoding pld.Encoding, payload []byte) int {
+	if encoding == pld.Utf16 {
+		return 10 + len(payload)
+	}
+	return 9 + len(payload)
+}
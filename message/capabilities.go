@@ -0,0 +1,20 @@
+package message
+
+// Capabilities represents a set of optional protocol features
+// negotiated during the handshake
+type Capabilities uint16
+
+const (
+	// CapabilityFragmentation represents the support
+	// of fragmented messages
+	CapabilityFragmentation Capabilities = 1 << iota
+
+	// CapabilityCompression represents the support
+	// of compressed message payloads
+	CapabilityCompression
+)
+
+// Has returns true if the set contains all of the given capabilities
+func (caps Capabilities) Has(capabilities Capabilities) bool {
+	return caps&capabilities == capabilities
+}
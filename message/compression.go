@@ -0,0 +1,169 @@
+package message
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// flateReaders pools DEFLATE decompressors
+var flateReaders = sync.Pool{
+	New: func() interface{} {
+		return flate.NewReader(nil)
+	},
+}
+
+// flateWriters pools DEFLATE compressors
+var flateWriters = sync.Pool{
+	New: func() interface{} {
+		// flate.NewWriter only fails on invalid compression levels
+		writer, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return writer
+	},
+}
+
+// scratchBuffers pools the buffers used during compression and decompression
+var scratchBuffers = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+// decompress replaces the compressed message envelope in the message buffer
+// by the decompressed message. Returns an error if the decompressed message
+// exceeds the message buffer
+func (msg *Message) decompress() error {
+	if msg.MsgBuffer.len < MinLenCompressed {
+		return errors.New("invalid msg length, too short")
+	}
+
+	// Copy the compressed message because it's decompressed
+	// into the same message buffer
+	compressed := scratchBuffers.Get().(*bytes.Buffer)
+	defer scratchBuffers.Put(compressed)
+	compressed.Reset()
+	compressed.Write(msg.MsgBuffer.buf[1:msg.MsgBuffer.len])
+
+	reader := flateReaders.Get().(io.ReadCloser)
+	defer flateReaders.Put(reader)
+	if err := reader.(flate.Resetter).Reset(compressed, nil); err != nil {
+		msg.MsgBuffer.Close()
+		return fmt.Errorf("couldn't reset decompressor: %s", err)
+	}
+
+	cursor := 0
+	for cursor < len(msg.MsgBuffer.buf) {
+		read, err := reader.Read(msg.MsgBuffer.buf[cursor:])
+		cursor += read
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			msg.MsgBuffer.Close()
+			return fmt.Errorf("couldn't decompress message: %s", err)
+		}
+	}
+
+	// Ensure the entire message was decompressed
+	if cursor >= len(msg.MsgBuffer.buf) {
+		var probe [1]byte
+		if read, _ := reader.Read(probe[:]); read > 0 {
+			msg.MsgBuffer.Close()
+			return errors.New("message buffer overflow")
+		}
+	}
+
+	if cursor < 1 {
+		msg.MsgBuffer.Close()
+		return errors.New("empty compressed message")
+	}
+
+	msg.MsgBuffer.len = cursor
+	msg.Compressed = true
+	return nil
+}
+
+// compressingWriter buffers the written message and compresses it on Close
+// if it reaches the threshold
+type compressingWriter struct {
+	writer    io.WriteCloser
+	threshold int
+	buffer    *bytes.Buffer
+}
+
+// NewCompressingWriter wraps the given message writer compressing messages
+// of at least threshold bytes into a compressed message envelope.
+// Messages are written uncompressed if compression doesn't reduce their size.
+// The entire (compressed) message is written to the given writer at once on
+// Close, so the given writer can check the length of the transmitted message
+// against the buffer size in addition to the uncompressed length calculated
+// by the CalcMsgLen* functions
+func NewCompressingWriter(
+	writer io.WriteCloser,
+	threshold int,
+) io.WriteCloser {
+	buffer := scratchBuffers.Get().(*bytes.Buffer)
+	buffer.Reset()
+	return &compressingWriter{
+		writer:    writer,
+		threshold: threshold,
+		buffer:    buffer,
+	}
+}
+
+// Write implements the io.Writer interface
+func (wr *compressingWriter) Write(data []byte) (int, error) {
+	if wr.buffer == nil {
+		return 0, errors.New("write to closed writer")
+	}
+	return wr.buffer.Write(data)
+}
+
+// Close implements the io.Closer interface
+// writing the (compressed) message to the underlying writer
+func (wr *compressingWriter) Close() error {
+	if wr.buffer == nil {
+		return errors.New("writer already closed")
+	}
+	buffer := wr.buffer
+	wr.buffer = nil
+	defer scratchBuffers.Put(buffer)
+
+	data := buffer.Bytes()
+	if len(data) >= wr.threshold && len(data) > 0 {
+		compressed := scratchBuffers.Get().(*bytes.Buffer)
+		defer scratchBuffers.Put(compressed)
+		compressed.Reset()
+		compressed.WriteByte(MsgCompressed)
+
+		compressor := flateWriters.Get().(*flate.Writer)
+		compressor.Reset(compressed)
+		_, err := compressor.Write(data)
+		if err == nil {
+			err = compressor.Close()
+		}
+		flateWriters.Put(compressor)
+		if err != nil {
+			if closeErr := wr.writer.Close(); closeErr != nil {
+				return fmt.Errorf("%s: %s", err, closeErr)
+			}
+			return err
+		}
+
+		// Only send the compressed message if it's smaller
+		if compressed.Len() < len(data) {
+			data = compressed.Bytes()
+		}
+	}
+
+	if _, err := wr.writer.Write(data); err != nil {
+		if closeErr := wr.writer.Close(); closeErr != nil {
+			return fmt.Errorf("%s: %s", err, closeErr)
+		}
+		return err
+	}
+
+	return wr.writer.Close()
+}
@@ -0,0 +1,126 @@
+package message_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"math/rand"
+	"testing"
+
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// compressRaw returns the given message wrapped
+// in a compressed message envelope
+func compressRaw(t *testing.T, msg []byte) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(message.MsgCompressed)
+	compressor, err := flate.NewWriter(buf, flate.DefaultCompression)
+	require.NoError(t, err)
+	_, err = compressor.Write(msg)
+	require.NoError(t, err)
+	require.NoError(t, compressor.Close())
+	return buf.Bytes()
+}
+
+// TestCompressingWriter tests compressing messages
+// and parsing compressed messages
+func TestCompressingWriter(t *testing.T) {
+	id := genRndMsgIdentifier()
+	payload := bytes.Repeat([]byte("compressible "), 100)
+
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgReply(
+		message.NewCompressingWriter(writer, 64),
+		id,
+		pld.Utf8,
+		payload,
+	))
+	require.True(t, writer.closed)
+	require.Equal(t, message.MsgCompressed, writer.buf[0])
+	require.True(t, len(writer.buf) < len(payload))
+	require.True(
+		t,
+		len(writer.buf) <= message.CalcMsgLenReply(pld.Utf8, payload),
+	)
+
+	// Parse
+	actual := message.NewMessage(
+		uint32(message.CalcMsgLenReply(pld.Utf8, payload)),
+	)
+	typeDetermined, err := actual.ReadBytes(writer.buf)
+	require.True(t, typeDetermined)
+	require.NoError(t, err)
+
+	// Compare
+	require.True(t, actual.Compressed)
+	require.Equal(t, message.MsgReplyUtf8, actual.MsgType)
+	require.Equal(t, id, actual.MsgIdentifierBytes)
+	require.Equal(t, payload, actual.MsgPayload.Data)
+}
+
+// TestCompressingWriterThreshold tests not compressing messages
+// below the threshold
+func TestCompressingWriterThreshold(t *testing.T) {
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgHeartbeat(
+		message.NewCompressingWriter(writer, 64),
+	))
+	require.Equal(t, []byte{message.MsgHeartbeat}, writer.buf)
+	require.True(t, writer.closed)
+}
+
+// TestCompressingWriterIncompressible tests not compressing messages
+// whose size isn't reduced by compression
+func TestCompressingWriterIncompressible(t *testing.T) {
+	id := genRndMsgIdentifier()
+	payload := make([]byte, 256)
+	rand.Read(payload)
+
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgReply(
+		message.NewCompressingWriter(writer, 0),
+		id,
+		pld.Binary,
+		payload,
+	))
+	require.Equal(t, message.MsgReplyBinary, writer.buf[0])
+	require.Len(t, writer.buf, message.CalcMsgLenReply(pld.Binary, payload))
+}
+
+// TestMsgParseCompressedOverflow tests parsing of compressed messages
+// exceeding the message buffer when decompressed
+func TestMsgParseCompressedOverflow(t *testing.T) {
+	inner := append(
+		[]byte{message.MsgSignalBinary, 0},
+		bytes.Repeat([]byte{'a'}, 1024)...,
+	)
+	encoded := compressRaw(t, inner)
+
+	msg := message.NewMessage(512)
+	_, err := msg.ReadBytes(encoded)
+	require.Error(t, err)
+}
+
+// TestMsgParseCompressedNested tests parsing of nested
+// compressed message envelopes
+func TestMsgParseCompressedNested(t *testing.T) {
+	inner := append(
+		[]byte{message.MsgSignalBinary, 0},
+		bytes.Repeat([]byte{'a'}, 64)...,
+	)
+	encoded := compressRaw(t, compressRaw(t, inner))
+
+	msg := message.NewMessage(1024)
+	_, err := msg.ReadBytes(encoded)
+	require.Error(t, err)
+}
+
+// TestMsgParseCompressedCorrupt tests parsing of corrupt
+// compressed message envelopes
+func TestMsgParseCompressedCorrupt(t *testing.T) {
+	msg := message.NewMessage(1024)
+	_, err := msg.ReadBytes([]byte{message.MsgCompressed, 0xff, 0xff, 0xff})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Header represents a key/value message header
+type Header struct {
+	Key   []byte
+	Value []byte
+}
+
+// unwrapHeaders parses the header section of the header envelope in the
+// message buffer and replaces the envelope by the enveloped message.
+// The header section is copied because the enveloped message is moved
+// to the beginning of the message buffer
+func (msg *Message) unwrapHeaders() error {
+	if msg.MsgBuffer.len < MinLenHeaders {
+		return errors.New("invalid header envelope, too short")
+	}
+
+	dat := msg.MsgBuffer.Data()
+	count := int(dat[1])
+	if count < 1 {
+		return errors.New("invalid header envelope, no headers")
+	}
+
+	// Determine the end of the header section verifying the length flags
+	end := 2
+	for i := 0; i < count; i++ {
+		if end >= len(dat) {
+			return fmt.Errorf("invalid header envelope, header %d missing", i)
+		}
+		keyLen := int(dat[end])
+		if keyLen < 1 {
+			return fmt.Errorf("invalid header envelope, header %d has no key", i)
+		}
+		if end+1+keyLen+2 > len(dat) {
+			return fmt.Errorf(
+				"invalid header envelope, too short for header key (%d)",
+				keyLen,
+			)
+		}
+		for _, char := range dat[end+1 : end+1+keyLen] {
+			if char < 32 || char > 126 {
+				return fmt.Errorf(
+					"invalid header envelope, unsupported character in "+
+						"header key: %s",
+					string(char),
+				)
+			}
+		}
+		end += 1 + keyLen
+		valueLen := int(dat[end]) | int(dat[end+1])<<8
+		end += 2 + valueLen
+		if end > len(dat) {
+			return fmt.Errorf(
+				"invalid header envelope, too short for header value (%d)",
+				valueLen,
+			)
+		}
+	}
+
+	if end >= len(dat) {
+		return errors.New("invalid header envelope, missing enveloped message")
+	}
+
+	switch dat[end] {
+	case MsgSignalBinary, MsgSignalUtf8, MsgSignalUtf16,
+		MsgRequestBinary, MsgRequestUtf8, MsgRequestUtf16,
+		MsgReplyBinary, MsgReplyUtf8, MsgReplyUtf16:
+	default:
+		return fmt.Errorf(
+			"invalid header envelope, unsupported enveloped message type: %d",
+			dat[end],
+		)
+	}
+
+	// Copy the header section and slice the headers
+	msg.headerData = append(msg.headerData[:0], dat[2:end]...)
+	section := msg.headerData
+	for i := 0; i < count; i++ {
+		keyLen := int(section[0])
+		key := section[1 : 1+keyLen]
+		section = section[1+keyLen:]
+		valueLen := int(section[0]) | int(section[1])<<8
+		msg.Headers = append(msg.Headers, Header{
+			Key:   key,
+			Value: section[2 : 2+valueLen],
+		})
+		section = section[2+valueLen:]
+	}
+
+	// Move the enveloped message to the beginning of the buffer
+	msg.MsgBuffer.len = copy(msg.MsgBuffer.buf, dat[end:])
+	return nil
+}
+
+// CalcMsgLenHeaders returns the size of the header envelope
+// for the given headers excluding the enveloped message
+func CalcMsgLenHeaders(headers []Header) int {
+	size := 2
+	for i := range headers {
+		size += 3 + len(headers[i].Key) + len(headers[i].Value)
+	}
+	return size
+}
@@ -0,0 +1,162 @@
+package message_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testHeaders returns a sample set of headers
+func testHeaders() []message.Header {
+	return []message.Header{
+		{Key: []byte("traceparent"), Value: []byte("00-abc-def-01")},
+		{Key: []byte("empty"), Value: nil},
+	}
+}
+
+// TestMsgParseHeaders tests parsing of a request enveloped
+// in a header envelope
+func TestMsgParseHeaders(t *testing.T) {
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgHeaders(writer, testHeaders()))
+	require.False(t, writer.closed)
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{1, 2, 3, 4, 5, 6, 7, 8},
+		[]byte("name"),
+		pld.Utf16,
+		[]byte{0x41, 0x00},
+		true,
+	))
+	require.Len(t,
+		writer.buf,
+		message.CalcMsgLenHeaders(testHeaders())+
+			message.CalcMsgLenRequest([]byte("name"), pld.Utf16, []byte{0x41, 0}),
+	)
+
+	actual := tryParseNoErr(t, writer.buf)
+	require.Equal(t, message.MsgRequestUtf16, actual.MsgType)
+	require.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, actual.MsgIdentifierBytes)
+	require.Equal(t, []byte("name"), actual.MsgName)
+	require.Equal(t, pld.Payload{
+		Encoding: pld.Utf16,
+		Data:     []byte{0x41, 0x00},
+	}, actual.MsgPayload)
+	require.Len(t, actual.Headers, 2)
+	require.Equal(t, []byte("00-abc-def-01"), actual.Header("traceparent"))
+	require.Len(t, actual.Header("empty"), 0)
+	require.Nil(t, actual.Header("inexistent"))
+}
+
+// TestMsgParseHeadersCompressed tests parsing of a compressed signal
+// enveloped in a header envelope
+func TestMsgParseHeadersCompressed(t *testing.T) {
+	writer := &testWriter{}
+	compressing := message.NewCompressingWriter(writer, 1)
+	require.NoError(t, message.WriteMsgHeaders(compressing, testHeaders()))
+	require.NoError(t, message.WriteMsgSignal(
+		compressing,
+		[]byte("name"),
+		pld.Utf8,
+		bytes.Repeat([]byte("a"), 512),
+		true,
+	))
+	require.Equal(t, message.MsgCompressed, writer.buf[0])
+
+	actual := message.NewMessage(1024)
+	typeParsed, err := actual.ReadBytes(writer.buf)
+	require.NoError(t, err)
+	require.True(t, typeParsed)
+	require.True(t, actual.Compressed)
+	require.Equal(t, message.MsgSignalUtf8, actual.MsgType)
+	require.Equal(t, []byte("00-abc-def-01"), actual.Header("traceparent"))
+}
+
+// TestMsgParseHeadersInvalid tests parsing of invalid header envelopes
+func TestMsgParseHeadersInvalid(t *testing.T) {
+	signal := []byte{message.MsgSignalBinary, 0, 'x'}
+	for name, encoded := range map[string][]byte{
+		"no headers": append(
+			[]byte{message.MsgHeaders, 0, 1, 'k', 0, 0},
+			signal...,
+		),
+		"empty key": append(
+			[]byte{message.MsgHeaders, 1, 0, 0, 0, 0},
+			signal...,
+		),
+		"invalid key character": append(
+			[]byte{message.MsgHeaders, 1, 1, 10, 0, 0},
+			signal...,
+		),
+		"value too long": append(
+			[]byte{message.MsgHeaders, 1, 1, 'k', 0xFF, 0},
+			signal...,
+		),
+		"missing enveloped message": {
+			message.MsgHeaders, 1, 1, 'k', 1, 0, 'v',
+		},
+		"nested envelope": append(
+			[]byte{message.MsgHeaders, 1, 1, 'k', 0, 0, message.MsgHeaders},
+			[]byte{1, 1, 'k', 0, 0, message.MsgSignalBinary, 0, 'x'}...,
+		),
+		"unsupported enveloped message": {
+			message.MsgHeaders, 1, 1, 'k', 0, 0, message.MsgHeartbeat,
+		},
+	} {
+		msg := message.NewMessage(uint32(len(encoded)))
+		_, err := msg.ReadBytes(encoded)
+		require.Error(t, err, name)
+	}
+}
+
+// TestWriteMsgHeadersInvalid tests WriteMsgHeaders with invalid headers
+func TestWriteMsgHeadersInvalid(t *testing.T) {
+	tooMany := make([]message.Header, message.MaxHeaders+1)
+	for i := range tooMany {
+		tooMany[i] = message.Header{Key: []byte("k")}
+	}
+
+	for name, headers := range map[string][]message.Header{
+		"no headers":            nil,
+		"too many headers":      tooMany,
+		"empty key":             {{Key: nil}},
+		"invalid key character": {{Key: []byte{127}}},
+		"value too long": {{
+			Key:   []byte("k"),
+			Value: make([]byte, message.MaxLenHeaderValue+1),
+		}},
+	} {
+		writer := &testWriter{}
+		require.Error(t, message.WriteMsgHeaders(writer, headers), name)
+		require.True(t, writer.closed, name)
+		require.Nil(t, writer.buf, name)
+	}
+}
+
+// TestMsgParseHeadersOnRaw tests observing the raw data of an enveloped
+// message before it's unwrapped
+func TestMsgParseHeadersOnRaw(t *testing.T) {
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgHeaders(writer, testHeaders()))
+	require.NoError(t, message.WriteMsgSignal(
+		writer,
+		[]byte("name"),
+		pld.Binary,
+		[]byte{1, 2, 3},
+		true,
+	))
+
+	var raw []byte
+	msg := message.NewMessage(1024)
+	msg.OnRaw = func(data []byte) {
+		raw = append([]byte(nil), data...)
+	}
+	typeParsed, err := msg.ReadBytes(writer.buf)
+	require.NoError(t, err)
+	require.True(t, typeParsed)
+	require.Equal(t, message.MsgSignalBinary, msg.MsgType)
+	require.Equal(t, writer.buf, raw)
+}
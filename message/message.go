@@ -103,6 +103,7 @@ const (
 	// of session creation notification messages.
 	// Session destruction notification message structure:
 	//  1. message type (1 byte)
+	//  2. closure reason (1 byte, optional, cannot be 0)
 	MinLenNotifySessionClosed = int(1)
 
 	// MinLenAcceptConf represents the minimum length
@@ -114,6 +115,93 @@ const (
 	//  5. message buffer size in bytes (4 byte)
 	//  6. sub-protocol name (0+ bytes)
 	MinLenAcceptConf = int(11)
+
+	// MinLenAcceptConfV21 represents the minimum length
+	// of an endpoint metadata message of protocol version 2.1 and newer.
+	//  1. message type (1 byte)
+	//  2. major protocol version (1 byte)
+	//  3. minor protocol version (1 byte)
+	//  4. read timeout in milliseconds (4 byte)
+	//  5. message buffer size in bytes (4 byte)
+	//  6. session codec identifier (1 byte)
+	//  7. sub-protocol name (0+ bytes)
+	MinLenAcceptConfV21 = int(12)
+
+	// MinLenAcceptConfV22 represents the minimum length
+	// of an endpoint metadata message of protocol version 2.2 and newer.
+	//  1. message type (1 byte)
+	//  2. major protocol version (1 byte)
+	//  3. minor protocol version (1 byte)
+	//  4. read timeout in milliseconds (4 byte)
+	//  5. message buffer size in bytes (4 byte)
+	//  6. session codec identifier (1 byte)
+	//  7. negotiated capabilities (2 byte)
+	//  8. sub-protocol name (0+ bytes)
+	MinLenAcceptConfV22 = int(14)
+
+	// MinLenClientHello represents the minimum length
+	// of a client hello message.
+	// Client hello message structure:
+	//  1. message type (1 byte)
+	//  2. major protocol version (1 byte)
+	//  3. min supported minor protocol version (1 byte)
+	//  4. max supported minor protocol version (1 byte)
+	//  5. supported capabilities (2 byte)
+	//  6. client name length flag (1 byte)
+	//  7. client name (0 to 255 bytes, UTF8 encoded, optional)
+	//  8. authentication blob (n bytes, optional)
+	MinLenClientHello = int(7)
+
+	// MinLenCompressed represents the minimum length
+	// of a compressed message envelope.
+	// Compressed message envelope structure:
+	//  1. message type (1 byte)
+	//  2. DEFLATE compressed message (n bytes, at least 1 byte)
+	MinLenCompressed = int(2)
+
+	// MinLenHeaders represents the minimum length
+	// of a header envelope.
+	// Header envelope structure:
+	//  1. message type (1 byte)
+	//  2. number of headers (1 byte, at least 1)
+	//  3. headers (n bytes), each header consisting of:
+	//     3.1. key length flag (1 byte, at least 1)
+	//     3.2. key (1 to 255 bytes, 7-bit ASCII encoded)
+	//     3.3. value length (2 bytes, little-endian)
+	//     3.4. value (0 to 65535 bytes)
+	//  4. enveloped signal, request or reply message (n bytes)
+	MinLenHeaders = int(7)
+
+	// MinLenRejectConf represents the minimum length
+	// of a connection rejection message.
+	// Connection rejection message structure:
+	//  1. message type (1 byte)
+	//  2. rejection reason (0 to 255 bytes, UTF8 encoded, optional)
+	MinLenRejectConf = int(1)
+
+	// MaxLenRejectReason represents the maximum length
+	// of the rejection reason of a connection rejection message
+	MaxLenRejectReason = int(255)
+
+	// MaxHeaders represents the maximum number of headers of a message
+	MaxHeaders = int(255)
+
+	// MaxLenHeaderKey represents the maximum length of a header key
+	MaxLenHeaderKey = int(255)
+
+	// MaxLenHeaderValue represents the maximum length of a header value
+	MaxLenHeaderValue = int(65535)
+)
+
+const (
+	// SessionCodecJSON identifies the JSON session codec used to encode
+	// session objects in session creation notifications and session
+	// restoration replies. It's the only codec supported by protocol
+	// version 2.0
+	SessionCodecJSON = byte(0)
+
+	// SessionCodecBinary identifies the compact binary session codec
+	SessionCodecBinary = byte(1)
 )
 
 const (
@@ -157,6 +245,11 @@ const (
 	// server right after the handshake and includes the server configurations
 	MsgAcceptConf = byte(23)
 
+	// MsgRejectConf is a connection rejection push-message sent only by the
+	// server instead of MsgAcceptConf when the connection is rejected during
+	// the handshake and includes the rejection reason
+	MsgRejectConf = byte(24)
+
 	// CLIENT
 
 	// MsgRequestCloseSession is session closure command sent only by the client to
@@ -172,6 +265,25 @@ const (
 	// down on read timeout
 	MsgHeartbeat = byte(33)
 
+	// MsgClientHello is sent only by the client right after the connection
+	// is established and before the server configuration is received when the
+	// server requires it. It's used to negotiate the protocol version and
+	// capabilities and carries the client name and an authentication blob
+	MsgClientHello = byte(34)
+
+	// ENVELOPE
+
+	// MsgCompressed is an envelope wrapping a DEFLATE compressed message.
+	// It's sent by both the client and the server if compression
+	// was negotiated during the handshake (see CapabilityCompression)
+	MsgCompressed = byte(48)
+
+	// MsgHeaders is an envelope prepending a key/value header section
+	// to a signal, request or reply message. It's optional and only sent by
+	// endpoints attaching headers to a message, which is why endpoints not
+	// supporting headers remain compatible as long as they don't receive any
+	MsgHeaders = byte(49)
+
 	// SIGNAL
 
 	// Signals are sent by both the client and the server
@@ -213,6 +325,19 @@ const (
 	MsgReplyUtf16 = byte(193)
 )
 
+const (
+	// SessionClosureUnspecified represents an unspecified session closure
+	// reason. It's never transmitted, a session closure notification without
+	// a reason byte is considered to be of unspecified reason
+	SessionClosureUnspecified = byte(0)
+
+	// SessionClosureEvicted indicates that the session was closed for this
+	// connection because another connection of the same session took over
+	// its slot after the maximum number of concurrent session connections
+	// was reached
+	SessionClosureEvicted = byte(1)
+)
+
 // ServerConfiguration represents the MsgAcceptConf payload data
 type ServerConfiguration struct {
 	MajorProtocolVersion byte
@@ -220,6 +345,48 @@ type ServerConfiguration struct {
 	SubProtocolName      []byte
 	ReadTimeout          time.Duration
 	MessageBufferSize    uint32
+
+	// SessionCodec identifies the codec used to encode session objects.
+	// It's only transmitted since protocol version 2.1 and is always
+	// SessionCodecJSON for older versions
+	SessionCodec byte
+
+	// Capabilities represents the capabilities negotiated with the client.
+	// It's only transmitted since protocol version 2.2
+	Capabilities Capabilities
+}
+
+// hasSessionCodec returns true if the server configuration message of the
+// given protocol version carries the session codec identifier
+func hasSessionCodec(majorVersion, minorVersion byte) bool {
+	return majorVersion == 2 && minorVersion >= 1
+}
+
+// hasCapabilities returns true if the server configuration message of the
+// given protocol version carries the negotiated capabilities
+func hasCapabilities(majorVersion, minorVersion byte) bool {
+	return majorVersion == 2 && minorVersion >= 2
+}
+
+// ClientHello represents the contents of a client hello message
+type ClientHello struct {
+	// MajorProtocolVersion defines the major protocol version
+	// supported by the client
+	MajorProtocolVersion byte
+
+	// MinMinorProtocolVersion and MaxMinorProtocolVersion define the range
+	// of minor protocol versions supported by the client
+	MinMinorProtocolVersion byte
+	MaxMinorProtocolVersion byte
+
+	// Capabilities represents the capabilities supported by the client
+	Capabilities Capabilities
+
+	// ClientName identifies the client library and its version
+	ClientName []byte
+
+	// AuthBlob carries arbitrary authentication data
+	AuthBlob []byte
 }
 
 // Message represents a non-thread-safe WebWire protocol message
@@ -234,6 +401,30 @@ type Message struct {
 	// ServerConfiguration is only initialized for MsgAcceptConf type messages
 	ServerConfiguration ServerConfiguration
 
+	// SessionClosureReason is only initialized
+	// for MsgNotifySessionClosed type messages
+	SessionClosureReason byte
+
+	// ClientHello is only initialized for MsgClientHello type messages
+	ClientHello ClientHello
+
+	// Compressed is true if the message was received wrapped
+	// in a compressed message envelope
+	Compressed bool
+
+	// Headers represents the headers of the message, it's only initialized
+	// for messages received wrapped in a header envelope
+	Headers []Header
+
+	// headerData holds the header section
+	// the keys and values of Headers are referring to
+	headerData []byte
+
+	// OnRaw is called with the raw message data before it's parsed
+	// if it's not nil. It's used for capturing traffic and must neither
+	// modify nor retain the data
+	OnRaw func(raw []byte)
+
 	onClose func()
 }
 
@@ -295,6 +486,19 @@ func (msg *Message) PayloadUtf8() ([]byte, error) {
 	return msg.MsgPayload.Utf8()
 }
 
+// Header implements the Message interface
+func (msg *Message) Header(key string) []byte {
+	if msg.MsgBuffer.IsEmpty() {
+		panic("read after close")
+	}
+	for i := range msg.Headers {
+		if string(msg.Headers[i].Key) == key {
+			return msg.Headers[i].Value
+		}
+	}
+	return nil
+}
+
 // Close implements the Message interface
 func (msg *Message) Close() {
 	if msg.MsgBuffer.IsEmpty() {
@@ -308,6 +512,10 @@ func (msg *Message) Close() {
 	msg.MsgName = nil
 	msg.MsgPayload = pld.Payload{}
 	msg.ServerConfiguration = ServerConfiguration{}
+	msg.SessionClosureReason = SessionClosureUnspecified
+	msg.ClientHello = ClientHello{}
+	msg.Compressed = false
+	msg.Headers = msg.Headers[:0]
 
 	// Call closure callback
 	msg.onClose()
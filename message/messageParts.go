@@ -3,6 +3,8 @@ package message
 var msgTypeHeartbeat = []byte{MsgHeartbeat}
 var msgTypeSessionCreated = []byte{MsgNotifySessionCreated}
 var msgTypeSessionClosed = []byte{MsgNotifySessionClosed}
+var msgTypeRejectConf = []byte{MsgRejectConf}
+var msgTypeHeaders = []byte{MsgHeaders}
 
 var msgTypeSignalBinary = []byte{MsgSignalBinary}
 var msgTypeSignalUtf8 = []byte{MsgSignalUtf8}
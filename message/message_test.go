@@ -129,3 +129,14 @@ func TestRequiresReplyRequestUtf16(t *testing.T) {
 		"Expected a UTF16 request message to require a reply",
 	)
 }
+
+// TestTypeName tests naming message types
+func TestTypeName(t *testing.T) {
+	require.Equal(
+		t,
+		"MsgReplyMaxSessConnsReached",
+		message.TypeName(message.MsgReplyMaxSessConnsReached),
+	)
+	require.Equal(t, "MsgRequestUtf16", message.TypeName(message.MsgRequestUtf16))
+	require.Equal(t, "MsgUnknown(200)", message.TypeName(200))
+}
@@ -9,7 +9,36 @@ import (
 // NewAcceptConfMessage composes a server configuration message and writes it to the
 // given buffer
 func NewAcceptConfMessage(conf ServerConfiguration) ([]byte, error) {
-	buf := make([]byte, MinLenAcceptConf+len(conf.SubProtocolName))
+	headerLen := MinLenAcceptConf
+	if hasCapabilities(
+		conf.MajorProtocolVersion,
+		conf.MinorProtocolVersion,
+	) {
+		headerLen = MinLenAcceptConfV22
+	} else if conf.Capabilities != 0 {
+		return nil, fmt.Errorf(
+			"capabilities not supported by protocol version %d.%d",
+			conf.MajorProtocolVersion,
+			conf.MinorProtocolVersion,
+		)
+	}
+	if hasSessionCodec(
+		conf.MajorProtocolVersion,
+		conf.MinorProtocolVersion,
+	) {
+		if headerLen < MinLenAcceptConfV21 {
+			headerLen = MinLenAcceptConfV21
+		}
+	} else if conf.SessionCodec != SessionCodecJSON {
+		return nil, fmt.Errorf(
+			"session codec (%d) not supported by protocol version %d.%d",
+			conf.SessionCodec,
+			conf.MajorProtocolVersion,
+			conf.MinorProtocolVersion,
+		)
+	}
+
+	buf := make([]byte, headerLen+len(conf.SubProtocolName))
 
 	buf[0] = byte(MsgAcceptConf)
 	buf[1] = byte(conf.MajorProtocolVersion)
@@ -31,7 +60,14 @@ func NewAcceptConfMessage(conf ServerConfiguration) ([]byte, error) {
 	binary.LittleEndian.PutUint32(buf[3:7], uint32(readTimeoutMs))
 	binary.LittleEndian.PutUint32(buf[7:11], conf.MessageBufferSize)
 
-	copy(buf[11:], conf.SubProtocolName)
+	if headerLen >= MinLenAcceptConfV21 {
+		buf[11] = conf.SessionCodec
+	}
+	if headerLen >= MinLenAcceptConfV22 {
+		binary.LittleEndian.PutUint16(buf[12:14], uint16(conf.Capabilities))
+	}
+
+	copy(buf[headerLen:], conf.SubProtocolName)
 
 	return buf, nil
 }
@@ -13,14 +13,45 @@ func (msg *Message) parse() (parsedMsgType bool, err error) {
 	if msg.MsgBuffer.IsEmpty() {
 		return false, nil
 	}
+	if msg.OnRaw != nil {
+		msg.OnRaw(msg.MsgBuffer.Data())
+	}
 	var payloadEncoding pld.Encoding
 	msgType := msg.MsgBuffer.buf[0:1][0]
 
+	// Unwrap compressed messages
+	msg.Compressed = false
+	if msgType == MsgCompressed {
+		if err := msg.decompress(); err != nil {
+			return false, err
+		}
+		msgType = msg.MsgBuffer.buf[0:1][0]
+		if msgType == MsgCompressed {
+			return false, errors.New("nested compressed message envelope")
+		}
+	}
+
+	// Unwrap header envelopes
+	msg.Headers = msg.Headers[:0]
+	if msgType == MsgHeaders {
+		if err := msg.unwrapHeaders(); err != nil {
+			return false, err
+		}
+		msgType = msg.MsgBuffer.buf[0:1][0]
+	}
+
 	switch msgType {
 
 	// Server Configuration
 	case MsgAcceptConf:
 		err = msg.parseAcceptConf()
+	case MsgRejectConf:
+		payloadEncoding = pld.Utf8
+		err = msg.parseRejectConf()
+
+	// Client hello
+	case MsgClientHello:
+		err = msg.parseClientHello()
 
 	// Heartbeat
 	case MsgHeartbeat:
@@ -13,19 +13,45 @@ func (msg *Message) parseAcceptConf() error {
 	}
 	dat := msg.MsgBuffer.Data()
 
+	majorVersion := dat[1:2][0]
+	minorVersion := dat[2:3][0]
+
+	// Read the session codec identifier if supported by the protocol version
+	headerLen := MinLenAcceptConf
+	sessionCodec := SessionCodecJSON
+	if hasSessionCodec(majorVersion, minorVersion) {
+		if msg.MsgBuffer.len < MinLenAcceptConfV21 {
+			return errors.New("invalid msg length, too short")
+		}
+		headerLen = MinLenAcceptConfV21
+		sessionCodec = dat[11]
+	}
+
+	// Read the negotiated capabilities if supported by the protocol version
+	capabilities := Capabilities(0)
+	if hasCapabilities(majorVersion, minorVersion) {
+		if msg.MsgBuffer.len < MinLenAcceptConfV22 {
+			return errors.New("invalid msg length, too short")
+		}
+		headerLen = MinLenAcceptConfV22
+		capabilities = Capabilities(binary.LittleEndian.Uint16(dat[12:14]))
+	}
+
 	subProtocolName := []byte(nil)
-	if msg.MsgBuffer.len > MinLenAcceptConf {
-		subProtocolName = dat[11:]
+	if msg.MsgBuffer.len > headerLen {
+		subProtocolName = dat[headerLen:]
 	}
 
 	msg.ServerConfiguration = ServerConfiguration{
-		MajorProtocolVersion: dat[1:2][0],
-		MinorProtocolVersion: dat[2:3][0],
+		MajorProtocolVersion: majorVersion,
+		MinorProtocolVersion: minorVersion,
 		ReadTimeout: time.Duration(
 			binary.LittleEndian.Uint32(dat[3:7]),
 		) * time.Millisecond,
 		MessageBufferSize: binary.LittleEndian.Uint32(dat[7:11]),
 		SubProtocolName:   subProtocolName,
+		SessionCodec:      sessionCodec,
+		Capabilities:      capabilities,
 	}
 	return nil
 }
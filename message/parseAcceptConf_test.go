@@ -65,3 +65,80 @@ func TestMsgParseAcceptConf(t *testing.T) {
 	require.Equal(t, pld.Payload{}, actual.MsgPayload)
 	require.Equal(t, srvConf, actual.ServerConfiguration)
 }
+
+// TestMsgParseAcceptConfV21 tests parsing of server configuration messages
+// of protocol version 2.1 carrying the session codec identifier
+func TestMsgParseAcceptConfV21(t *testing.T) {
+	srvConf := message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: 1,
+		ReadTimeout:          11 * time.Second,
+		MessageBufferSize:    8192,
+		SubProtocolName:      []byte("test - sub-protocol name"),
+		SessionCodec:         message.SessionCodecBinary,
+	}
+
+	// Compose encoded message
+	buf, err := message.NewAcceptConfMessage(srvConf)
+	require.NoError(t, err)
+	require.Len(t, buf, message.MinLenAcceptConfV21+len(srvConf.SubProtocolName))
+
+	// Parse
+	actual := tryParseNoErr(t, buf)
+
+	// Compare
+	require.Equal(t, message.MsgAcceptConf, actual.MsgType)
+	require.Equal(t, srvConf, actual.ServerConfiguration)
+}
+
+// TestMsgParseAcceptConfV22 tests parsing of server configuration messages
+// of protocol version 2.2 carrying the negotiated capabilities
+func TestMsgParseAcceptConfV22(t *testing.T) {
+	srvConf := message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: 2,
+		ReadTimeout:          11 * time.Second,
+		MessageBufferSize:    8192,
+		SubProtocolName:      []byte("test - sub-protocol name"),
+		SessionCodec:         message.SessionCodecBinary,
+		Capabilities:         message.CapabilityCompression,
+	}
+
+	// Compose encoded message
+	buf, err := message.NewAcceptConfMessage(srvConf)
+	require.NoError(t, err)
+	require.Len(t, buf, message.MinLenAcceptConfV22+len(srvConf.SubProtocolName))
+
+	// Parse
+	actual := tryParseNoErr(t, buf)
+
+	// Compare
+	require.Equal(t, message.MsgAcceptConf, actual.MsgType)
+	require.Equal(t, srvConf, actual.ServerConfiguration)
+}
+
+// TestMsgNewAcceptConfUnsupportedCapabilities tests composing server
+// configuration messages of protocol version 2.1 carrying capabilities
+func TestMsgNewAcceptConfUnsupportedCapabilities(t *testing.T) {
+	_, err := message.NewAcceptConfMessage(message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: 1,
+		ReadTimeout:          11 * time.Second,
+		MessageBufferSize:    8192,
+		Capabilities:         message.CapabilityCompression,
+	})
+	require.Error(t, err)
+}
+
+// TestMsgNewAcceptConfUnsupportedSessionCodec tests composing server
+// configuration messages of protocol version 2.0 using a non-JSON session codec
+func TestMsgNewAcceptConfUnsupportedSessionCodec(t *testing.T) {
+	_, err := message.NewAcceptConfMessage(message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: 0,
+		ReadTimeout:          11 * time.Second,
+		MessageBufferSize:    8192,
+		SessionCodec:         message.SessionCodecBinary,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package message
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// parseClientHello parses MsgClientHello messages
+func (msg *Message) parseClientHello() error {
+	if msg.MsgBuffer.len < MinLenClientHello {
+		return errors.New("invalid msg length, too short")
+	}
+	dat := msg.MsgBuffer.Data()
+
+	clientNameLen := int(dat[6])
+	if msg.MsgBuffer.len < MinLenClientHello+clientNameLen {
+		return errors.New(
+			"invalid msg, client name length flag too big",
+		)
+	}
+
+	var clientName []byte
+	if clientNameLen > 0 {
+		clientName = dat[MinLenClientHello : MinLenClientHello+clientNameLen]
+	}
+
+	var authBlob []byte
+	if msg.MsgBuffer.len > MinLenClientHello+clientNameLen {
+		authBlob = dat[MinLenClientHello+clientNameLen:]
+	}
+
+	msg.ClientHello = ClientHello{
+		MajorProtocolVersion:    dat[1],
+		MinMinorProtocolVersion: dat[2],
+		MaxMinorProtocolVersion: dat[3],
+		Capabilities:            Capabilities(binary.LittleEndian.Uint16(dat[4:6])),
+		ClientName:              clientName,
+		AuthBlob:                authBlob,
+	}
+
+	if msg.ClientHello.MinMinorProtocolVersion >
+		msg.ClientHello.MaxMinorProtocolVersion {
+		return errors.New("invalid msg, inverted protocol version range")
+	}
+
+	return nil
+}
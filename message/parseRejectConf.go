@@ -0,0 +1,22 @@
+package message
+
+import (
+	"errors"
+
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// parseRejectConf parses MsgRejectConf messages
+// writing the rejection reason to the UTF8 encoded payload
+func (msg *Message) parseRejectConf() error {
+	if msg.MsgBuffer.len > MinLenRejectConf+MaxLenRejectReason {
+		return errors.New("invalid msg length, too long")
+	}
+
+	msg.MsgPayload = pld.Payload{
+		Encoding: pld.Utf8,
+		Data:     msg.MsgBuffer.Data()[MinLenRejectConf:],
+	}
+
+	return nil
+}
@@ -72,5 +72,10 @@ func (msg *Message) parseRequestUtf16() error {
 		}
 	}
 
+	// Reject malformed UTF16 payloads
+	if err := pld.ValidateUtf16(msg.MsgPayload.Data); err != nil {
+		return fmt.Errorf("invalid request message payload: %s", err)
+	}
+
 	return nil
 }
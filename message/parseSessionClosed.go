@@ -4,10 +4,22 @@ import "errors"
 
 // parseSessionClosed parses MsgNotifySessionClosed messages
 func (msg *Message) parseSessionClosed() error {
-	if msg.MsgBuffer.len != MinLenNotifySessionClosed {
-		return errors.New(
-			"invalid session closure notification message, too short",
-		)
+	switch msg.MsgBuffer.len {
+	case MinLenNotifySessionClosed:
+		return nil
+	case MinLenNotifySessionClosed + 1:
+		// Read the optional closure reason which must not be unspecified
+		reason := msg.MsgBuffer.Data()[1]
+		if reason == SessionClosureUnspecified {
+			return errors.New(
+				"invalid session closure notification message, " +
+					"unspecified closure reason",
+			)
+		}
+		msg.SessionClosureReason = reason
+		return nil
 	}
-	return nil
+	return errors.New(
+		"invalid session closure notification message, too long",
+	)
 }
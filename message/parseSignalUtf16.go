@@ -58,5 +58,11 @@ func (msg *Message) parseSignalUtf16() error {
 			Data: dat[2:],
 		}
 	}
+
+	// Reject malformed UTF16 payloads
+	if err := pld.ValidateUtf16(msg.MsgPayload.Data); err != nil {
+		return fmt.Errorf("invalid signal message payload: %s", err)
+	}
+
 	return nil
 }
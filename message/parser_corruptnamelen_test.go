@@ -138,3 +138,13 @@ func TestMsgParseSignalUtf16CorruptNameLenFlag(t *testing.T) {
 		"Expected Parse to return an error due to corrupt name length flag",
 	)
 }
+
+// TestMsgParseClientHelloCorruptNameLenFlag tests parsing of a client hello
+// message with a client name length flag exceeding the message length
+func TestMsgParseClientHelloCorruptNameLenFlag(t *testing.T) {
+	encoded := []byte{message.MsgClientHello, 2, 0, 2, 0, 0, 255}
+	encoded = append(encoded, []byte("test")...)
+
+	_, err := tryParse(t, encoded)
+	require.Error(t, err)
+}
@@ -106,3 +106,60 @@ func TestMsgParseSignalUtf16CorruptInput(t *testing.T) {
 		"Expected Parse to return an error due to corrupt input stream",
 	)
 }
+
+// TestMsgParseRequestUtf16UnpairedSurrogate tests parsing of a named
+// UTF16 encoded request with a payload containing an unpaired surrogate
+func TestMsgParseRequestUtf16UnpairedSurrogate(t *testing.T) {
+	id := genRndMsgIdentifier()
+	name := genRndName(1, 255)
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{message.MsgRequestUtf16}
+	// Add identifier
+	encoded = append(encoded, id[:]...)
+	// Add name length flag
+	encoded = append(encoded, byte(len(name)))
+	// Add name
+	encoded = append(encoded, []byte(name)...)
+	// Add header padding if necessary
+	if len(name)%2 != 0 {
+		encoded = append(encoded, byte(0))
+	}
+	// Add payload (high surrogate followed by a non-surrogate)
+	encoded = append(encoded, 0x3D, 0xD8, 0x41, 0x00)
+
+	// Parse
+	_, err := tryParse(t, encoded)
+	require.Error(t,
+		err,
+		"Expected Parse to return an error due to malformed UTF16 payload",
+	)
+}
+
+// TestMsgParseSignalUtf16UnpairedSurrogate tests parsing of a named
+// UTF16 encoded signal with a payload containing an unpaired surrogate
+func TestMsgParseSignalUtf16UnpairedSurrogate(t *testing.T) {
+	name := genRndName(1, 255)
+
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{message.MsgSignalUtf16}
+	// Add name length flag
+	encoded = append(encoded, byte(len(name)))
+	// Add name
+	encoded = append(encoded, []byte(name)...)
+	// Add header padding if necessary
+	if len(name)%2 != 0 {
+		encoded = append(encoded, byte(0))
+	}
+	// Add payload (leading low surrogate)
+	encoded = append(encoded, 0x00, 0xDE, 0x41, 0x00)
+
+	// Parse
+	_, err := tryParse(t, encoded)
+	require.Error(t,
+		err,
+		"Expected Parse to return an error due to malformed UTF16 payload",
+	)
+}
@@ -210,3 +210,20 @@ func TestMsgParseInvalidSpecialReplyTooShort(t *testing.T) {
 			"(too short: 8)",
 	)
 }
+
+// TestMsgParseInvalidClientHelloTooShort tests parsing of an invalid
+// client hello message which is too short to be considered valid
+func TestMsgParseInvalidClientHelloTooShort(t *testing.T) {
+	lenTooShort := message.MinLenClientHello - 1
+	invalidMessage := make([]byte, lenTooShort)
+
+	invalidMessage[0] = message.MsgClientHello
+
+	_, err := tryParse(t, invalidMessage)
+	require.Error(t,
+		err,
+		"Expected error while parsing invalid client hello message "+
+			"(too short: %d)",
+		lenTooShort,
+	)
+}
@@ -312,6 +312,84 @@ func TestMsgParseSessClosedSig(t *testing.T) {
 	require.Equal(t, message.ServerConfiguration{}, actual.ServerConfiguration)
 }
 
+// TestMsgParseSessClosedSigReason tests parsing of session closed signals
+// carrying a closure reason
+func TestMsgParseSessClosedSigReason(t *testing.T) {
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{message.MsgNotifySessionClosed}
+	// Add closure reason
+	encoded = append(encoded, message.SessionClosureEvicted)
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, message.MsgNotifySessionClosed, actual.MsgType)
+	require.Equal(t, message.SessionClosureEvicted, actual.SessionClosureReason)
+	require.Nil(t, actual.MsgName)
+	require.Equal(t, pld.Payload{}, actual.MsgPayload)
+}
+
+// TestMsgParseRejectConf tests parsing of connection rejection messages
+func TestMsgParseRejectConf(t *testing.T) {
+	// Compose encoded message
+	// Add type flag
+	encoded := []byte{message.MsgRejectConf}
+	// Add rejection reason
+	encoded = append(encoded, []byte("unauthorized")...)
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, message.MsgRejectConf, actual.MsgType)
+	require.Equal(t, pld.Utf8, actual.MsgPayload.Encoding)
+	require.Equal(t, []byte("unauthorized"), actual.MsgPayload.Data)
+	require.Nil(t, actual.MsgName)
+}
+
+// TestMsgParseClientHello tests parsing of client hello messages
+func TestMsgParseClientHello(t *testing.T) {
+	// Compose encoded message
+	encoded := []byte{
+		message.MsgClientHello,
+		2,    // Major protocol version
+		1,    // Min minor protocol version
+		2,    // Max minor protocol version
+		2, 0, // Capabilities
+		4, // Client name length
+	}
+	encoded = append(encoded, []byte("test")...)
+	encoded = append(encoded, []byte("token")...)
+
+	// Parse
+	actual := tryParseNoErr(t, encoded)
+
+	// Compare
+	require.Equal(t, message.MsgClientHello, actual.MsgType)
+	require.Equal(t, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MinMinorProtocolVersion: 1,
+		MaxMinorProtocolVersion: 2,
+		Capabilities:            message.CapabilityCompression,
+		ClientName:              []byte("test"),
+		AuthBlob:                []byte("token"),
+	}, actual.ClientHello)
+}
+
+// TestMsgParseClientHelloNoName tests parsing of client hello messages
+// without a client name and an authentication blob
+func TestMsgParseClientHelloNoName(t *testing.T) {
+	encoded := []byte{message.MsgClientHello, 2, 0, 2, 0, 0, 0}
+
+	actual := tryParseNoErr(t, encoded)
+
+	require.Equal(t, message.MsgClientHello, actual.MsgType)
+	require.Nil(t, actual.ClientHello.ClientName)
+	require.Nil(t, actual.ClientHello.AuthBlob)
+}
+
 // TestMsgParseHeartbeat tests parsing of heartbeat messages
 func TestMsgParseHeartbeat(t *testing.T) {
 	// Compose encoded message
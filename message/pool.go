@@ -6,3 +6,32 @@ type Pool interface {
 	// it's no longer needed
 	Get() *Message
 }
+
+// StatsPool defines the interface of message buffer pools
+// reporting their usage statistics
+type StatsPool interface {
+	Pool
+
+	// Stats returns the usage statistics of the pool
+	Stats() PoolStats
+}
+
+// PoolStats represents the usage statistics of a message buffer pool
+type PoolStats struct {
+	// BufferSize is the size of the pooled message buffers in bytes
+	BufferSize uint32
+
+	// Allocated is the number of message buffers allocated by the pool
+	Allocated uint64
+
+	// Acquired is the number of message buffers fetched from the pool
+	Acquired uint64
+
+	// Released is the number of message buffers put back into the pool
+	Released uint64
+}
+
+// InUse returns the number of message buffers currently in use
+func (stats PoolStats) InUse() uint64 {
+	return stats.Acquired - stats.Released
+}
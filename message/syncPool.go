@@ -1,30 +1,52 @@
 package message
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // SyncPool represents a thread-safe messageBuffer pool
 type SyncPool struct {
+	allocated  uint64
+	acquired   uint64
+	released   uint64
 	bufferSize uint32
 	pool       *sync.Pool
 }
 
 // NewSyncPool initializes a new sync.Pool based message buffer pool instance
 func NewSyncPool(bufferSize, prealloc uint32) *SyncPool {
-	pool := &sync.Pool{}
-	pool.New = func() interface{} {
+	syncPool := &SyncPool{
+		bufferSize: bufferSize,
+		pool:       &sync.Pool{},
+	}
+	syncPool.pool.New = func() interface{} {
+		atomic.AddUint64(&syncPool.allocated, 1)
 		msg := NewMessage(bufferSize)
 		msg.onClose = func() {
-			pool.Put(msg)
+			atomic.AddUint64(&syncPool.released, 1)
+			syncPool.pool.Put(msg)
 		}
 		return msg
 	}
-	return &SyncPool{
-		bufferSize: bufferSize,
-		pool:       pool,
-	}
+	return syncPool
 }
 
 // Get implements the Pool interface
 func (mbp *SyncPool) Get() *Message {
+	atomic.AddUint64(&mbp.acquired, 1)
 	return mbp.pool.Get().(*Message)
 }
+
+// Stats implements the StatsPool interface
+func (mbp *SyncPool) Stats() PoolStats {
+	return PoolStats{
+		BufferSize: mbp.bufferSize,
+		Allocated:  atomic.LoadUint64(&mbp.allocated),
+		Acquired:   atomic.LoadUint64(&mbp.acquired),
+		Released:   atomic.LoadUint64(&mbp.released),
+	}
+}
+
+// Make sure the sync pool implements the StatsPool interface
+var _ StatsPool = (*SyncPool)(nil)
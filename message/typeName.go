@@ -0,0 +1,41 @@
+package message
+
+import "fmt"
+
+// typeNames maps the message types to the names of their constants
+var typeNames = map[byte]string{
+	MsgReplyError:               "MsgReplyError",
+	MsgReplyShutdown:            "MsgReplyShutdown",
+	MsgReplyInternalError:       "MsgReplyInternalError",
+	MsgReplySessionNotFound:     "MsgReplySessionNotFound",
+	MsgReplyMaxSessConnsReached: "MsgReplyMaxSessConnsReached",
+	MsgReplySessionsDisabled:    "MsgReplySessionsDisabled",
+	MsgNotifySessionCreated:     "MsgNotifySessionCreated",
+	MsgNotifySessionClosed:      "MsgNotifySessionClosed",
+	MsgAcceptConf:               "MsgAcceptConf",
+	MsgRejectConf:               "MsgRejectConf",
+	MsgRequestCloseSession:      "MsgRequestCloseSession",
+	MsgRequestRestoreSession:    "MsgRequestRestoreSession",
+	MsgHeartbeat:                "MsgHeartbeat",
+	MsgClientHello:              "MsgClientHello",
+	MsgCompressed:               "MsgCompressed",
+	MsgHeaders:                  "MsgHeaders",
+	MsgSignalBinary:             "MsgSignalBinary",
+	MsgSignalUtf8:               "MsgSignalUtf8",
+	MsgSignalUtf16:              "MsgSignalUtf16",
+	MsgRequestBinary:            "MsgRequestBinary",
+	MsgRequestUtf8:              "MsgRequestUtf8",
+	MsgRequestUtf16:             "MsgRequestUtf16",
+	MsgReplyBinary:              "MsgReplyBinary",
+	MsgReplyUtf8:                "MsgReplyUtf8",
+	MsgReplyUtf16:               "MsgReplyUtf16",
+}
+
+// TypeName returns the name of the given message type's constant
+// such as "MsgReplyMaxSessConnsReached". Unknown types are named by number
+func TypeName(msgType byte) string {
+	if name, known := typeNames[msgType]; known {
+		return name
+	}
+	return fmt.Sprintf("MsgUnknown(%d)", msgType)
+}
@@ -0,0 +1,38 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateHeaders returns an error if the given headers can't be written
+// to a header envelope
+func ValidateHeaders(headers []Header) error {
+	if len(headers) < 1 {
+		return errors.New("header envelope requires at least one header")
+	}
+	if len(headers) > MaxHeaders {
+		return fmt.Errorf("too many headers: %d", len(headers))
+	}
+	for i := range headers {
+		key := headers[i].Key
+		if len(key) < 1 || len(key) > MaxLenHeaderKey {
+			return fmt.Errorf("unsupported header key length: %d", len(key))
+		}
+		if len(headers[i].Value) > MaxLenHeaderValue {
+			return fmt.Errorf(
+				"unsupported header value length: %d",
+				len(headers[i].Value),
+			)
+		}
+		for _, char := range key {
+			if char < 32 || char > 126 {
+				return fmt.Errorf(
+					"unsupported character in header key: %s",
+					string(char),
+				)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package message
+
+import "fmt"
+
+// ValidateSignalName returns an error if the given signal name contains
+// characters other than printable 7-bit ASCII characters
+func ValidateSignalName(name []byte) error {
+	for i := range name {
+		char := name[i]
+		if char < 32 || char > 126 {
+			return fmt.Errorf(
+				"unsupported character in signal name: %s",
+				string(char),
+			)
+		}
+	}
+	return nil
+}
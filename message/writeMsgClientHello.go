@@ -0,0 +1,51 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WriteMsgClientHello writes a client hello message to the given writer
+// closing it eventually
+func WriteMsgClientHello(writer io.WriteCloser, hello ClientHello) error {
+	if len(hello.ClientName) > 255 {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf(
+				"client name too long (%d bytes): %s",
+				len(hello.ClientName),
+				closeErr,
+			)
+		}
+		return fmt.Errorf(
+			"client name too long (%d bytes)",
+			len(hello.ClientName),
+		)
+	}
+
+	header := make([]byte, MinLenClientHello)
+	header[0] = MsgClientHello
+	header[1] = hello.MajorProtocolVersion
+	header[2] = hello.MinMinorProtocolVersion
+	header[3] = hello.MaxMinorProtocolVersion
+	binary.LittleEndian.PutUint16(header[4:6], uint16(hello.Capabilities))
+	header[6] = byte(len(hello.ClientName))
+
+	for _, part := range [][]byte{
+		header,
+		hello.ClientName,
+		hello.AuthBlob,
+	} {
+		if len(part) < 1 {
+			continue
+		}
+		if _, err := writer.Write(part); err != nil {
+			if closeErr := writer.Close(); closeErr != nil {
+				return fmt.Errorf("%s: %s", err, closeErr)
+			}
+			return err
+		}
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,58 @@
+package message
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteMsgHeaders writes a header envelope carrying the given headers to the
+// given writer. The signal, request or reply message to be enveloped must be
+// written to the same writer afterwards, which is why, unlike the other
+// writers, it only closes the writer in case of an error
+func WriteMsgHeaders(writer io.WriteCloser, headers []Header) error {
+	if initialErr := ValidateHeaders(headers); initialErr != nil {
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("%s: %s", initialErr, err)
+		}
+		return initialErr
+	}
+
+	// Write message type flag and number of headers
+	if _, err := writer.Write(msgTypeHeaders); err != nil {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf("%s: %s", err, closeErr)
+		}
+		return err
+	}
+	if _, err := writer.Write([]byte{byte(len(headers))}); err != nil {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf("%s: %s", err, closeErr)
+		}
+		return err
+	}
+
+	// Write headers
+	for i := range headers {
+		key := headers[i].Key
+		value := headers[i].Value
+		valueLen := [2]byte{byte(len(value)), byte(len(value) >> 8)}
+		for _, part := range [][]byte{
+			{byte(len(key))},
+			key,
+			valueLen[:],
+			value,
+		} {
+			if len(part) < 1 {
+				continue
+			}
+			if _, err := writer.Write(part); err != nil {
+				if closeErr := writer.Close(); closeErr != nil {
+					return fmt.Errorf("%s: %s", err, closeErr)
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
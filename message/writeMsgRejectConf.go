@@ -0,0 +1,39 @@
+package message
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteMsgRejectConf writes a connection rejection message including the
+// given rejection reason to the given writer closing it eventually
+func WriteMsgRejectConf(writer io.WriteCloser, reason []byte) error {
+	if len(reason) > MaxLenRejectReason {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf(
+				"rejection reason too long (%d bytes): %s",
+				len(reason),
+				closeErr,
+			)
+		}
+		return fmt.Errorf("rejection reason too long (%d bytes)", len(reason))
+	}
+
+	// Write message type flag
+	if _, err := writer.Write(msgTypeRejectConf); err != nil {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf("%s: %s", err, closeErr)
+		}
+		return err
+	}
+
+	// Write the rejection reason
+	if _, err := writer.Write(reason); err != nil {
+		if closeErr := writer.Close(); closeErr != nil {
+			return fmt.Errorf("%s: %s", err, closeErr)
+		}
+		return err
+	}
+
+	return writer.Close()
+}
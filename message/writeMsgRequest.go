@@ -68,6 +68,17 @@ func WriteMsgRequest(
 				return initialErr
 			}
 		}
+
+		// Validate UTF8 encoded payload
+		if payloadEncoding == pld.Utf8 {
+			if err := pld.ValidateUtf8(payloadData); err != nil {
+				initialErr := fmt.Errorf("invalid request payload: %s", err)
+				if err := writer.Close(); err != nil {
+					return fmt.Errorf("%s: %s", initialErr, err)
+				}
+				return initialErr
+			}
+		}
 	}
 
 	// Determine message type from payload encoding type
@@ -8,6 +8,19 @@ import (
 // WriteMsgNotifySessionClosed writes a session closure notification message to the
 // given writer closing it eventually
 func WriteMsgNotifySessionClosed(writer io.WriteCloser) error {
+	return WriteMsgNotifySessionClosedReason(
+		writer,
+		SessionClosureUnspecified,
+	)
+}
+
+// WriteMsgNotifySessionClosedReason writes a session closure notification
+// message including the closure reason to the given writer closing it
+// eventually. The reason is omitted if it's SessionClosureUnspecified
+func WriteMsgNotifySessionClosedReason(
+	writer io.WriteCloser,
+	reason byte,
+) error {
 	// Write message type flag
 	if _, err := writer.Write(msgTypeSessionClosed); err != nil {
 		if closeErr := writer.Close(); closeErr != nil {
@@ -16,5 +29,15 @@ func WriteMsgNotifySessionClosed(writer io.WriteCloser) error {
 		return err
 	}
 
+	// Write the closure reason (if any)
+	if reason != SessionClosureUnspecified {
+		if _, err := writer.Write([]byte{reason}); err != nil {
+			if closeErr := writer.Close(); closeErr != nil {
+				return fmt.Errorf("%s: %s", err, closeErr)
+			}
+			return err
+		}
+	}
+
 	return writer.Close()
 }
@@ -40,13 +40,17 @@ func WriteMsgSignal(
 	}
 
 	if safeMode {
-		for i := range name {
-			char := name[i]
-			if char < 32 || char > 126 {
-				initialErr := fmt.Errorf(
-					"unsupported character in request name: %s",
-					string(char),
-				)
+		if initialErr := ValidateSignalName(name); initialErr != nil {
+			if err := writer.Close(); err != nil {
+				return fmt.Errorf("%s: %s", initialErr, err)
+			}
+			return initialErr
+		}
+
+		// Validate UTF8 encoded payload
+		if payloadEncoding == pld.Utf8 {
+			if err := pld.ValidateUtf8(payloadData); err != nil {
+				initialErr := fmt.Errorf("invalid signal payload: %s", err)
 				if err := writer.Close(); err != nil {
 					return fmt.Errorf("%s: %s", initialErr, err)
 				}
@@ -424,6 +424,82 @@ func TestWriteMsgNotifySessionClosed(t *testing.T) {
 	require.True(t, writer.closed)
 }
 
+// TestWriteMsgNotifySessionClosedReason tests
+// WriteMsgNotifySessionClosedReason
+func TestWriteMsgNotifySessionClosedReason(t *testing.T) {
+	// Compose expected message
+	expected := []byte{
+		message.MsgNotifySessionClosed,
+		message.SessionClosureEvicted,
+	}
+
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgNotifySessionClosedReason(
+		writer,
+		message.SessionClosureEvicted,
+	))
+	require.Equal(t, expected, writer.buf)
+	require.True(t, writer.closed)
+}
+
+// TestWriteMsgRejectConf tests WriteMsgRejectConf
+func TestWriteMsgRejectConf(t *testing.T) {
+	// Compose expected message
+	expected := []byte{message.MsgRejectConf}
+	expected = append(expected, []byte("unauthorized")...)
+
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgRejectConf(
+		writer,
+		[]byte("unauthorized"),
+	))
+	require.Equal(t, expected, writer.buf)
+	require.True(t, writer.closed)
+}
+
+// TestWriteMsgRejectConfReasonTooLong tests WriteMsgRejectConf
+// with a rejection reason exceeding the maximum length
+func TestWriteMsgRejectConfReasonTooLong(t *testing.T) {
+	writer := &testWriter{}
+	require.Error(t, message.WriteMsgRejectConf(
+		writer,
+		make([]byte, message.MaxLenRejectReason+1),
+	))
+	require.Nil(t, writer.buf)
+	require.True(t, writer.closed)
+}
+
+// TestWriteMsgClientHello tests WriteMsgClientHello
+func TestWriteMsgClientHello(t *testing.T) {
+	// Compose expected message
+	expected := []byte{
+		message.MsgClientHello,
+		2,    // Major protocol version
+		0,    // Min minor protocol version
+		2,    // Max minor protocol version
+		3, 0, // Capabilities
+		4, // Client name length
+	}
+	expected = append(expected, []byte("test")...)
+	expected = append(expected, []byte("token")...)
+
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgClientHello(
+		writer,
+		message.ClientHello{
+			MajorProtocolVersion:    2,
+			MinMinorProtocolVersion: 0,
+			MaxMinorProtocolVersion: 2,
+			Capabilities: message.CapabilityFragmentation |
+				message.CapabilityCompression,
+			ClientName: []byte("test"),
+			AuthBlob:   []byte("token"),
+		},
+	))
+	require.Equal(t, expected, writer.buf)
+	require.True(t, writer.closed)
+}
+
 // TestWriteMsgHeartbeat tests WriteMsgHeartbeat
 func TestWriteMsgHeartbeat(t *testing.T) {
 	// Compose expected message
@@ -252,3 +252,34 @@ func TestWriteMsgReplyErrorCodeCharsetAboveAscii126(t *testing.T) {
 	require.True(t, writer.closed)
 	require.Nil(t, writer.buf)
 }
+
+// TestWriteMsgReqInvalidUtf8Payload tests WriteMsgRequest
+// with a malformed UTF8 encoded payload in safe mode
+func TestWriteMsgReqInvalidUtf8Payload(t *testing.T) {
+	writer := &testWriter{}
+	require.Error(t, message.WriteMsgRequest(
+		writer,
+		genRndMsgIdentifier(),
+		[]byte("name"),
+		pld.Utf8,
+		[]byte{0x41, 0xC3, 0x28},
+		true,
+	))
+	require.True(t, writer.closed)
+	require.Nil(t, writer.buf)
+}
+
+// TestWriteMsgSigInvalidUtf8Payload tests WriteMsgSignal
+// with a malformed UTF8 encoded payload in safe mode
+func TestWriteMsgSigInvalidUtf8Payload(t *testing.T) {
+	writer := &testWriter{}
+	require.Error(t, message.WriteMsgSignal(
+		writer,
+		[]byte("name"),
+		pld.Utf8,
+		[]byte{0x41, 0xC3, 0x28},
+		true,
+	))
+	require.True(t, writer.closed)
+	require.Nil(t, writer.buf)
+}
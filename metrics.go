@@ -0,0 +1,105 @@
+package webwire
+
+import "time"
+
+// Reasons of error replies reported to Metrics.ErrorReplySent
+const (
+	// ErrorReplyRequest represents a user-space request error (ErrRequest)
+	ErrorReplyRequest = "request"
+
+	// ErrorReplyInternal represents an internal server error
+	ErrorReplyInternal = "internal"
+
+	// ErrorReplyMaxSessConnsReached represents a session restoration request
+	// rejected due to the session reaching the maximum number of connections
+	ErrorReplyMaxSessConnsReached = "max_sess_conns_reached"
+
+	// ErrorReplySessionNotFound represents a session restoration request for
+	// an inexistent session
+	ErrorReplySessionNotFound = "session_not_found"
+
+	// ErrorReplySessionsDisabled represents a session related request
+	// rejected because sessions are disabled
+	ErrorReplySessionsDisabled = "sessions_disabled"
+
+	// ErrorReplyShutdown represents a request rejected due to the server
+	// shutting down
+	ErrorReplyShutdown = "shutdown"
+
+	// ErrorReplyRateLimitExceeded represents a request rejected due to
+	// exceeding a rate limit
+	ErrorReplyRateLimitExceeded = "rate_limit_exceeded"
+
+	// ErrorReplyProtocolError represents a request rejected due to
+	// violating the protocol
+	ErrorReplyProtocolError = "protocol_error"
+)
+
+// Metrics defines the interface of a server metrics collector.
+// All methods are called concurrently and must not block
+type Metrics interface {
+	// ConnectionOpened is called when a new connection was established
+	ConnectionOpened()
+
+	// ConnectionClosed is called when a connection was closed
+	ConnectionClosed()
+
+	// MessageReceived is called for every received message
+	MessageReceived()
+
+	// RequestHandled is called when a request handler returned
+	// either successfully or with an error
+	RequestHandled(latency time.Duration, failed bool)
+
+	// SignalHandled is called when a signal handler returned
+	SignalHandled(latency time.Duration)
+
+	// ErrorReplySent is called for every error reply sent to a client.
+	// The reason is one of the ErrorReply* constants
+	ErrorReplySent(reason string)
+
+	// ActiveSessions is called whenever the number of active sessions changed.
+	// It's called while the session registry is locked to keep the reports
+	// in order and must therefore not call back into the server
+	ActiveSessions(num int)
+
+	// BufferOverflow is called when an outgoing message exceeded
+	// the message buffer size
+	BufferOverflow()
+
+	// ProtocolViolation is called when a client sent a corrupt
+	// or unexpected message
+	ProtocolViolation()
+}
+
+// NoopMetrics implements the webwire.Metrics interface discarding all metrics.
+// It's the default metrics collector and can be embedded by custom
+// implementations only interested in certain metrics
+type NoopMetrics struct{}
+
+// ConnectionOpened implements the webwire.Metrics interface
+func (NoopMetrics) ConnectionOpened() {}
+
+// ConnectionClosed implements the webwire.Metrics interface
+func (NoopMetrics) ConnectionClosed() {}
+
+// MessageReceived implements the webwire.Metrics interface
+func (NoopMetrics) MessageReceived() {}
+
+// RequestHandled implements the webwire.Metrics interface
+func (NoopMetrics) RequestHandled(time.Duration, bool) {}
+
+// SignalHandled implements the webwire.Metrics interface
+func (NoopMetrics) SignalHandled(time.Duration) {}
+
+// ErrorReplySent implements the webwire.Metrics interface
+func (NoopMetrics) ErrorReplySent(string) {}
+
+// ActiveSessions implements the webwire.Metrics interface
+func (NoopMetrics) ActiveSessions(int) {}
+
+// BufferOverflow implements the webwire.Metrics interface
+func (NoopMetrics) BufferOverflow() {}
+
+// ProtocolViolation implements the webwire.Metrics interface
+func (NoopMetrics) ProtocolViolation() {}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// WriteTo renders the collected metrics in the Prometheus text exposition
+// format to the given writer
+func (col *Collector) WriteTo(writer io.Writer) (int64, error) {
+	out := &countingWriter{writer: bufio.NewWriter(writer)}
+
+	col.writeCounter(
+		out,
+		"connections_opened_total",
+		"Total number of established connections.",
+		atomic.LoadUint64(&col.connectionsOpened),
+	)
+	col.writeCounter(
+		out,
+		"connections_closed_total",
+		"Total number of closed connections.",
+		atomic.LoadUint64(&col.connectionsClosed),
+	)
+	col.writeHeader(
+		out,
+		"connections_active",
+		"gauge",
+		"Number of currently established connections.",
+	)
+	fmt.Fprintf(
+		out,
+		"%s_connections_active %d\n",
+		col.namespace,
+		int64(atomic.LoadUint64(&col.connectionsOpened))-
+			int64(atomic.LoadUint64(&col.connectionsClosed)),
+	)
+	col.writeCounter(
+		out,
+		"messages_received_total",
+		"Total number of received messages.",
+		atomic.LoadUint64(&col.messagesReceived),
+	)
+
+	col.writeHeader(
+		out,
+		"requests_total",
+		"counter",
+		"Total number of handled requests by outcome.",
+	)
+	fmt.Fprintf(
+		out,
+		"%s_requests_total{outcome=\"success\"} %d\n",
+		col.namespace,
+		atomic.LoadUint64(&col.requestsSucceeded),
+	)
+	fmt.Fprintf(
+		out,
+		"%s_requests_total{outcome=\"failure\"} %d\n",
+		col.namespace,
+		atomic.LoadUint64(&col.requestsFailed),
+	)
+	col.writeHistogram(
+		out,
+		"request_duration_seconds",
+		"Latency of the request handlers in seconds.",
+		col.requestDuration,
+	)
+
+	col.writeCounter(
+		out,
+		"signals_total",
+		"Total number of handled signals.",
+		atomic.LoadUint64(&col.signalsHandled),
+	)
+	col.writeHistogram(
+		out,
+		"signal_duration_seconds",
+		"Latency of the signal handlers in seconds.",
+		col.signalDuration,
+	)
+
+	col.writeHeader(
+		out,
+		"error_replies_total",
+		"counter",
+		"Total number of sent error replies by reason.",
+	)
+	reasons, counts := col.errorRepliesSnapshot()
+	for i, reason := range reasons {
+		fmt.Fprintf(
+			out,
+			"%s_error_replies_total{reason=%s} %d\n",
+			col.namespace,
+			strconv.Quote(reason),
+			counts[i],
+		)
+	}
+
+	col.writeHeader(
+		out,
+		"sessions_active",
+		"gauge",
+		"Number of currently active sessions.",
+	)
+	fmt.Fprintf(
+		out,
+		"%s_sessions_active %d\n",
+		col.namespace,
+		atomic.LoadInt64(&col.activeSessions),
+	)
+	col.writeCounter(
+		out,
+		"buffer_overflows_total",
+		"Total number of outgoing messages exceeding the message buffer.",
+		atomic.LoadUint64(&col.bufferOverflows),
+	)
+	col.writeCounter(
+		out,
+		"protocol_violations_total",
+		"Total number of corrupt or unexpected incoming messages.",
+		atomic.LoadUint64(&col.protocolViolations),
+	)
+
+	if out.err != nil {
+		return out.written, out.err
+	}
+	return out.written, out.writer.Flush()
+}
+
+func (col *Collector) writeHeader(
+	out io.Writer,
+	name string,
+	typ string,
+	help string,
+) {
+	fmt.Fprintf(out, "# HELP %s_%s %s\n", col.namespace, name, help)
+	fmt.Fprintf(out, "# TYPE %s_%s %s\n", col.namespace, name, typ)
+}
+
+func (col *Collector) writeCounter(
+	out io.Writer,
+	name string,
+	help string,
+	value uint64,
+) {
+	col.writeHeader(out, name, "counter", help)
+	fmt.Fprintf(out, "%s_%s %d\n", col.namespace, name, value)
+}
+
+func (col *Collector) writeHistogram(
+	out io.Writer,
+	name string,
+	help string,
+	hist *histogram,
+) {
+	col.writeHeader(out, name, "histogram", help)
+	buckets, count, sum := hist.snapshot()
+	for i, bound := range hist.bounds {
+		fmt.Fprintf(
+			out,
+			"%s_%s_bucket{le=\"%s\"} %d\n",
+			col.namespace,
+			name,
+			strconv.FormatFloat(bound, 'g', -1, 64),
+			buckets[i],
+		)
+	}
+	fmt.Fprintf(
+		out,
+		"%s_%s_bucket{le=\"+Inf\"} %d\n",
+		col.namespace,
+		name,
+		count,
+	)
+	fmt.Fprintf(
+		out,
+		"%s_%s_sum %s\n",
+		col.namespace,
+		name,
+		strconv.FormatFloat(sum, 'g', -1, 64),
+	)
+	fmt.Fprintf(out, "%s_%s_count %d\n", col.namespace, name, count)
+}
+
+// countingWriter counts the written bytes and retains the first write error
+type countingWriter struct {
+	writer  *bufio.Writer
+	written int64
+	err     error
+}
+
+// Write implements the io.Writer interface
+func (cw *countingWriter) Write(data []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.writer.Write(data)
+	cw.written += int64(n)
+	cw.err = err
+	return n, err
+}
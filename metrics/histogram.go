@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// histogram represents a thread safe cumulative histogram
+type histogram struct {
+	lock    sync.Mutex
+	bounds  []float64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// newHistogram creates a new histogram with the given bucket upper bounds
+func newHistogram(bounds []float64) *histogram {
+	sorted := make([]float64, len(bounds))
+	copy(sorted, bounds)
+	sort.Float64s(sorted)
+	return &histogram{
+		bounds:  sorted,
+		buckets: make([]uint64, len(bounds)),
+	}
+}
+
+// observe records the given value
+func (hist *histogram) observe(value float64) {
+	hist.lock.Lock()
+	for i, bound := range hist.bounds {
+		if value <= bound {
+			hist.buckets[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+	hist.lock.Unlock()
+}
+
+// snapshot returns a consistent copy of the histogram state
+func (hist *histogram) snapshot() (buckets []uint64, count uint64, sum float64) {
+	hist.lock.Lock()
+	buckets = make([]uint64, len(hist.buckets))
+	copy(buckets, hist.buckets)
+	count = hist.count
+	sum = hist.sum
+	hist.lock.Unlock()
+	return buckets, count, sum
+}
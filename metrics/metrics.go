@@ -0,0 +1,139 @@
+// Package metrics implements an in-process webwire.Metrics collector
+// exposing the collected metrics in the Prometheus text exposition format
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// DefaultBuckets defines the default upper bounds (in seconds)
+// of the latency histogram buckets
+var DefaultBuckets = []float64{
+	.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+// Collector implements the webwire.Metrics interface collecting metrics in
+// memory. It implements the http.Handler interface as well rendering the
+// collected metrics in the Prometheus text exposition format
+type Collector struct {
+	// Counters and gauges must be accessed atomically and are kept at the top
+	// of the struct to guarantee 64-bit alignment
+	connectionsOpened  uint64
+	connectionsClosed  uint64
+	messagesReceived   uint64
+	requestsSucceeded  uint64
+	requestsFailed     uint64
+	signalsHandled     uint64
+	bufferOverflows    uint64
+	protocolViolations uint64
+	activeSessions     int64
+
+	namespace        string
+	requestDuration  *histogram
+	signalDuration   *histogram
+	errorRepliesLock sync.Mutex
+	errorReplies     map[string]uint64
+}
+
+// New creates a new metrics collector. All metric names are prefixed with the
+// given namespace ("webwire" if empty). The latency histograms use the given
+// bucket upper bounds in seconds (DefaultBuckets if nil)
+func New(namespace string, buckets []float64) *Collector {
+	if namespace == "" {
+		namespace = "webwire"
+	}
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &Collector{
+		namespace:       namespace,
+		requestDuration: newHistogram(buckets),
+		signalDuration:  newHistogram(buckets),
+		errorReplies:    make(map[string]uint64),
+	}
+}
+
+// ConnectionOpened implements the webwire.Metrics interface
+func (col *Collector) ConnectionOpened() {
+	atomic.AddUint64(&col.connectionsOpened, 1)
+}
+
+// ConnectionClosed implements the webwire.Metrics interface
+func (col *Collector) ConnectionClosed() {
+	atomic.AddUint64(&col.connectionsClosed, 1)
+}
+
+// MessageReceived implements the webwire.Metrics interface
+func (col *Collector) MessageReceived() {
+	atomic.AddUint64(&col.messagesReceived, 1)
+}
+
+// RequestHandled implements the webwire.Metrics interface
+func (col *Collector) RequestHandled(latency time.Duration, failed bool) {
+	if failed {
+		atomic.AddUint64(&col.requestsFailed, 1)
+	} else {
+		atomic.AddUint64(&col.requestsSucceeded, 1)
+	}
+	col.requestDuration.observe(latency.Seconds())
+}
+
+// SignalHandled implements the webwire.Metrics interface
+func (col *Collector) SignalHandled(latency time.Duration) {
+	atomic.AddUint64(&col.signalsHandled, 1)
+	col.signalDuration.observe(latency.Seconds())
+}
+
+// ErrorReplySent implements the webwire.Metrics interface
+func (col *Collector) ErrorReplySent(reason string) {
+	col.errorRepliesLock.Lock()
+	col.errorReplies[reason]++
+	col.errorRepliesLock.Unlock()
+}
+
+// ActiveSessions implements the webwire.Metrics interface
+func (col *Collector) ActiveSessions(num int) {
+	atomic.StoreInt64(&col.activeSessions, int64(num))
+}
+
+// BufferOverflow implements the webwire.Metrics interface
+func (col *Collector) BufferOverflow() {
+	atomic.AddUint64(&col.bufferOverflows, 1)
+}
+
+// ProtocolViolation implements the webwire.Metrics interface
+func (col *Collector) ProtocolViolation() {
+	atomic.AddUint64(&col.protocolViolations, 1)
+}
+
+// errorRepliesSnapshot returns a copy of the error reply counters
+// sorted by reason
+func (col *Collector) errorRepliesSnapshot() (reasons []string, counts []uint64) {
+	col.errorRepliesLock.Lock()
+	reasons = make([]string, 0, len(col.errorReplies))
+	for reason := range col.errorReplies {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	counts = make([]uint64, len(reasons))
+	for i, reason := range reasons {
+		counts[i] = col.errorReplies[reason]
+	}
+	col.errorRepliesLock.Unlock()
+	return reasons, counts
+}
+
+// ServeHTTP implements the http.Handler interface
+func (col *Collector) ServeHTTP(resp http.ResponseWriter, _ *http.Request) {
+	resp.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	col.WriteTo(resp)
+}
+
+// Make sure the collector implements the webwire.Metrics interface
+var _ wwr.Metrics = (*Collector)(nil)
@@ -0,0 +1,93 @@
+package metrics_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectorExposition tests rendering the collected metrics
+// in the Prometheus text exposition format
+func TestCollectorExposition(t *testing.T) {
+	col := metrics.New("", []float64{0.01, 0.1})
+
+	col.ConnectionOpened()
+	col.ConnectionOpened()
+	col.ConnectionClosed()
+	col.MessageReceived()
+	col.RequestHandled(5*time.Millisecond, false)
+	col.RequestHandled(50*time.Millisecond, true)
+	col.SignalHandled(time.Second)
+	col.ErrorReplySent(wwr.ErrorReplyRequest)
+	col.ErrorReplySent(wwr.ErrorReplyRequest)
+	col.ErrorReplySent(wwr.ErrorReplyInternal)
+	col.ActiveSessions(3)
+	col.BufferOverflow()
+	col.ProtocolViolation()
+
+	recorder := httptest.NewRecorder()
+	col.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(
+		t,
+		"text/plain; version=0.0.4",
+		recorder.Header().Get("Content-Type"),
+	)
+
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE webwire_connections_opened_total counter\n",
+		"webwire_connections_opened_total 2\n",
+		"webwire_connections_closed_total 1\n",
+		"webwire_connections_active 1\n",
+		"webwire_messages_received_total 1\n",
+		`webwire_requests_total{outcome="success"} 1` + "\n",
+		`webwire_requests_total{outcome="failure"} 1` + "\n",
+		"# TYPE webwire_request_duration_seconds histogram\n",
+		`webwire_request_duration_seconds_bucket{le="0.01"} 1` + "\n",
+		`webwire_request_duration_seconds_bucket{le="0.1"} 2` + "\n",
+		`webwire_request_duration_seconds_bucket{le="+Inf"} 2` + "\n",
+		"webwire_request_duration_seconds_sum 0.055\n",
+		"webwire_request_duration_seconds_count 2\n",
+		"webwire_signals_total 1\n",
+		`webwire_signal_duration_seconds_bucket{le="0.1"} 0` + "\n",
+		`webwire_signal_duration_seconds_bucket{le="+Inf"} 1` + "\n",
+		`webwire_error_replies_total{reason="internal"} 1` + "\n",
+		`webwire_error_replies_total{reason="request"} 2` + "\n",
+		"webwire_sessions_active 3\n",
+		"webwire_buffer_overflows_total 1\n",
+		"webwire_protocol_violations_total 1\n",
+	} {
+		require.Contains(t, body, line)
+	}
+}
+
+// TestCollectorNamespace tests prefixing metric names with a custom namespace
+func TestCollectorNamespace(t *testing.T) {
+	col := metrics.New("myapp", nil)
+	col.ConnectionOpened()
+
+	recorder := httptest.NewRecorder()
+	col.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Contains(t, recorder.Body.String(), "myapp_connections_opened_total 1\n")
+	require.NotContains(t, recorder.Body.String(), "webwire_")
+}
+
+// TestCollectorUnsortedBuckets tests passing unsorted bucket bounds
+func TestCollectorUnsortedBuckets(t *testing.T) {
+	col := metrics.New("", []float64{1, 0.1})
+	col.RequestHandled(50*time.Millisecond, false)
+
+	recorder := httptest.NewRecorder()
+	col.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	require.Contains(
+		t,
+		body,
+		`webwire_request_duration_seconds_bucket{le="0.1"} 1`+"\n"+
+			`webwire_request_duration_seconds_bucket{le="1"} 1`+"\n",
+	)
+}
@@ -32,16 +32,25 @@ func NewServer(
 		sessionsEnabled = true
 	}
 
+	// Advertise protocol version 2.1 only when a session codec other than JSON
+	// is used to stay compatible with 2.0 clients. Clients sending a client
+	// hello negotiate the highest mutually supported version instead
+	minorProtocolVersion := byte(0)
+	if opts.SessionCodec.Identifier() != message.SessionCodecJSON {
+		minorProtocolVersion = 1
+	}
+
+	configuration := message.ServerConfiguration{
+		MajorProtocolVersion: 2,
+		MinorProtocolVersion: minorProtocolVersion,
+		ReadTimeout:          opts.ReadTimeout,
+		MessageBufferSize:    opts.MessageBufferSize,
+		SubProtocolName:      opts.SubProtocolName,
+		SessionCodec:         opts.SessionCodec.Identifier(),
+	}
+
 	// Prepare the configuration push message for the webwire accept handshake
-	configMsg, err := message.NewAcceptConfMessage(
-		message.ServerConfiguration{
-			MajorProtocolVersion: 2,
-			MinorProtocolVersion: 0,
-			ReadTimeout:          opts.ReadTimeout,
-			MessageBufferSize:    opts.MessageBufferSize,
-			SubProtocolName:      opts.SubProtocolName,
-		},
-	)
+	configMsg, err := message.NewAcceptConfMessage(configuration)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"couldn't initialize server configuration-push message: %s",
@@ -56,32 +65,57 @@ func NewServer(
 		sessionManager:    opts.SessionManager,
 		sessionKeyGen:     opts.SessionKeyGenerator,
 		sessionInfoParser: opts.SessionInfoParser,
+		sessionCodec:      opts.SessionCodec,
 		addr:              url.URL{},
 		options:           opts,
+		configuration:     configuration,
 		configMsg:         configMsg,
 		shutdown:          false,
 		shutdownRdy:       make(chan bool),
 		currentOps:        0,
 		opsLock:           &sync.Mutex{},
-		connections:       make([]*connection, 0),
+		connections:       make(map[uint64]*connection),
 		connectionsLock:   &sync.Mutex{},
 		sessionsEnabled:   sessionsEnabled,
-		messagePool:       message.NewSyncPool(opts.MessageBufferSize, 1024),
-		warnLog:           opts.WarnLog,
-		errorLog:          opts.ErrorLog,
+		sessionEvents: newSessionEventBus(
+			opts.HashSessionEventKeys,
+			opts.Logger,
+		),
+		messagePool: message.NewSyncPool(opts.MessageBufferSize, 1024),
+		rateLimiter: newRateLimiter(opts.RateLimits),
+		connectionLimiter: newConnectionLimiter(
+			opts.MaxConnections,
+			opts.MaxConnectionsPerIP,
+		),
+		metrics: opts.Metrics,
+		tracer:  opts.Tracer,
+		logger:  opts.Logger,
+	}
+
+	if opts.Compression == Enabled {
+		srv.capabilities |= message.CapabilityCompression
 	}
 
 	srv.sessionRegistry = newSessionRegistry(
 		opts.MaxSessionConnections,
 		func(sessionKey string) {
+			srv.rateLimiter.forgetSession(sessionKey)
 			if err := srv.sessionManager.OnSessionClosed(
 				sessionKey,
 			); err != nil {
-				srv.errorLog.Printf("session registry ")
+				srv.logger.Log(
+					LogLevelError,
+					"OnSessionClosed hook failed",
+					LogField{LogKeySessionKeyHash, HashSessionKey(sessionKey)},
+					logErr(err),
+				)
 			}
 		},
 	)
 
+	srv.sessionRegistry.metrics = opts.Metrics
+	srv.sessionRegistry.logger = opts.Logger
+
 	// Initialize the transport layer
 	if err := transport.Initialize(
 		opts,
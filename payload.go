@@ -1,6 +1,9 @@
 package webwire
 
-import "github.com/qbeon/webwire-go/payload"
+import (
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+)
 
 // PayloadEncoding represents the type of encoding of the message payload
 type PayloadEncoding = payload.Encoding
@@ -16,6 +19,9 @@ const (
 	EncodingUtf16 PayloadEncoding = payload.Utf16
 )
 
+// Header represents a key/value message header
+type Header = message.Header
+
 // Payload represents an encoded payload
 type Payload struct {
 	// Encoding represents the encoding type of the payload which is
@@ -24,4 +30,8 @@ type Payload struct {
 
 	// Data represents the payload data
 	Data []byte
+
+	// Headers optionally attaches headers to the reply or signal carrying the
+	// payload, which are then sent wrapped in a header envelope
+	Headers []Header
 }
@@ -89,3 +89,118 @@ func TestConvertCorruptUtf16(t *testing.T) {
 	require.Error(t, err)
 	require.Len(t, result, 0)
 }
+
+// TestConvertUtf16SurrogatePairsToUtf8 tests the Utf8() payload conversion
+// method with a UTF16 encoded payload containing surrogate pairs
+func TestConvertUtf16SurrogatePairsToUtf8(t *testing.T) {
+	payload := Payload{
+		Encoding: Utf16,
+		Data: []byte{
+			0x41, 0x00, // A
+			0x3D, 0xD8, 0x00, 0xDE, // 😀
+			0x42, 0x00, // B
+		},
+	}
+
+	result, err := payload.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "A😀B", string(result))
+}
+
+// TestConvertUtf16BomToUtf8 tests the Utf8() payload conversion method
+// with UTF16 encoded payloads starting with a byte order mark
+func TestConvertUtf16BomToUtf8(t *testing.T) {
+	littleEndian := Payload{
+		Encoding: Utf16,
+		Data:     []byte{0xFF, 0xFE, 0x41, 0x00, 0x3D, 0xD8, 0x00, 0xDE},
+	}
+	result, err := littleEndian.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "A😀", string(result))
+
+	bigEndian := Payload{
+		Encoding: Utf16,
+		Data:     []byte{0xFE, 0xFF, 0x00, 0x41, 0xD8, 0x3D, 0xDE, 0x00},
+	}
+	result, err = bigEndian.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "A😀", string(result))
+}
+
+// TestConvertUnpairedSurrogateUtf16 tests the Utf8() payload conversion
+// method with UTF16 encoded payloads containing unpaired surrogates
+func TestConvertUnpairedSurrogateUtf16(t *testing.T) {
+	for _, data := range [][]byte{
+		{0x3D, 0xD8},             // Trailing high surrogate
+		{0x3D, 0xD8, 0x41, 0x00}, // High surrogate followed by a non-surrogate
+		{0x00, 0xDE, 0x41, 0x00}, // Leading low surrogate
+	} {
+		payload := Payload{Encoding: Utf16, Data: data}
+		result, err := payload.Utf8()
+		require.Error(t, err)
+		require.Len(t, result, 0)
+		require.Error(t, ValidateUtf16(data))
+	}
+}
+
+// TestConvertToUtf16 tests the Utf16() payload conversion method
+func TestConvertToUtf16(t *testing.T) {
+	expected := []byte{0x41, 0x00, 0x51, 0x04, 0x3D, 0xD8, 0x00, 0xDE}
+
+	// UTF8 encoded payload
+	fromUtf8 := Payload{Encoding: Utf8, Data: []byte("Aё😀")}
+	result, err := fromUtf8.Utf16()
+	require.NoError(t, err)
+	require.Equal(t, expected, result)
+
+	// Big-endian UTF16 payload with a byte order mark
+	fromBigEndian := Payload{
+		Encoding: Utf16,
+		Data: []byte{
+			0xFE, 0xFF, 0x00, 0x41, 0x04, 0x51, 0xD8, 0x3D, 0xDE, 0x00,
+		},
+	}
+	result, err = fromBigEndian.Utf16()
+	require.NoError(t, err)
+	require.Equal(t, expected, result)
+
+	// Invalid UTF8 payload
+	invalid := Payload{Encoding: Binary, Data: []byte{0xFF, 0x41}}
+	_, err = invalid.Utf16()
+	require.Error(t, err)
+}
+
+// TestFromString tests creating payloads from strings
+func TestFromString(t *testing.T) {
+	utf8Payload, err := FromString("Aё😀", Utf8)
+	require.NoError(t, err)
+	require.Equal(t, Utf8, utf8Payload.Encoding)
+	require.Equal(t, []byte("Aё😀"), utf8Payload.Data)
+
+	utf16Payload, err := FromString("Aё😀", Utf16)
+	require.NoError(t, err)
+	require.Equal(t, Utf16, utf16Payload.Encoding)
+	require.Equal(t,
+		[]byte{0x41, 0x00, 0x51, 0x04, 0x3D, 0xD8, 0x00, 0xDE},
+		utf16Payload.Data,
+	)
+
+	// Round trip
+	result, err := utf16Payload.Utf8()
+	require.NoError(t, err)
+	require.Equal(t, "Aё😀", string(result))
+
+	_, err = FromString("A", Encoding(42))
+	require.Error(t, err)
+}
+
+// TestValidateUtf8 tests UTF8 validation
+func TestValidateUtf8(t *testing.T) {
+	require.NoError(t, ValidateUtf8([]byte("ABC ёжз 😀")))
+	require.NoError(t, ValidateUtf8(nil))
+
+	// Malformed sequences
+	require.Error(t, ValidateUtf8([]byte{0x41, 0xC3, 0x28}))
+	require.Error(t, ValidateUtf8([]byte{0xFF}))
+	require.Error(t, ValidateUtf8([]byte{0xF0, 0x9F, 0x98}))
+}
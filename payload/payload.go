@@ -3,7 +3,6 @@ package payload
 import (
 	"bytes"
 	"fmt"
-	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -13,26 +12,66 @@ type Payload struct {
 	Data     []byte
 }
 
-// Utf8 returns a UTF8 representation of the payload data
+// Utf8 returns a UTF8 representation of the payload data.
+// UTF16 encoded data is decoded taking surrogate pairs and an optional
+// byte order mark into account, malformed UTF16 data results in an error
 func (pld *Payload) Utf8() ([]byte, error) {
 	if pld.Encoding == Utf16 {
-		if len(pld.Data)%2 != 0 {
+		utf8str := bytes.NewBuffer(make([]byte, 0, len(pld.Data)))
+		utf8buf := make([]byte, utf8.UTFMax)
+		if err := decodeUtf16(pld.Data, func(rn rune) {
+			rnSize := utf8.EncodeRune(utf8buf, rn)
+			utf8str.Write(utf8buf[:rnSize])
+		}); err != nil {
 			return nil, fmt.Errorf(
-				"Cannot convert invalid UTF16 payload data to UTF8",
+				"Cannot convert invalid UTF16 payload data to UTF8: %s",
+				err,
 			)
 		}
-		u16str := make([]uint16, 1)
-		utf8str := &bytes.Buffer{}
-		utf8buf := make([]byte, 4)
-		for i := 0; i < len(pld.Data); i += 2 {
-			u16str[0] = uint16(pld.Data[i]) + (uint16(pld.Data[i+1]) << 8)
-			rn := utf16.Decode(u16str)
-			rnSize := utf8.EncodeRune(utf8buf, rn[0])
-			utf8str.Write(utf8buf[:rnSize])
-		}
 		return utf8str.Bytes(), nil
 	}
 
 	// Binary and UTF8 encoded payloads should pass through untouched
 	return pld.Data, nil
 }
+
+// Utf16 returns a little-endian UTF16 representation of the payload data
+// without a byte order mark. Binary payloads are interpreted as UTF8 text
+func (pld *Payload) Utf16() ([]byte, error) {
+	if pld.Encoding == Utf16 {
+		if err := ValidateUtf16(pld.Data); err != nil {
+			return nil, err
+		}
+
+		order, bomLen := DetectByteOrder(pld.Data)
+		data := pld.Data[bomLen:]
+		if order == LittleEndian {
+			return data, nil
+		}
+
+		// Swap big-endian code units
+		swapped := make([]byte, len(data))
+		for i := 0; i < len(data); i += 2 {
+			swapped[i], swapped[i+1] = data[i+1], data[i]
+		}
+		return swapped, nil
+	}
+
+	return encodeUtf16(pld.Data)
+}
+
+// FromString creates a new payload encoding the given string
+// using the given encoding
+func FromString(str string, enc Encoding) (Payload, error) {
+	switch enc {
+	case Binary, Utf8:
+		return Payload{Encoding: enc, Data: []byte(str)}, nil
+	case Utf16:
+		data, err := encodeUtf16([]byte(str))
+		if err != nil {
+			return Payload{}, err
+		}
+		return Payload{Encoding: Utf16, Data: data}, nil
+	}
+	return Payload{}, fmt.Errorf("unsupported payload encoding: %d", enc)
+}
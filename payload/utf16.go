@@ -0,0 +1,134 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ByteOrder represents the byte order of UTF16 encoded data
+type ByteOrder int
+
+const (
+	// LittleEndian represents the little-endian byte order which is assumed
+	// for UTF16 encoded data not starting with a byte order mark
+	LittleEndian ByteOrder = iota
+
+	// BigEndian represents the big-endian byte order
+	BigEndian
+)
+
+const (
+	surrogateMin     = 0xD800
+	surrogateHighMax = 0xDBFF
+	surrogateMax     = 0xDFFF
+)
+
+// DetectByteOrder detects the byte order of the given UTF16 encoded data
+// by its byte order mark returning the byte order and the length of the mark.
+// Data not starting with a byte order mark is assumed to be little-endian
+func DetectByteOrder(data []byte) (order ByteOrder, bomLen int) {
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFF && data[1] == 0xFE:
+			return LittleEndian, 2
+		case data[0] == 0xFE && data[1] == 0xFF:
+			return BigEndian, 2
+		}
+	}
+	return LittleEndian, 0
+}
+
+// unitAt reads the 16-bit code unit at the given offset
+func unitAt(data []byte, offset int, order ByteOrder) uint16 {
+	if order == BigEndian {
+		return uint16(data[offset])<<8 | uint16(data[offset+1])
+	}
+	return uint16(data[offset]) | uint16(data[offset+1])<<8
+}
+
+// decodeUtf16 calls onRune for every code point of the given UTF16 encoded
+// data. Surrogate pairs are combined into a single code point, while
+// unpaired surrogates and odd data lengths are rejected with an error.
+// onRune may be nil in which case the data is only validated
+func decodeUtf16(data []byte, onRune func(rune)) error {
+	if len(data)%2 != 0 {
+		return errors.New("invalid UTF16 data, odd number of bytes")
+	}
+
+	order, offset := DetectByteOrder(data)
+	for ; offset < len(data); offset += 2 {
+		unit := unitAt(data, offset, order)
+
+		if unit < surrogateMin || unit > surrogateMax {
+			if onRune != nil {
+				onRune(rune(unit))
+			}
+			continue
+		}
+
+		if unit > surrogateHighMax {
+			return fmt.Errorf(
+				"invalid UTF16 data, unpaired low surrogate at offset %d",
+				offset,
+			)
+		}
+
+		// High surrogate, expect a low surrogate to follow
+		if offset+3 >= len(data) {
+			return fmt.Errorf(
+				"invalid UTF16 data, unpaired high surrogate at offset %d",
+				offset,
+			)
+		}
+		low := unitAt(data, offset+2, order)
+		if low <= surrogateHighMax || low > surrogateMax {
+			return fmt.Errorf(
+				"invalid UTF16 data, unpaired high surrogate at offset %d",
+				offset,
+			)
+		}
+		if onRune != nil {
+			onRune(utf16.DecodeRune(rune(unit), rune(low)))
+		}
+		offset += 2
+	}
+
+	return nil
+}
+
+// ValidateUtf16 returns an error if the given data isn't well-formed UTF16.
+// A leading byte order mark is taken into account, data without one
+// is expected to be little-endian
+func ValidateUtf16(data []byte) error {
+	return decodeUtf16(data, nil)
+}
+
+// encodeUtf16 encodes the given UTF8 text to little-endian UTF16
+// without a byte order mark
+func encodeUtf16(text []byte) ([]byte, error) {
+	if !utf8.Valid(text) {
+		return nil, errors.New("invalid UTF8 data, cannot encode to UTF16")
+	}
+
+	encoded := make([]byte, 0, len(text)*2)
+	for len(text) > 0 {
+		rn, size := utf8.DecodeRune(text)
+		text = text[size:]
+
+		if rn > 0xFFFF {
+			// Characters outside the basic multilingual plane
+			// are encoded as surrogate pairs
+			r1, r2 := utf16.EncodeRune(rn)
+			encoded = append(
+				encoded,
+				byte(r1), byte(r1>>8),
+				byte(r2), byte(r2>>8),
+			)
+			continue
+		}
+		encoded = append(encoded, byte(rn), byte(rn>>8))
+	}
+	return encoded, nil
+}
@@ -0,0 +1,21 @@
+package payload
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ValidateUtf8 returns an error if the given data isn't well-formed UTF8
+func ValidateUtf8(data []byte) error {
+	for offset := 0; offset < len(data); {
+		rn, size := utf8.DecodeRune(data[offset:])
+		if rn == utf8.RuneError && size < 2 {
+			return fmt.Errorf(
+				"invalid UTF8 data, malformed sequence at offset %d",
+				offset,
+			)
+		}
+		offset += size
+	}
+	return nil
+}
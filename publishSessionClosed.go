@@ -0,0 +1,29 @@
+package webwire
+
+// publishSessionClosed publishes the session closure and the detachment of the
+// given connection. connsLeft is the number of connections of the session left
+// after the deregistration of the connection, the session is considered
+// destroyed if there are none left. Nothing is published if the connection
+// wasn't registered (connsLeft < 0)
+func (srv *server) publishSessionClosed(
+	con *connection,
+	sessionKey string,
+	connsLeft int,
+) {
+	if connsLeft < 0 {
+		return
+	}
+
+	outcome := SessionOutcomeSuccess
+	if connsLeft == 0 {
+		outcome = SessionOutcomeDestroyed
+	}
+	srv.sessionEvents.publish(SessionEventClosed, con, sessionKey, outcome, nil)
+	srv.sessionEvents.publish(
+		SessionEventDetached,
+		con,
+		sessionKey,
+		SessionOutcomeSuccess,
+		nil,
+	)
+}
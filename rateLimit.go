@@ -0,0 +1,312 @@
+package webwire
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/qbeon/webwire-go/message"
+)
+
+// RateLimitExceededCode defines the error code of the error replies to
+// requests rejected due to exceeding a rate limit
+const RateLimitExceededCode = "RATE_LIMIT_EXCEEDED"
+
+// RateLimit defines a token bucket rate limit
+type RateLimit struct {
+	// Rate defines the number of messages per second the bucket is refilled
+	// with. The limit is disabled if Rate is zero
+	Rate float64
+
+	// Burst defines the capacity of the bucket, which is the maximum number
+	// of messages accepted at once. Defaults to the rate rounded up
+	Burst uint
+}
+
+// enabled returns true if the rate limit is enabled
+func (limit RateLimit) enabled() bool {
+	return limit.Rate > 0
+}
+
+// RateLimits defines the rate limits applied to incoming requests, signals and
+// session restoration and closure requests. Messages exceeding any of the
+// limits are rejected: requests are replied to with an ErrRateLimitExceeded
+// error while signals are dropped
+type RateLimits struct {
+	// Global defines the limit shared by all connections of the server
+	Global RateLimit
+
+	// Connection defines the limit of each individual connection
+	Connection RateLimit
+
+	// Session defines the limit shared by all connections of a session
+	Session RateLimit
+
+	// Messages defines separate limits for messages of a particular name
+	// applied to each individual connection in addition to the other limits
+	Messages map[string]RateLimit
+
+	// SessionRestore defines a separate limit for session restoration requests
+	// applied to each individual connection in addition to the connection and
+	// global limits. It slows down clients guessing session keys, which should
+	// also be bounded by a global limit and ServerOptions.MaxConnectionsPerIP
+	// since clients can reconnect to obtain a fresh connection budget
+	SessionRestore RateLimit
+
+	// MaxViolations defines the number of consecutive rejected messages
+	// after which the connection is closed. Connections are never closed
+	// if MaxViolations is zero
+	MaxViolations uint
+}
+
+// validate returns an error if any of the limits is invalid
+func (limits RateLimits) validate() error {
+	verify := func(name string, limit RateLimit) error {
+		if limit.Rate < 0 || math.IsNaN(limit.Rate) {
+			return fmt.Errorf("invalid %s rate limit: %f", name, limit.Rate)
+		}
+		return nil
+	}
+	if err := verify("global", limits.Global); err != nil {
+		return err
+	}
+	if err := verify("connection", limits.Connection); err != nil {
+		return err
+	}
+	if err := verify("session", limits.Session); err != nil {
+		return err
+	}
+	if err := verify("session restore", limits.SessionRestore); err != nil {
+		return err
+	}
+	for name, limit := range limits.Messages {
+		if err := verify("message ("+name+")", limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tokenBucket represents a thread safe token bucket
+type tokenBucket struct {
+	lock     sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// newTokenBucket creates a new full token bucket for the given rate limit.
+// Returns nil if the limit is disabled
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	if !limit.enabled() {
+		return nil
+	}
+	capacity := float64(limit.Burst)
+	if capacity < 1 {
+		capacity = math.Ceil(limit.Rate)
+	}
+	return &tokenBucket{
+		rate:     limit.Rate,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     now,
+	}
+}
+
+// refill refills the bucket according to the time elapsed since
+// the last refill. The bucket must be locked
+func (bucket *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = math.Min(
+			bucket.capacity,
+			bucket.tokens+elapsed.Seconds()*bucket.rate,
+		)
+		bucket.last = now
+	}
+}
+
+// take refills the bucket and takes a token from it returning false if
+// the bucket is empty. Always returns true for nil buckets
+func (bucket *tokenBucket) take(now time.Time) bool {
+	return takeAll(now, bucket)
+}
+
+// takeAll takes a token from each of the given buckets only if none of them
+// is empty, otherwise no tokens are taken and false is returned. Nil buckets
+// are ignored. Buckets must always be passed in the same order since they're
+// all locked at once
+func takeAll(now time.Time, buckets ...*tokenBucket) bool {
+	allowed := true
+	for _, bucket := range buckets {
+		if bucket == nil {
+			continue
+		}
+		bucket.lock.Lock()
+		bucket.refill(now)
+		if bucket.tokens < 1 {
+			allowed = false
+		}
+	}
+	for _, bucket := range buckets {
+		if bucket == nil {
+			continue
+		}
+		if allowed {
+			bucket.tokens--
+		}
+		bucket.lock.Unlock()
+	}
+	return allowed
+}
+
+// rateLimiter represents the rate limiter of a server
+type rateLimiter struct {
+	limits       RateLimits
+	global       *tokenBucket
+	sessionsLock sync.Mutex
+	sessions     map[string]*tokenBucket
+}
+
+// newRateLimiter creates a new rate limiter enforcing the given limits
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		limits:   limits,
+		global:   newTokenBucket(limits.Global, time.Now()),
+		sessions: make(map[string]*tokenBucket),
+	}
+}
+
+// connectionRateLimits represents the rate limits state of a connection.
+// It's only accessed by the goroutine reading from the connection
+type connectionRateLimits struct {
+	bucket     *tokenBucket
+	restore    *tokenBucket
+	messages   map[string]*tokenBucket
+	violations uint
+}
+
+// newConnectionRateLimits creates the rate limits state for a new connection
+func (limiter *rateLimiter) newConnectionRateLimits() connectionRateLimits {
+	now := time.Now()
+	return connectionRateLimits{
+		bucket:  newTokenBucket(limiter.limits.Connection, now),
+		restore: newTokenBucket(limiter.limits.SessionRestore, now),
+	}
+}
+
+// sessionBucket returns the bucket of the session identified by the given key
+func (limiter *rateLimiter) sessionBucket(
+	sessionKey string,
+	now time.Time,
+) *tokenBucket {
+	if sessionKey == "" || !limiter.limits.Session.enabled() {
+		return nil
+	}
+
+	limiter.sessionsLock.Lock()
+	defer limiter.sessionsLock.Unlock()
+
+	bucket, exists := limiter.sessions[sessionKey]
+	if !exists {
+		bucket = newTokenBucket(limiter.limits.Session, now)
+		limiter.sessions[sessionKey] = bucket
+	}
+	return bucket
+}
+
+// forgetSession removes the bucket of the session identified by the given key
+func (limiter *rateLimiter) forgetSession(sessionKey string) {
+	limiter.sessionsLock.Lock()
+	delete(limiter.sessions, sessionKey)
+	limiter.sessionsLock.Unlock()
+}
+
+// messageBucket returns the per-name bucket of the connection for messages
+// of the given name. Returns nil if there's no limit for the name
+func (limiter *rateLimiter) messageBucket(
+	limits *connectionRateLimits,
+	name []byte,
+	now time.Time,
+) *tokenBucket {
+	limit, exists := limiter.limits.Messages[string(name)]
+	if !exists {
+		return nil
+	}
+	if limits.messages == nil {
+		limits.messages = make(map[string]*tokenBucket)
+	}
+	bucket, exists := limits.messages[string(name)]
+	if !exists {
+		bucket = newTokenBucket(limit, now)
+		limits.messages[string(name)] = bucket
+	}
+	return bucket
+}
+
+// allow returns true if the given message received on the given connection is
+// within all rate limits. Tokens are only taken if the message is within all
+// of the limits, rejected messages don't drain any of the budgets
+func (limiter *rateLimiter) allow(
+	con *connection,
+	msg *message.Message,
+) bool {
+	now := time.Now()
+	limits := &con.rateLimits
+
+	// The narrowest bucket, which is either the per-name bucket
+	// or the session restoration bucket, comes first
+	var narrow *tokenBucket
+	switch msg.MsgType {
+	case message.MsgRequestRestoreSession:
+		narrow = limits.restore
+	case message.MsgRequestCloseSession:
+		// Session closures are only subject to the shared limits
+	default:
+		narrow = limiter.messageBucket(limits, msg.MsgName, now)
+	}
+
+	allowed := takeAll(
+		now,
+		narrow,
+		limits.bucket,
+		limiter.sessionBucket(con.SessionKey(), now),
+		limiter.global,
+	)
+
+	if allowed {
+		limits.violations = 0
+	} else {
+		limits.violations++
+	}
+	return allowed
+}
+
+// isRepeatOffender returns true if the given connection exceeded
+// the maximum number of consecutive rate limit violations
+func (limiter *rateLimiter) isRepeatOffender(con *connection) bool {
+	return limiter.limits.MaxViolations > 0 &&
+		con.rateLimits.violations >= limiter.limits.MaxViolations
+}
+
+// rejectRateLimited rejects a message exceeding the rate limits.
+// Requests are failed while signals are dropped. The connection is closed
+// if it exceeded the maximum number of consecutive violations
+func (srv *server) rejectRateLimited(con *connection, msg *message.Message) {
+	srv.failMsg(con, msg, ErrRateLimitExceeded{})
+
+	// Release message buffer
+	msg.Close()
+
+	if !srv.rateLimiter.isRepeatOffender(con) {
+		return
+	}
+
+	srv.logger.Log(
+		LogLevelWarn,
+		"closing connection repeatedly exceeding the rate limits",
+		con.logFields()...,
+	)
+	con.Close()
+}
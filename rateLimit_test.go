@@ -0,0 +1,120 @@
+package webwire
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenBucket tests taking tokens from and refilling a token bucket
+func TestTokenBucket(t *testing.T) {
+	start := time.Now()
+	bucket := newTokenBucket(RateLimit{Rate: 2, Burst: 3}, start)
+
+	// Drain the burst capacity
+	require.True(t, bucket.take(start))
+	require.True(t, bucket.take(start))
+	require.True(t, bucket.take(start))
+	require.False(t, bucket.take(start))
+
+	// Refill a single token
+	require.True(t, bucket.take(start.Add(500*time.Millisecond)))
+	require.False(t, bucket.take(start.Add(500*time.Millisecond)))
+
+	// Never exceed the capacity
+	later := start.Add(time.Hour)
+	require.True(t, bucket.take(later))
+	require.True(t, bucket.take(later))
+	require.True(t, bucket.take(later))
+	require.False(t, bucket.take(later))
+}
+
+// TestTokenBucketDefaultBurst tests the default capacity of a token bucket
+func TestTokenBucketDefaultBurst(t *testing.T) {
+	start := time.Now()
+	bucket := newTokenBucket(RateLimit{Rate: 1.5}, start)
+	require.True(t, bucket.take(start))
+	require.True(t, bucket.take(start))
+	require.False(t, bucket.take(start))
+}
+
+// TestTokenBucketDisabled tests disabled rate limits
+func TestTokenBucketDisabled(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{}, time.Now())
+	require.Nil(t, bucket)
+	for i := 0; i < 100; i++ {
+		require.True(t, bucket.take(time.Now()))
+	}
+}
+
+// TestTokenBucketTakeAll tests taking tokens from multiple buckets at once
+// expecting rejections not to drain any of the buckets
+func TestTokenBucketTakeAll(t *testing.T) {
+	start := time.Now()
+	wide := newTokenBucket(RateLimit{Rate: 0.001, Burst: 2}, start)
+	narrow := newTokenBucket(RateLimit{Rate: 0.001, Burst: 1}, start)
+
+	require.True(t, takeAll(start, narrow, nil, wide))
+
+	// Expect the narrow bucket to reject without draining the wide one
+	require.False(t, takeAll(start, narrow, nil, wide))
+	require.False(t, takeAll(start, narrow, nil, wide))
+	require.True(t, wide.take(start))
+	require.False(t, wide.take(start))
+}
+
+// TestRateLimitsValidate tests the validation of invalid rate limits
+func TestRateLimitsValidate(t *testing.T) {
+	require.NoError(t, RateLimits{}.validate())
+	require.Error(t, RateLimits{Session: RateLimit{Rate: -1}}.validate())
+	require.Error(t, RateLimits{
+		SessionRestore: RateLimit{Rate: -1},
+	}.validate())
+	require.Error(t, RateLimits{
+		Messages: map[string]RateLimit{"a": {Rate: -1}},
+	}.validate())
+}
+
+// rateLimitTestSocket is a disconnected socket stub
+type rateLimitTestSocket struct{ Socket }
+
+// RemoteAddr implements the Socket interface
+func (rateLimitTestSocket) RemoteAddr() net.Addr { return nil }
+
+// Close implements the Socket interface
+func (rateLimitTestSocket) Close() error { return nil }
+
+// TestRateLimiterForgetDisconnectedSession tests dropping the bucket
+// of a session once its last connection disconnected
+func TestRateLimiterForgetDisconnectedSession(t *testing.T) {
+	srv := &server{
+		sessionRegistry: newSessionRegistry(0, nil),
+		sessionEvents:   newSessionEventBus(false, nil),
+		rateLimiter: newRateLimiter(RateLimits{
+			Session: RateLimit{Rate: 1},
+		}),
+	}
+	limiter := srv.rateLimiter
+
+	// Connect two connections with the same session
+	sess := NewSession(nil, func() string { return "testkey" })
+	conA := newConnection(rateLimitTestSocket{}, srv, ConnectionOptions{})
+	conA.session = &sess
+	require.NoError(t, srv.sessionRegistry.register(conA))
+	conB := newConnection(rateLimitTestSocket{}, srv, ConnectionOptions{})
+	conB.session = &sess
+	require.NoError(t, srv.sessionRegistry.register(conB))
+
+	require.NotNil(t, limiter.sessionBucket("testkey", time.Now()))
+	require.Len(t, limiter.sessions, 1)
+
+	// Expect the bucket to be kept while the session is still connected
+	conA.unlink()
+	require.Len(t, limiter.sessions, 1)
+
+	// Expect the bucket to be dropped after the last connection disconnected
+	conB.unlink()
+	require.Len(t, limiter.sessions, 0)
+}
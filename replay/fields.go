@@ -0,0 +1,236 @@
+package replay
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// fields maps the paths of the fields of a message to their values
+type fields map[string]string
+
+// observed represents a flattened message
+type observed struct {
+	msgType byte
+	id      [8]byte
+	hasID   bool
+	fields  fields
+
+	// sessionKey is the key of the carried session object, if any
+	sessionKey string
+}
+
+// hasIdentifier returns true if messages of the given type
+// carry a message identifier
+func hasIdentifier(msgType byte) bool {
+	switch msgType {
+	case message.MsgReplyError,
+		message.MsgReplyShutdown,
+		message.MsgReplyInternalError,
+		message.MsgReplySessionNotFound,
+		message.MsgReplyMaxSessConnsReached,
+		message.MsgReplySessionsDisabled,
+		message.MsgRequestCloseSession,
+		message.MsgRequestRestoreSession,
+		message.MsgRequestBinary,
+		message.MsgRequestUtf8,
+		message.MsgRequestUtf16,
+		message.MsgReplyBinary,
+		message.MsgReplyUtf8,
+		message.MsgReplyUtf16:
+		return true
+	}
+	return false
+}
+
+// hasPayloadEncoding returns true if the payload encoding of messages
+// of the given type is variable
+func hasPayloadEncoding(msgType byte) bool {
+	switch msgType {
+	case message.MsgSignalBinary,
+		message.MsgSignalUtf8,
+		message.MsgSignalUtf16,
+		message.MsgRequestBinary,
+		message.MsgRequestUtf8,
+		message.MsgRequestUtf16,
+		message.MsgReplyBinary,
+		message.MsgReplyUtf8,
+		message.MsgReplyUtf16:
+		return true
+	}
+	return false
+}
+
+// flattenJSON flattens the given decoded JSON value into fields
+func flattenJSON(into fields, prefix string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, val := range value {
+			flattenJSON(into, prefix+"/"+key, val)
+		}
+	case []interface{}:
+		for index, val := range value {
+			flattenJSON(into, prefix+"/"+strconv.Itoa(index), val)
+		}
+	default:
+		encoded, _ := json.Marshal(value)
+		into[prefix] = string(encoded)
+	}
+}
+
+// flattenPayload flattens the given payload into fields of the given prefix.
+// JSON objects and arrays are flattened into their values, other text
+// payloads are quoted while binary payloads are hex encoded
+func flattenPayload(into fields, prefix string, payload pld.Payload) {
+	if len(payload.Data) < 1 {
+		return
+	}
+
+	text := payload.Data
+	if payload.Encoding == pld.Utf16 {
+		utf8, err := payload.Utf8()
+		if err != nil {
+			into[prefix] = hex.EncodeToString(payload.Data)
+			return
+		}
+		text = utf8
+	}
+
+	var decoded interface{}
+	if payload.Encoding != pld.Binary || json.Valid(text) {
+		if err := json.Unmarshal(text, &decoded); err == nil {
+			switch decoded.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenJSON(into, prefix, decoded)
+				return
+			}
+		}
+	}
+
+	if payload.Encoding == pld.Binary {
+		into[prefix] = hex.EncodeToString(payload.Data)
+		return
+	}
+	into[prefix] = strconv.Quote(string(text))
+}
+
+// sessionKey returns the key of the given JSON encoded session object
+func sessionKey(data []byte) string {
+	var session struct {
+		Key string `json:"k"`
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return ""
+	}
+	return session.Key
+}
+
+// flatten flattens the given parsed message. isSession must be true if the
+// message carries a session object
+func flatten(msg *message.Message, isSession bool) observed {
+	obs := observed{
+		msgType: msg.MsgType,
+		hasID:   hasIdentifier(msg.MsgType),
+		fields:  fields{"type": message.TypeName(msg.MsgType)},
+	}
+	if obs.hasID {
+		obs.id = msg.MsgIdentifier
+		obs.fields["id"] = hex.EncodeToString(msg.MsgIdentifierBytes)
+	}
+	if len(msg.MsgName) > 0 {
+		obs.fields["name"] = strconv.Quote(string(msg.MsgName))
+	}
+	if hasPayloadEncoding(msg.MsgType) {
+		obs.fields["encoding"] = msg.MsgPayload.Encoding.String()
+	}
+	for _, header := range msg.Headers {
+		obs.fields["header/"+string(header.Key)] = strconv.Quote(
+			string(header.Value),
+		)
+	}
+
+	switch msg.MsgType {
+	case message.MsgAcceptConf:
+		conf := msg.ServerConfiguration
+		obs.fields["conf/protocol"] = fmt.Sprintf(
+			"%d.%d",
+			conf.MajorProtocolVersion,
+			conf.MinorProtocolVersion,
+		)
+		obs.fields["conf/readTimeout"] = conf.ReadTimeout.String()
+		obs.fields["conf/bufferSize"] = strconv.FormatUint(
+			uint64(conf.MessageBufferSize),
+			10,
+		)
+		obs.fields["conf/subProtocol"] = strconv.Quote(
+			string(conf.SubProtocolName),
+		)
+		obs.fields["conf/sessionCodec"] = strconv.Itoa(int(conf.SessionCodec))
+		obs.fields["conf/capabilities"] = strconv.Itoa(int(conf.Capabilities))
+		return obs
+	case message.MsgNotifySessionClosed:
+		obs.fields["reason"] = strconv.Itoa(int(msg.SessionClosureReason))
+		return obs
+	}
+
+	if isSession {
+		obs.sessionKey = sessionKey(msg.MsgPayload.Data)
+		flattenPayload(obs.fields, "session", msg.MsgPayload)
+		return obs
+	}
+	flattenPayload(obs.fields, "payload", msg.MsgPayload)
+	return obs
+}
+
+// matches returns true if the given received message corresponds to the
+// expected one. Messages carrying an identifier are matched by identifier,
+// others by type
+func (obs observed) matches(actual observed) bool {
+	if obs.hasID {
+		return actual.hasID && obs.id == actual.id
+	}
+	return !actual.hasID && obs.msgType == actual.msgType
+}
+
+// ignored returns true if the field of the given path matches
+// any of the given patterns
+func ignored(field string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, field); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the paths of the differing fields which aren't ignored
+// in sorted order
+func diff(expected, actual fields, ignore []string) []string {
+	var paths []string
+	for field, value := range expected {
+		if actualValue, exists := actual[field]; !exists ||
+			actualValue != value {
+			paths = append(paths, field)
+		}
+	}
+	for field := range actual {
+		if _, exists := expected[field]; !exists {
+			paths = append(paths, field)
+		}
+	}
+
+	differing := paths[:0]
+	for _, field := range paths {
+		if !ignored(field, ignore) {
+			differing = append(differing, field)
+		}
+	}
+	sort.Strings(differing)
+	return differing
+}
@@ -0,0 +1,361 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Player replays captured client sessions against a server under test.
+// Sessions are replayed sequentially in the order of their first record.
+// The session keys mapped during the replay are shared by all sessions
+// replayed by the same player
+type Player struct {
+	opts     Options
+	messages *message.SyncPool
+
+	// keys maps recorded session keys to the keys of the sessions
+	// created during the replay
+	keys map[string]string
+}
+
+// NewPlayer creates a new player
+func NewPlayer(opts Options) (*Player, error) {
+	if opts.Transport == nil {
+		return nil, errors.New("missing transport")
+	}
+	if opts.Ignore == nil {
+		opts.Ignore = DefaultIgnore
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MessageBufferSize == 0 {
+		opts.MessageBufferSize = DefaultMessageBufferSize
+	}
+	return &Player{
+		opts:     opts,
+		messages: message.NewSyncPool(opts.MessageBufferSize, 0),
+		keys:     make(map[string]string),
+	}, nil
+}
+
+// Load reads all records of the capture read from the given source
+func Load(src io.Reader) ([]capture.Record, error) {
+	reader, err := capture.NewReader(src)
+	if err != nil {
+		return nil, err
+	}
+	var records []capture.Record
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// sessions groups the given records by connection
+// in the order of their first record
+func sessions(records []capture.Record) [][]capture.Record {
+	var order []uint64
+	byConnection := make(map[uint64][]capture.Record)
+	for _, rec := range records {
+		if _, exists := byConnection[rec.ConnectionID]; !exists {
+			order = append(order, rec.ConnectionID)
+		}
+		byConnection[rec.ConnectionID] = append(
+			byConnection[rec.ConnectionID],
+			rec,
+		)
+	}
+	grouped := make([][]capture.Record, len(order))
+	for i, connID := range order {
+		grouped[i] = byConnection[connID]
+	}
+	return grouped
+}
+
+// Play replays the client sessions of the given records. Divergences are
+// reported, an error is only returned if a session couldn't be replayed
+func (player *Player) Play(records []capture.Record) (*Report, error) {
+	report := &Report{}
+	for _, session := range sessions(records) {
+		if err := player.playSession(report, session); err != nil {
+			return report, fmt.Errorf(
+				"couldn't replay connection %d: %s",
+				session[0].ConnectionID,
+				err,
+			)
+		}
+		report.Sessions++
+	}
+	return report, nil
+}
+
+// session represents a session being replayed
+type session struct {
+	player   *Player
+	report   *Report
+	sock     wwr.ClientSocket
+	received chan observed
+
+	// pool holds received messages not yet matched
+	pool []observed
+
+	// closed is set when the connection was found closed while sending
+	closed bool
+
+	lock sync.Mutex
+
+	// recordedRestores and receivedRestores hold the identifiers of the
+	// sent session restoration requests the recorded and received replies to
+	// which carry session objects respectively
+	recordedRestores map[[8]byte]bool
+	receivedRestores map[[8]byte]bool
+}
+
+// playSession replays the given records of a single connection
+func (player *Player) playSession(
+	report *Report,
+	records []capture.Record,
+) error {
+	sock, err := player.opts.Transport.NewSocket(player.opts.Timeout)
+	if err != nil {
+		return err
+	}
+	if err := sock.Dial(time.Now().Add(player.opts.Timeout)); err != nil {
+		sock.Close()
+		return fmt.Errorf("couldn't dial: %s", err)
+	}
+
+	sess := &session{
+		player:           player,
+		report:           report,
+		sock:             sock,
+		received:         make(chan observed, 64),
+		recordedRestores: make(map[[8]byte]bool),
+		receivedRestores: make(map[[8]byte]bool),
+	}
+	go sess.readLoop()
+	defer func() {
+		sock.Close()
+		// Drain the reader
+		for range sess.received {
+		}
+	}()
+
+	for _, rec := range records {
+		if err := sess.play(rec); err != nil {
+			return err
+		}
+		if sess.closed {
+			break
+		}
+	}
+
+	// Report the received messages that weren't expected
+	for _, actual := range sess.pool {
+		report.Divergences = append(report.Divergences, Divergence{
+			ConnectionID: records[0].ConnectionID,
+			MsgType:      actual.msgType,
+			Actual:       actual.fields["type"],
+		})
+	}
+	return nil
+}
+
+// play replays a single record
+func (sess *session) play(rec capture.Record) error {
+	msg := sess.player.messages.Get()
+	defer msg.Close()
+
+	typeParsed, err := msg.ReadBytes(rec.Data)
+	if err != nil || !typeParsed {
+		return fmt.Errorf("corrupt record at %s", rec.Time)
+	}
+	if msg.MsgType == message.MsgHeartbeat {
+		// Heartbeats depend on timing
+		return nil
+	}
+
+	switch rec.Direction {
+	case capture.ClientToServer:
+		if err := sess.send(rec, msg); err != nil {
+			sess.report.Divergences = append(
+				sess.report.Divergences,
+				Divergence{
+					ConnectionID: rec.ConnectionID,
+					Time:         rec.Time,
+					MsgType:      msg.MsgType,
+					Field:        "connection",
+					Expected:     "open",
+					Actual:       err.Error(),
+				},
+			)
+			sess.closed = true
+			return nil
+		}
+		sess.report.Sent++
+
+	case capture.ServerToClient:
+		isSession := sess.isSession(sess.recordedRestores, msg)
+		sess.expect(rec, flatten(msg, isSession))
+
+	default:
+		return fmt.Errorf("invalid direction %d", rec.Direction)
+	}
+	return nil
+}
+
+// isSession returns true if the given message carries a session object.
+// Replies to the session restoration requests of the given set
+// are removed from it
+func (sess *session) isSession(
+	restores map[[8]byte]bool,
+	msg *message.Message,
+) bool {
+	switch msg.MsgType {
+	case message.MsgNotifySessionCreated:
+		return true
+	case message.MsgReplyUtf8, message.MsgReplyBinary:
+		sess.lock.Lock()
+		defer sess.lock.Unlock()
+		if restores[msg.MsgIdentifier] {
+			delete(restores, msg.MsgIdentifier)
+			return true
+		}
+	}
+	return false
+}
+
+// readLoop reads and flattens the messages received from the server
+// until the socket is closed
+func (sess *session) readLoop() {
+	defer close(sess.received)
+	for {
+		msg := sess.player.messages.Get()
+		if err := sess.sock.Read(msg, time.Time{}); err != nil {
+			msg.Close()
+			return
+		}
+		if msg.MsgType == message.MsgHeartbeat {
+			msg.Close()
+			continue
+		}
+		isSession := sess.isSession(sess.receivedRestores, msg)
+		received := flatten(msg, isSession)
+		msg.Close()
+		sess.received <- received
+	}
+}
+
+// send sends the given recorded client message. Recorded session keys
+// of restoration requests are replaced by the keys of the sessions
+// created during the replay
+func (sess *session) send(rec capture.Record, msg *message.Message) error {
+	writer, err := sess.sock.GetWriter()
+	if err != nil {
+		return err
+	}
+
+	if msg.MsgType != message.MsgRequestRestoreSession {
+		if _, err := writer.Write(rec.Data); err != nil {
+			if closeErr := writer.Close(); closeErr != nil {
+				return fmt.Errorf("%s: %s", err, closeErr)
+			}
+			return err
+		}
+		return writer.Close()
+	}
+
+	sess.lock.Lock()
+	sess.recordedRestores[msg.MsgIdentifier] = true
+	sess.receivedRestores[msg.MsgIdentifier] = true
+	sess.lock.Unlock()
+
+	key := string(msg.MsgPayload.Data)
+	if replayKey, mapped := sess.player.keys[key]; mapped {
+		key = replayKey
+	}
+	return message.WriteMsgNamelessRequest(
+		writer,
+		message.MsgRequestRestoreSession,
+		msg.MsgIdentifierBytes,
+		[]byte(key),
+	)
+}
+
+// expect waits for the message corresponding to the given recorded one
+// and compares them
+func (sess *session) expect(rec capture.Record, expected observed) {
+	actual, received := sess.await(expected)
+	if !received {
+		sess.report.Divergences = append(sess.report.Divergences, Divergence{
+			ConnectionID: rec.ConnectionID,
+			Time:         rec.Time,
+			MsgType:      expected.msgType,
+			Expected:     expected.fields["type"],
+		})
+		return
+	}
+	sess.report.Received++
+
+	if expected.sessionKey != "" && actual.sessionKey != "" {
+		sess.player.keys[expected.sessionKey] = actual.sessionKey
+	}
+
+	for _, field := range diff(
+		expected.fields,
+		actual.fields,
+		sess.player.opts.Ignore,
+	) {
+		sess.report.Divergences = append(sess.report.Divergences, Divergence{
+			ConnectionID: rec.ConnectionID,
+			Time:         rec.Time,
+			MsgType:      expected.msgType,
+			Field:        field,
+			Expected:     expected.fields[field],
+			Actual:       actual.fields[field],
+		})
+	}
+}
+
+// await returns the received message matching the expected one, either from
+// the pool of previously received messages or from the socket. Messages not
+// matching are added to the pool
+func (sess *session) await(expected observed) (observed, bool) {
+	for i, actual := range sess.pool {
+		if expected.matches(actual) {
+			sess.pool = append(sess.pool[:i], sess.pool[i+1:]...)
+			return actual, true
+		}
+	}
+
+	timer := time.NewTimer(sess.player.opts.Timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case actual, open := <-sess.received:
+			if !open {
+				return observed{}, false
+			}
+			if expected.matches(actual) {
+				return actual, true
+			}
+			sess.pool = append(sess.pool, actual)
+		case <-timer.C:
+			return observed{}, false
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package replay replays captured client sessions (see package capture)
+// against a server under test comparing the server's messages with the
+// recorded ones.
+//
+// Messages are compared field by field. Each message is flattened into
+// fields identified by slash-separated paths such as "type", "id", "name",
+// "encoding", "header/traceparent", "conf/readTimeout" or "payload". JSON
+// payloads are flattened into their values ("payload/user/name",
+// "payload/items/0"). Session objects, which are carried by session creation
+// notifications and session restoration replies, are flattened into "session"
+// fields instead ("session/k" is the session key). Fields are ignored using
+// path.Match patterns, by default the session keys and timestamps are ignored
+// (see DefaultIgnore).
+//
+// Session keys of sessions created during the replay are mapped to the
+// recorded ones so that recorded session restoration requests restore
+// the sessions created during the replay. This requires the JSON session codec.
+//
+// Captures are replayed either by a Player connecting to the server under test
+// over any client transport or by using a Transport as the server transport,
+// which replays the captured client sessions once the server is served
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// DefaultIgnore lists the fields ignored by default: the session keys as well
+// as the session creation and last lookup timestamps
+var DefaultIgnore = []string{"session/k", "session/c", "session/l"}
+
+// DefaultTimeout is the default duration to wait for an expected message
+const DefaultTimeout = 5 * time.Second
+
+// DefaultMessageBufferSize is the default size of the message buffers
+// messages are received and decoded into
+const DefaultMessageBufferSize = 1024 * 1024
+
+// Options represents the replay options
+type Options struct {
+	// Transport connects to the server under test
+	Transport wwr.ClientTransport
+
+	// Ignore lists the path.Match patterns of the ignored fields,
+	// DefaultIgnore is used if nil
+	Ignore []string
+
+	// Timeout defines how long to wait for each expected message,
+	// DefaultTimeout is used if zero
+	Timeout time.Duration
+
+	// MessageBufferSize defines the size of the message buffers,
+	// DefaultMessageBufferSize is used if zero
+	MessageBufferSize uint32
+}
+
+// Divergence represents a difference between a recorded message and the
+// message received from the server under test
+type Divergence struct {
+	// ConnectionID is the recorded ID of the connection
+	ConnectionID uint64
+
+	// Time is the recorded time of the message
+	Time time.Time
+
+	// MsgType is the type of the recorded message, or of the received
+	// message if it wasn't expected
+	MsgType byte
+
+	// Field is the path of the diverging field. It's empty if the message
+	// either wasn't received or wasn't expected
+	Field string
+
+	// Expected is the recorded value, empty if the field wasn't recorded
+	Expected string
+
+	// Actual is the received value, empty if the field wasn't received
+	Actual string
+}
+
+// String stringifies the divergence
+func (div Divergence) String() string {
+	switch {
+	case div.Field != "":
+		return fmt.Sprintf(
+			"connection %d: %s: %s: expected %s, got %s",
+			div.ConnectionID,
+			message.TypeName(div.MsgType),
+			div.Field,
+			orNone(div.Expected),
+			orNone(div.Actual),
+		)
+	case div.Actual == "":
+		return fmt.Sprintf(
+			"connection %d: %s recorded at %s wasn't received",
+			div.ConnectionID,
+			message.TypeName(div.MsgType),
+			div.Time.UTC().Format(time.RFC3339Nano),
+		)
+	}
+	return fmt.Sprintf(
+		"connection %d: unexpected %s",
+		div.ConnectionID,
+		message.TypeName(div.MsgType),
+	)
+}
+
+// orNone returns "<none>" for empty values
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// Report represents the result of a replay
+type Report struct {
+	// Sessions is the number of replayed sessions
+	Sessions int
+
+	// Sent is the number of messages sent to the server
+	Sent int
+
+	// Received is the number of expected messages received from the server
+	Received int
+
+	// Divergences lists the divergences in the order of the recorded messages
+	Divergences []Divergence
+}
+
+// Err returns an error listing all divergences or nil if there are none
+func (rep *Report) Err() error {
+	if len(rep.Divergences) < 1 {
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%d divergences:", len(rep.Divergences))
+	for _, div := range rep.Divergences {
+		buf.WriteString("\n")
+		buf.WriteString(div.String())
+	}
+	return errors.New(buf.String())
+}
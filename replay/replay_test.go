@@ -0,0 +1,86 @@
+package replay_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/replay"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDivergenceString tests stringifying divergences
+func TestDivergenceString(t *testing.T) {
+	require.Equal(
+		t,
+		`connection 1: MsgReplyUtf8: payload/name: expected "a", got <none>`,
+		replay.Divergence{
+			ConnectionID: 1,
+			MsgType:      message.MsgReplyUtf8,
+			Field:        "payload/name",
+			Expected:     `"a"`,
+		}.String(),
+	)
+	require.Equal(
+		t,
+		"connection 2: MsgReplySessionNotFound recorded at "+
+			"2017-07-14T02:40:00Z wasn't received",
+		replay.Divergence{
+			ConnectionID: 2,
+			Time:         time.Unix(1500000000, 0),
+			MsgType:      message.MsgReplySessionNotFound,
+			Expected:     "MsgReplySessionNotFound",
+		}.String(),
+	)
+	require.Equal(
+		t,
+		"connection 3: unexpected MsgSignalUtf8",
+		replay.Divergence{
+			ConnectionID: 3,
+			MsgType:      message.MsgSignalUtf8,
+			Actual:       "MsgSignalUtf8",
+		}.String(),
+	)
+}
+
+// TestReportErr tests the error of a replay report
+func TestReportErr(t *testing.T) {
+	require.NoError(t, (&replay.Report{Sessions: 1}).Err())
+
+	err := (&replay.Report{Divergences: []replay.Divergence{
+		{ConnectionID: 1, MsgType: message.MsgSignalUtf8, Actual: "x"},
+	}}).Err()
+	require.EqualError(
+		t,
+		err,
+		"1 divergences:\nconnection 1: unexpected MsgSignalUtf8",
+	)
+}
+
+// TestLoad tests loading captures
+func TestLoad(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRecord(capture.Record{
+		Time:         time.Unix(1500000000, 0),
+		Direction:    capture.ServerToClient,
+		ConnectionID: 1,
+		Data:         []byte{message.MsgHeartbeat},
+	}))
+
+	records, err := replay.Load(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	_, err = replay.Load(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	require.Error(t, err)
+}
+
+// TestNewPlayerMissingTransport tests creating a player without transport
+func TestNewPlayerMissingTransport(t *testing.T) {
+	_, err := replay.NewPlayer(replay.Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package replay
+
+import (
+	"errors"
+	"net/url"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/transport/memchan"
+)
+
+// Transport is a server transport replaying captured client sessions against
+// the server it's used by. The server is served over an in-memory memchan
+// transport and the records are replayed as soon as the server is served.
+// Wait returns the report once the replay completed
+type Transport struct {
+	// Records are the captured records to be replayed
+	Records []capture.Record
+
+	// Options defines the replay options, Options.Transport is ignored
+	Options Options
+
+	server *memchan.Transport
+	done   chan struct{}
+	report *Report
+	err    error
+}
+
+// Initialize implements the webwire.Transport interface
+func (trans *Transport) Initialize(
+	options wwr.ServerOptions,
+	isShuttingdown wwr.IsShuttingDown,
+	onNewConnection wwr.OnNewConnection,
+) error {
+	trans.server = &memchan.Transport{}
+	trans.done = make(chan struct{})
+	return trans.server.Initialize(options, isShuttingdown, onNewConnection)
+}
+
+// Serve implements the webwire.Transport interface
+func (trans *Transport) Serve() error {
+	if trans.server == nil {
+		return errors.New("server is not initialized")
+	}
+	go trans.play()
+	return trans.server.Serve()
+}
+
+// Shutdown implements the webwire.Transport interface
+func (trans *Transport) Shutdown() error {
+	if trans.server == nil {
+		return nil
+	}
+	return trans.server.Shutdown()
+}
+
+// Address implements the webwire.Transport interface
+func (trans *Transport) Address() url.URL {
+	return url.URL{
+		Scheme: "replay",
+	}
+}
+
+// play replays the records against the served server
+func (trans *Transport) play() {
+	defer close(trans.done)
+	opts := trans.Options
+	opts.Transport = &memchan.ClientTransport{Server: trans.server}
+	player, err := NewPlayer(opts)
+	if err != nil {
+		trans.err = err
+		return
+	}
+	trans.report, trans.err = player.Play(trans.Records)
+}
+
+// Wait blocks until the replay completed returning the report. An error is
+// only returned if a session couldn't be replayed (see Player.Play).
+// The server must be initialized and served before
+func (trans *Transport) Wait() (*Report, error) {
+	<-trans.done
+	return trans.report, trans.err
+}
@@ -25,6 +25,11 @@ func (rp *reply) PayloadUtf8() ([]byte, error) {
 	return rp.msg.MsgPayload.Utf8()
 }
 
+// Header implements the Reply interface
+func (rp *reply) Header(key string) []byte {
+	return rp.msg.Header(key)
+}
+
 // Close implements the Reply interface
 func (rp *reply) Close() {
 	rp.msg.Close()
@@ -0,0 +1,138 @@
+// Package router dispatches requests and signals to the handlers registered
+// for their message names. A router implements the OnRequest and OnSignal
+// hooks of the webwire.ServerImplementation interface
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// UnknownRequestCode defines the error code of the error replies to requests
+// no handler is registered for
+const UnknownRequestCode = "UNKNOWN_REQUEST"
+
+// NamespaceSeparator separates the namespace from the name of a message
+const NamespaceSeparator = "."
+
+// routes represents the handlers shared by a router and its namespaces
+type routes struct {
+	lock     sync.RWMutex
+	requests map[string]func(
+		context.Context,
+		wwr.Connection,
+		wwr.Message,
+	) (wwr.Payload, error)
+	signals map[string]func(context.Context, wwr.Connection, wwr.Message)
+}
+
+// Router dispatches requests and signals by their message names
+type Router struct {
+	prefix string
+	routes *routes
+}
+
+// New creates a new router
+func New() *Router {
+	return &Router{
+		routes: &routes{
+			requests: make(map[string]func(
+				context.Context,
+				wwr.Connection,
+				wwr.Message,
+			) (wwr.Payload, error)),
+			signals: make(map[string]func(
+				context.Context,
+				wwr.Connection,
+				wwr.Message,
+			)),
+		},
+	}
+}
+
+// Namespace returns a router registering handlers in the given namespace.
+// The names of messages in a namespace are prefixed by the namespace name
+// and the NamespaceSeparator
+func (r *Router) Namespace(name string) *Router {
+	return &Router{
+		prefix: r.prefix + name + NamespaceSeparator,
+		routes: r.routes,
+	}
+}
+
+// Request registers the request handler for the given message name.
+// Panics if a request handler is already registered for the name
+func (r *Router) Request(
+	name string,
+	handler func(
+		ctx context.Context,
+		connection wwr.Connection,
+		message wwr.Message,
+	) (wwr.Payload, error),
+) {
+	name = r.prefix + name
+	r.routes.lock.Lock()
+	defer r.routes.lock.Unlock()
+	if _, exists := r.routes.requests[name]; exists {
+		panic(fmt.Errorf("duplicate request handler for %q", name))
+	}
+	r.routes.requests[name] = handler
+}
+
+// Signal registers the signal handler for the given message name.
+// Panics if a signal handler is already registered for the name
+func (r *Router) Signal(
+	name string,
+	handler func(
+		ctx context.Context,
+		connection wwr.Connection,
+		message wwr.Message,
+	),
+) {
+	name = r.prefix + name
+	r.routes.lock.Lock()
+	defer r.routes.lock.Unlock()
+	if _, exists := r.routes.signals[name]; exists {
+		panic(fmt.Errorf("duplicate signal handler for %q", name))
+	}
+	r.routes.signals[name] = handler
+}
+
+// OnRequest implements the webwire.ServerImplementation interface
+// dispatching the request to the handler registered for its name. Requests
+// of unknown names are replied to with an UnknownRequestCode request error
+func (r *Router) OnRequest(
+	ctx context.Context,
+	connection wwr.Connection,
+	message wwr.Message,
+) (wwr.Payload, error) {
+	r.routes.lock.RLock()
+	handler, exists := r.routes.requests[string(message.Name())]
+	r.routes.lock.RUnlock()
+	if !exists {
+		return wwr.Payload{}, wwr.ErrRequest{
+			Code:    UnknownRequestCode,
+			Message: fmt.Sprintf("unknown request: %q", message.Name()),
+		}
+	}
+	return handler(ctx, connection, message)
+}
+
+// OnSignal implements the webwire.ServerImplementation interface
+// dispatching the signal to the handler registered for its name.
+// Signals of unknown names are dropped
+func (r *Router) OnSignal(
+	ctx context.Context,
+	connection wwr.Connection,
+	message wwr.Message,
+) {
+	r.routes.lock.RLock()
+	handler, exists := r.routes.signals[string(message.Name())]
+	r.routes.lock.RUnlock()
+	if exists {
+		handler(ctx, connection, message)
+	}
+}
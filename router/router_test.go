@@ -0,0 +1,122 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/router"
+	"github.com/stretchr/testify/require"
+)
+
+// testWriter is an io.WriteCloser implementation for testing purposes
+type testWriter struct {
+	buf []byte
+}
+
+// Write implements the io.WriteCloser interface
+func (tw *testWriter) Write(p []byte) (int, error) {
+	tw.buf = append(tw.buf, p...)
+	return len(p), nil
+}
+
+// Close implements the io.WriteCloser interface
+func (tw *testWriter) Close() error {
+	return nil
+}
+
+// newRequest creates a parsed request message of the given name
+func newRequest(t *testing.T, name string) *message.Message {
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{0, 0, 0, 0, 0, 0, 0, 1},
+		[]byte(name),
+		pld.Binary,
+		nil,
+		true,
+	))
+	msg := message.NewMessage(uint32(len(writer.buf)))
+	_, err := msg.ReadBytes(writer.buf)
+	require.NoError(t, err)
+	return msg
+}
+
+// newSignal creates a parsed signal message of the given name
+func newSignal(t *testing.T, name string) *message.Message {
+	writer := &testWriter{}
+	require.NoError(t, message.WriteMsgSignal(
+		writer,
+		[]byte(name),
+		pld.Binary,
+		[]byte{0},
+		true,
+	))
+	msg := message.NewMessage(uint32(len(writer.buf)))
+	_, err := msg.ReadBytes(writer.buf)
+	require.NoError(t, err)
+	return msg
+}
+
+// TestRouterRequests tests dispatching requests
+func TestRouterRequests(t *testing.T) {
+	r := router.New()
+	r.Request("a", func(
+		context.Context,
+		wwr.Connection,
+		wwr.Message,
+	) (wwr.Payload, error) {
+		return wwr.Payload{Data: []byte("a")}, nil
+	})
+	r.Namespace("ns").Request("b", func(
+		context.Context,
+		wwr.Connection,
+		wwr.Message,
+	) (wwr.Payload, error) {
+		return wwr.Payload{Data: []byte("ns.b")}, nil
+	})
+
+	reply, err := r.OnRequest(context.Background(), nil, newRequest(t, "a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), reply.Data)
+
+	reply, err = r.OnRequest(context.Background(), nil, newRequest(t, "ns.b"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("ns.b"), reply.Data)
+
+	// Expect unknown requests to be rejected
+	_, err = r.OnRequest(context.Background(), nil, newRequest(t, "b"))
+	require.IsType(t, wwr.ErrRequest{}, err)
+	require.Equal(t, router.UnknownRequestCode, err.(wwr.ErrRequest).Code)
+
+	// Expect duplicate registrations to panic
+	require.Panics(t, func() {
+		r.Namespace("ns").Request("b", nil)
+	})
+}
+
+// TestRouterSignals tests dispatching signals
+func TestRouterSignals(t *testing.T) {
+	var received []string
+	r := router.New()
+	r.Namespace("ns").Signal("s", func(
+		_ context.Context,
+		_ wwr.Connection,
+		msg wwr.Message,
+	) {
+		received = append(received, string(msg.Name()))
+	})
+
+	r.OnSignal(context.Background(), nil, newSignal(t, "ns.s"))
+
+	// Expect unknown signals to be dropped
+	r.OnSignal(context.Background(), nil, newSignal(t, "s"))
+
+	require.Equal(t, []string{"ns.s"}, received)
+
+	require.Panics(t, func() {
+		r.Signal("ns.s", nil)
+	})
+}
@@ -2,7 +2,6 @@ package webwire
 
 import (
 	"fmt"
-	"log"
 	"net/url"
 	"sync"
 
@@ -17,22 +16,27 @@ type server struct {
 	sessionManager    SessionManager
 	sessionKeyGen     SessionKeyGenerator
 	sessionInfoParser SessionInfoParser
+	sessionCodec      SessionCodec
 	addr              url.URL
 	options           ServerOptions
+	configuration     message.ServerConfiguration
 	configMsg         []byte
+	capabilities      message.Capabilities
 	shutdown          bool
 	shutdownRdy       chan bool
 	currentOps        uint32
 	opsLock           *sync.Mutex
 	connectionsLock   *sync.Mutex
-	connections       []*connection
+	connections       map[uint64]*connection
 	sessionsEnabled   bool
 	sessionRegistry   *sessionRegistry
+	sessionEvents     *sessionEventBus
 	messagePool       message.Pool
-
-	// Internals
-	warnLog  *log.Logger
-	errorLog *log.Logger
+	rateLimiter       *rateLimiter
+	connectionLimiter *connectionLimiter
+	metrics           Metrics
+	tracer            Tracer
+	logger            Logger
 }
 
 // shutdownServer initiates the shutdown of the underlying transport layer
@@ -85,6 +89,13 @@ func (srv *server) SessionConnections(sessionKey string) []Connection {
 	return srv.sessionRegistry.sessionConnections(sessionKey)
 }
 
+// SubscribeSessionEvents implements the Server interface
+func (srv *server) SubscribeSessionEvents(
+	subscriber SessionEventSubscriber,
+) (unsubscribe func()) {
+	return srv.sessionEvents.subscribe(subscriber)
+}
+
 // CloseSession implements the Server interface
 func (srv *server) CloseSession(sessionKey string) (
 	affectedConnections []Connection,
@@ -121,3 +132,123 @@ func (srv *server) CloseSession(sessionKey string) (
 
 	return affectedConnections, errors, generalError
 }
+
+// sessionSnapshot returns a copy of the session identified by the given key
+// taken from any of the given connections or nil if none of them has it
+func sessionSnapshot(key string, connections []*connection) *Session {
+	for _, conn := range connections {
+		if session := conn.Session(); session != nil && session.Key == key {
+			return session
+		}
+	}
+	return nil
+}
+
+// ActiveSessions implements the Server interface
+func (srv *server) ActiveSessions() []SessionSummary {
+	sessions := srv.sessionRegistry.activeSessions()
+	summaries := make([]SessionSummary, 0, len(sessions))
+	for key, connections := range sessions {
+		session := sessionSnapshot(key, connections)
+		if session == nil {
+			// The session was closed in the meantime
+			continue
+		}
+		summaries = append(summaries, SessionSummary{
+			Key:         key,
+			Connections: len(connections),
+			Creation:    session.Creation,
+		})
+	}
+	return summaries
+}
+
+// CloseSessions implements the Server interface
+func (srv *server) CloseSessions(predicate func(*Session) bool) (
+	affectedConnections []Connection,
+	errors []error,
+	generalError error,
+) {
+	errNum := 0
+	for key, connections := range srv.sessionRegistry.activeSessions() {
+		session := sessionSnapshot(key, connections)
+		if session == nil || !predicate(session) {
+			continue
+		}
+
+		affected, closeErrs, _ := srv.CloseSession(key)
+		affectedConnections = append(affectedConnections, affected...)
+		errors = append(errors, closeErrs...)
+		for _, err := range closeErrs {
+			if err != nil {
+				errNum++
+			}
+		}
+	}
+
+	if errNum > 0 {
+		generalError = fmt.Errorf(
+			"%d errors during the closure of sessions",
+			errNum,
+		)
+	}
+
+	return affectedConnections, errors, generalError
+}
+
+// CloseConnectionSession implements the Server interface
+func (srv *server) CloseConnectionSession(connectionID uint64) error {
+	srv.connectionsLock.Lock()
+	connection, exists := srv.connections[connectionID]
+	srv.connectionsLock.Unlock()
+
+	if !exists {
+		return ErrConnectionNotFound{ConnectionID: connectionID}
+	}
+	return connection.CloseSession()
+}
+
+// Options implements the Server interface
+func (srv *server) Options() ServerOptions {
+	return srv.options
+}
+
+// Connections implements the Server interface
+func (srv *server) Connections() []Connection {
+	srv.connectionsLock.Lock()
+	defer srv.connectionsLock.Unlock()
+
+	connections := make([]Connection, 0, len(srv.connections))
+	for _, connection := range srv.connections {
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
+// CloseConnection implements the Server interface
+func (srv *server) CloseConnection(connectionID uint64) error {
+	srv.connectionsLock.Lock()
+	connection, exists := srv.connections[connectionID]
+	srv.connectionsLock.Unlock()
+
+	if !exists {
+		return ErrConnectionNotFound{ConnectionID: connectionID}
+	}
+	connection.Close()
+	return nil
+}
+
+// CurrentOperations implements the Server interface
+func (srv *server) CurrentOperations() uint32 {
+	srv.opsLock.Lock()
+	defer srv.opsLock.Unlock()
+	return srv.currentOps
+}
+
+// MessagePoolStats implements the Server interface
+func (srv *server) MessagePoolStats() message.PoolStats {
+	if pool, ok := srv.messagePool.(message.StatsPool); ok {
+		return pool.Stats()
+	}
+	return message.PoolStats{}
+}
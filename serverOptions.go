@@ -1,6 +1,7 @@
 package webwire
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -29,9 +30,80 @@ type ServerOptions struct {
 	SessionKeyGenerator   SessionKeyGenerator
 	SessionInfoParser     SessionInfoParser
 	MaxSessionConnections uint
-	WarnLog               *log.Logger
-	ErrorLog              *log.Logger
-	ReadTimeout           time.Duration
+
+	// SessionEvictionPolicy defines what happens when a connection tries to
+	// restore a session that already reached MaxSessionConnections.
+	// The new connection is rejected by default (EvictionReject)
+	SessionEvictionPolicy SessionEvictionPolicy
+
+	// SessionCodec defines the codec used to encode session objects sent to
+	// the clients. JSON is used by default (see NewJSONSessionCodec)
+	SessionCodec SessionCodec
+
+	// HashSessionEventKeys enables replacing the session keys in session
+	// events by their hex encoded SHA-256 hashes to prevent leaking
+	// session keys to audit logs
+	HashSessionEventKeys bool
+
+	// MaxConnections defines the maximum number of concurrent connections.
+	// Zero stands for unlimited
+	MaxConnections uint
+
+	// MaxConnectionsPerIP defines the maximum number of concurrent
+	// connections per remote IP address. Zero stands for unlimited
+	MaxConnectionsPerIP uint
+
+	// OnConnectionRefused is called when an incoming connection is refused
+	// due to exceeding either MaxConnections or MaxConnectionsPerIP
+	OnConnectionRefused OnConnectionRefused
+
+	// ClientHello requires the clients to send a client hello message before
+	// the server configuration is sent to them. The client hello is used to
+	// negotiate the protocol version and capabilities. Disabled by default
+	ClientHello OptionValue
+
+	// Compression enables the DEFLATE compression of messages for clients
+	// supporting it, which requires ClientHello to be enabled for the
+	// compression to be negotiated. Disabled by default
+	Compression OptionValue
+
+	// CompressionThreshold defines the minimum size of messages in bytes
+	// to be compressed. Defaults to 512 bytes
+	CompressionThreshold uint32
+
+	// ValidateUtf8 enables the validation of UTF8 encoded request and signal
+	// payloads. Requests carrying malformed UTF8 payloads are rejected with
+	// a protocol error reply while such signals are dropped.
+	// Disabled by default
+	ValidateUtf8 OptionValue
+
+	// OnHandshake is called for every incoming connection before the
+	// server configuration is sent to the client and allows rejecting the
+	// connection or creating a session for it (see HandshakeResult)
+	OnHandshake OnHandshake
+
+	// RateLimits defines the rate limits applied to incoming requests
+	// and signals. Messages aren't limited by default
+	RateLimits RateLimits
+
+	// Metrics defines the metrics collector.
+	// Metrics are discarded by default (see NoopMetrics)
+	Metrics Metrics
+
+	// Tracer defines the tracer used to trace handled messages.
+	// Spans are discarded by default (see NoopTracer)
+	Tracer Tracer
+
+	// Logger defines the structured logger. By default log entries are
+	// written to WarnLog and ErrorLog (see NewStdLogger)
+	Logger Logger
+
+	// WarnLog and ErrorLog define the standard library loggers used if no
+	// Logger is specified
+	WarnLog  *log.Logger
+	ErrorLog *log.Logger
+
+	ReadTimeout time.Duration
 
 	// SubProtocolName defines the optional name of the hosted webwire
 	// sub-protocol
@@ -59,10 +131,44 @@ func (op *ServerOptions) Prepare() error {
 		op.SessionKeyGenerator = NewDefaultSessionKeyGenerator()
 	}
 
+	if op.ClientHello == OptionUnset {
+		op.ClientHello = Disabled
+	}
+
+	if op.Compression == OptionUnset {
+		op.Compression = Disabled
+	}
+
+	if op.Compression == Enabled && op.ClientHello != Enabled {
+		return errors.New(
+			"compression requires the client hello to be enabled",
+		)
+	}
+
+	if op.ValidateUtf8 == OptionUnset {
+		op.ValidateUtf8 = Disabled
+	}
+
+	if op.CompressionThreshold == 0 {
+		op.CompressionThreshold = 512
+	}
+
 	if op.SessionInfoParser == nil {
 		op.SessionInfoParser = GenericSessionInfoParser
 	}
 
+	if op.SessionCodec == nil {
+		op.SessionCodec = NewJSONSessionCodec()
+	}
+
+	if op.Metrics == nil {
+		op.Metrics = NoopMetrics{}
+	}
+
+	if op.Tracer == nil {
+		op.Tracer = NoopTracer{}
+	}
+
 	if op.ReadTimeout < 1*time.Second {
 		op.ReadTimeout = 60 * time.Second
 	}
@@ -82,6 +188,22 @@ func (op *ServerOptions) Prepare() error {
 			log.Ldate|log.Ltime|log.Lshortfile,
 		)
 	}
+	if op.Logger == nil {
+		op.Logger = NewStdLogger(op.WarnLog, op.ErrorLog)
+	}
+
+	switch op.SessionEvictionPolicy {
+	case EvictionReject, EvictionOldest, EvictionLeastRecentlyActive:
+	default:
+		return fmt.Errorf(
+			"invalid session eviction policy: %d",
+			op.SessionEvictionPolicy,
+		)
+	}
+
+	if err := op.RateLimits.validate(); err != nil {
+		return err
+	}
 
 	const minMsgBufferSize = 32
 
@@ -35,16 +35,6 @@ func generateSessionKey() string {
 	return base64.URLEncoding.EncodeToString(bytes)
 }
 
-// JSONEncodedSession represents a JSON encoded session object.
-// This structure is used during session restoration for unmarshalling
-// TODO: move to internal shared package
-type JSONEncodedSession struct {
-	Key        string                 `json:"k"`
-	Creation   time.Time              `json:"c"`
-	LastLookup time.Time              `json:"l"`
-	Info       map[string]interface{} `json:"i,omitempty"`
-}
-
 // Session represents a session object.
 // If the key is empty the session is invalid.
 // Info can contain arbitrary attached data
@@ -55,6 +45,18 @@ type Session struct {
 	Info       SessionInfo
 }
 
+// SessionSummary represents a summary of an active session
+type SessionSummary struct {
+	// Key is the key of the session
+	Key string
+
+	// Connections is the number of connections the session is active on
+	Connections int
+
+	// Creation is the creation time of the session
+	Creation time.Time
+}
+
 // Clone returns an exact copy of the session object
 func (s *Session) Clone() *Session {
 	if s == nil {
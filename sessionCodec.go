@@ -0,0 +1,150 @@
+package webwire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qbeon/webwire-go/compact"
+	"github.com/qbeon/webwire-go/message"
+)
+
+// EncodedSession represents the transferable representation of a session
+// object sent to the client in session creation notifications and session
+// restoration replies
+type EncodedSession struct {
+	Key        string                 `json:"k"`
+	Creation   time.Time              `json:"c"`
+	LastLookup time.Time              `json:"l"`
+	Info       map[string]interface{} `json:"i,omitempty"`
+}
+
+// JSONEncodedSession represents a JSON encoded session object.
+// This structure is used during session restoration for unmarshalling
+//
+// Deprecated: use EncodedSession instead
+type JSONEncodedSession = EncodedSession
+
+// SessionCodec defines the interface of a session object encoder/decoder.
+// The codec is advertised to the clients during the handshake
+type SessionCodec interface {
+	// Identifier returns the identifier of the codec advertised to the clients
+	Identifier() byte
+
+	// Encode encodes the given session object
+	Encode(session EncodedSession) ([]byte, error)
+
+	// Decode decodes the given encoded session object
+	Decode(data []byte) (EncodedSession, error)
+}
+
+// JSONSessionCodec implements the webwire.SessionCodec interface encoding
+// session objects as JSON. It's the default session codec
+type JSONSessionCodec struct{}
+
+// NewJSONSessionCodec constructs a new JSON session codec
+func NewJSONSessionCodec() SessionCodec {
+	return JSONSessionCodec{}
+}
+
+// Identifier implements the webwire.SessionCodec interface
+func (JSONSessionCodec) Identifier() byte {
+	return message.SessionCodecJSON
+}
+
+// Encode implements the webwire.SessionCodec interface
+func (JSONSessionCodec) Encode(session EncodedSession) ([]byte, error) {
+	return json.Marshal(&session)
+}
+
+// Decode implements the webwire.SessionCodec interface
+func (JSONSessionCodec) Decode(data []byte) (EncodedSession, error) {
+	var session EncodedSession
+	err := json.Unmarshal(data, &session)
+	return session, err
+}
+
+// BinarySessionCodec implements the webwire.SessionCodec interface encoding
+// session objects using the compact binary encoding (see package compact).
+// Contrary to JSON it preserves binary blobs, precise integer types and
+// timestamps in the session info.
+//
+// The session object is encoded as a list of 4 items:
+// key (string), creation (time), last lookup (time) and info (map or nil)
+type BinarySessionCodec struct{}
+
+// NewBinarySessionCodec constructs a new binary session codec
+func NewBinarySessionCodec() SessionCodec {
+	return BinarySessionCodec{}
+}
+
+// Identifier implements the webwire.SessionCodec interface
+func (BinarySessionCodec) Identifier() byte {
+	return message.SessionCodecBinary
+}
+
+// Encode implements the webwire.SessionCodec interface
+func (BinarySessionCodec) Encode(session EncodedSession) ([]byte, error) {
+	var info interface{}
+	if session.Info != nil {
+		info = session.Info
+	}
+	return compact.Marshal([]interface{}{
+		session.Key,
+		session.Creation,
+		session.LastLookup,
+		info,
+	})
+}
+
+// Decode implements the webwire.SessionCodec interface
+func (BinarySessionCodec) Decode(data []byte) (EncodedSession, error) {
+	var items []interface{}
+	if err := compact.Unmarshal(data, &items); err != nil {
+		return EncodedSession{}, err
+	}
+	if len(items) != 4 {
+		return EncodedSession{}, fmt.Errorf(
+			"invalid number of session object items: %d",
+			len(items),
+		)
+	}
+
+	key, isString := items[0].(string)
+	if !isString {
+		return EncodedSession{}, errors.New("invalid session key")
+	}
+	creation, isTime := items[1].(time.Time)
+	if !isTime {
+		return EncodedSession{}, errors.New("invalid session creation time")
+	}
+	lastLookup, isTime := items[2].(time.Time)
+	if !isTime {
+		return EncodedSession{}, errors.New("invalid session last lookup time")
+	}
+
+	var info map[string]interface{}
+	if items[3] != nil {
+		var isMap bool
+		if info, isMap = items[3].(map[string]interface{}); !isMap {
+			return EncodedSession{}, errors.New("invalid session info")
+		}
+	}
+
+	return EncodedSession{
+		Key:        key,
+		Creation:   creation,
+		LastLookup: lastLookup,
+		Info:       info,
+	}, nil
+}
+
+// sessionPayloadEncoding returns the payload encoding of session objects
+// encoded by the given codec
+func sessionPayloadEncoding(codec SessionCodec) PayloadEncoding {
+	if codec.Identifier() == message.SessionCodecJSON {
+		return EncodingUtf8
+	}
+	return EncodingBinary
+}
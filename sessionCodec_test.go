@@ -0,0 +1,76 @@
+package webwire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBinarySessionCodecRoundTrip tests encoding and decoding session objects
+// using the binary session codec
+func TestBinarySessionCodecRoundTrip(t *testing.T) {
+	codec := NewBinarySessionCodec()
+	original := EncodedSession{
+		Key:        "sessionkey",
+		Creation:   time.Date(2018, 10, 1, 12, 0, 0, 1, time.UTC),
+		LastLookup: time.Date(2018, 10, 2, 12, 0, 0, 2, time.UTC),
+		Info: map[string]interface{}{
+			"blob":  []byte{0, 255},
+			"count": int64(-1),
+			"uid":   "user",
+		},
+	}
+
+	encoded, err := codec.Encode(original)
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, original, decoded)
+
+	// Nil session info
+	original.Info = nil
+	encoded, err = codec.Encode(original)
+	require.NoError(t, err)
+
+	decoded, err = codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, original, decoded)
+}
+
+// TestBinarySessionCodecDecodeInvalid tests decoding invalid session objects
+// using the binary session codec
+func TestBinarySessionCodecDecodeInvalid(t *testing.T) {
+	codec := NewBinarySessionCodec()
+
+	_, err := codec.Decode([]byte{})
+	require.Error(t, err)
+
+	// Wrong number of items
+	encoded, err := codec.Encode(EncodedSession{})
+	require.NoError(t, err)
+	_, err = codec.Decode(encoded[:len(encoded)-1])
+	require.Error(t, err)
+}
+
+// TestJSONSessionCodecRoundTrip tests encoding and decoding session objects
+// using the JSON session codec
+func TestJSONSessionCodecRoundTrip(t *testing.T) {
+	codec := NewJSONSessionCodec()
+	original := EncodedSession{
+		Key:        "sessionkey",
+		Creation:   time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC),
+		LastLookup: time.Date(2018, 10, 2, 12, 0, 0, 0, time.UTC),
+		Info: map[string]interface{}{
+			"uid": "user",
+		},
+	}
+
+	encoded, err := codec.Encode(original)
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, original, decoded)
+}
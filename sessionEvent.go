@@ -0,0 +1,139 @@
+package webwire
+
+import (
+	"net"
+	"time"
+)
+
+// SessionEventType represents the type of a session lifecycle event
+type SessionEventType byte
+
+const (
+	// SessionEventCreated is published when a new session was created
+	SessionEventCreated SessionEventType = iota + 1
+
+	// SessionEventRestored is published when a session was successfully
+	// restored on a connection
+	SessionEventRestored
+
+	// SessionEventRestoreFailed is published when a session restoration
+	// request failed. The outcome describes the reason of the failure
+	SessionEventRestoreFailed
+
+	// SessionEventMaxConnsRejected is published when a session restoration
+	// request was rejected because the session already reached the maximum
+	// number of concurrent connections
+	SessionEventMaxConnsRejected
+
+	// SessionEventEvicted is published when a connection was evicted from a
+	// session according to the session eviction policy
+	SessionEventEvicted
+
+	// SessionEventClosed is published when a session was closed on a
+	// connection either by the server or by the client
+	SessionEventClosed
+
+	// SessionEventAttached is published when a connection was attached to
+	// a session either due to the creation or the restoration of the session
+	SessionEventAttached
+
+	// SessionEventDetached is published when a connection was detached from
+	// a session either due to the closure of the session, an eviction or
+	// the connection being closed
+	SessionEventDetached
+)
+
+// String stringifies the session event type
+func (typ SessionEventType) String() string {
+	switch typ {
+	case SessionEventCreated:
+		return "created"
+	case SessionEventRestored:
+		return "restored"
+	case SessionEventRestoreFailed:
+		return "restore-failed"
+	case SessionEventMaxConnsRejected:
+		return "max-conns-rejected"
+	case SessionEventEvicted:
+		return "evicted"
+	case SessionEventClosed:
+		return "closed"
+	case SessionEventAttached:
+		return "attached"
+	case SessionEventDetached:
+		return "detached"
+	}
+	return ""
+}
+
+// SessionEventOutcome describes the outcome of the operation
+// a session event was published for
+type SessionEventOutcome string
+
+const (
+	// SessionOutcomeSuccess represents a successful operation
+	SessionOutcomeSuccess SessionEventOutcome = "success"
+
+	// SessionOutcomeNotFound represents a failed restoration
+	// of a session that wasn't found
+	SessionOutcomeNotFound SessionEventOutcome = "not-found"
+
+	// SessionOutcomeLookupFailed represents a failed restoration
+	// due to a failing session manager lookup hook
+	SessionOutcomeLookupFailed SessionEventOutcome = "lookup-failed"
+
+	// SessionOutcomeEncodingFailed represents a failed restoration
+	// due to the session object failing to be encoded
+	SessionOutcomeEncodingFailed SessionEventOutcome = "encoding-failed"
+
+	// SessionOutcomeSessionsDisabled represents a failed restoration
+	// due to sessions being disabled
+	SessionOutcomeSessionsDisabled SessionEventOutcome = "sessions-disabled"
+
+	// SessionOutcomeMaxConnsReached represents a rejected restoration
+	// due to the session reaching the maximum number of connections
+	SessionOutcomeMaxConnsReached SessionEventOutcome = "max-conns-reached"
+
+	// SessionOutcomeDestroyed represents a session closure that destroyed the
+	// session because the connection was the last one remaining
+	SessionOutcomeDestroyed SessionEventOutcome = "destroyed"
+
+	// SessionOutcomeEvicted represents a detachment due to an eviction
+	SessionOutcomeEvicted SessionEventOutcome = "evicted"
+
+	// SessionOutcomeDisconnected represents a detachment
+	// due to the connection being closed
+	SessionOutcomeDisconnected SessionEventOutcome = "disconnected"
+)
+
+// SessionEvent represents a session lifecycle event
+type SessionEvent struct {
+	// Type defines the type of the event
+	Type SessionEventType
+
+	// SessionKey is the key of the session the event refers to.
+	// It's the hex encoded SHA-256 hash of the key if
+	// ServerOptions.HashSessionEventKeys is enabled
+	SessionKey string
+
+	// ConnectionID is the identifier of the connection
+	// the event was caused by, see Connection.ID
+	ConnectionID uint64
+
+	// RemoteAddr is the remote address of the connection
+	RemoteAddr net.Addr
+
+	// Time is the time the event occurred at
+	Time time.Time
+
+	// Outcome describes the outcome of the operation
+	Outcome SessionEventOutcome
+
+	// Err is the error that caused the operation to fail, if any
+	Err error
+}
+
+// SessionEventSubscriber represents the type of a session event subscriber
+// function. Subscribers are invoked synchronously by the goroutine that
+// caused the event and must therefore not block
+type SessionEventSubscriber func(event SessionEvent)
@@ -0,0 +1,101 @@
+package webwire
+
+import (
+	"sync"
+	"time"
+)
+
+// sessionEventBus represents a thread safe session event dispatcher
+type sessionEventBus struct {
+	lock        sync.RWMutex
+	lastID      uint64
+	subscribers map[uint64]SessionEventSubscriber
+	hashKeys    bool
+	logger      Logger
+}
+
+// newSessionEventBus creates a new session event bus instance
+func newSessionEventBus(hashKeys bool, logger Logger) *sessionEventBus {
+	return &sessionEventBus{
+		subscribers: make(map[uint64]SessionEventSubscriber),
+		hashKeys:    hashKeys,
+		logger:      logger,
+	}
+}
+
+// subscribe registers the given subscriber
+// and returns a function removing it again
+func (bus *sessionEventBus) subscribe(
+	subscriber SessionEventSubscriber,
+) (unsubscribe func()) {
+	bus.lock.Lock()
+	bus.lastID++
+	id := bus.lastID
+	bus.subscribers[id] = subscriber
+	bus.lock.Unlock()
+
+	return func() {
+		bus.lock.Lock()
+		delete(bus.subscribers, id)
+		bus.lock.Unlock()
+	}
+}
+
+// publish dispatches a new event to all subscribers.
+// Does nothing if there are no subscribers
+func (bus *sessionEventBus) publish(
+	eventType SessionEventType,
+	con *connection,
+	sessionKey string,
+	outcome SessionEventOutcome,
+	err error,
+) {
+	bus.lock.RLock()
+	if len(bus.subscribers) < 1 {
+		bus.lock.RUnlock()
+		return
+	}
+	subscribers := make([]SessionEventSubscriber, 0, len(bus.subscribers))
+	for _, subscriber := range bus.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	bus.lock.RUnlock()
+
+	if bus.hashKeys {
+		sessionKey = HashSessionKey(sessionKey)
+	}
+
+	event := SessionEvent{
+		Type:       eventType,
+		SessionKey: sessionKey,
+		Time:       time.Now(),
+		Outcome:    outcome,
+		Err:        err,
+	}
+	if con != nil {
+		event.ConnectionID = con.ID()
+		event.RemoteAddr = con.RemoteAddr()
+	}
+
+	for _, subscriber := range subscribers {
+		bus.dispatch(subscriber, event)
+	}
+}
+
+// dispatch invokes the given subscriber
+// recovering potential user-space panics
+func (bus *sessionEventBus) dispatch(
+	subscriber SessionEventSubscriber,
+	event SessionEvent,
+) {
+	defer func() {
+		if recvErr := recover(); recvErr != nil {
+			bus.logger.Log(
+				LogLevelError,
+				"session event subscriber panic",
+				LogField{"panic", recvErr},
+			)
+		}
+	}()
+	subscriber(event)
+}
@@ -0,0 +1,43 @@
+package webwire
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionEventBusUnsubscribe tests canceling session event subscriptions
+func TestSessionEventBusUnsubscribe(t *testing.T) {
+	bus := newSessionEventBus(false, nil)
+
+	received := 0
+	unsubscribe := bus.subscribe(func(event SessionEvent) {
+		received++
+		require.Equal(t, SessionEventCreated, event.Type)
+		require.Equal(t, "key", event.SessionKey)
+	})
+
+	bus.publish(SessionEventCreated, nil, "key", SessionOutcomeSuccess, nil)
+	require.Equal(t, 1, received)
+
+	unsubscribe()
+	bus.publish(SessionEventCreated, nil, "key", SessionOutcomeSuccess, nil)
+	require.Equal(t, 1, received)
+}
+
+// TestSessionEventBusSubscriberPanic tests whether panicking subscribers are
+// recovered without affecting other subscribers
+func TestSessionEventBusSubscriberPanic(t *testing.T) {
+	logs := &bytes.Buffer{}
+	bus := newSessionEventBus(false, NewStdLogger(nil, log.New(logs, "", 0)))
+
+	bus.subscribe(func(SessionEvent) { panic("subscriber failure") })
+	received := false
+	bus.subscribe(func(SessionEvent) { received = true })
+
+	bus.publish(SessionEventClosed, nil, "key", SessionOutcomeSuccess, nil)
+	require.True(t, received)
+	require.Contains(t, logs.String(), "subscriber failure")
+}
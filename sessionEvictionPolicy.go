@@ -0,0 +1,34 @@
+package webwire
+
+// SessionEvictionPolicy defines how the server treats session restoration
+// requests for sessions that already reached the maximum number of concurrent
+// connections defined by ServerOptions.MaxSessionConnections
+type SessionEvictionPolicy byte
+
+const (
+	// EvictionReject rejects the restoration request of the new connection
+	// with an ErrMaxSessConnsReached error. This is the default policy
+	EvictionReject SessionEvictionPolicy = iota
+
+	// EvictionOldest closes the session on the oldest connection of the
+	// session making room for the new connection
+	EvictionOldest
+
+	// EvictionLeastRecentlyActive closes the session on the connection of the
+	// session that received a message least recently making room for the new
+	// connection
+	EvictionLeastRecentlyActive
+)
+
+// String stringifies the eviction policy
+func (policy SessionEvictionPolicy) String() string {
+	switch policy {
+	case EvictionReject:
+		return "reject"
+	case EvictionOldest:
+		return "evict-oldest"
+	case EvictionLeastRecentlyActive:
+		return "evict-least-recently-active"
+	}
+	return ""
+}
@@ -3,16 +3,18 @@ package webwire
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // sessionRegistry represents a thread safe registry
 // of all currently active sessions
 type sessionRegistry struct {
-	server           *server
 	lock             *sync.RWMutex
 	maxConns         uint
 	registry         map[string]map[*connection]struct{}
 	onSessionDestroy func(sessionKey string)
+	metrics          Metrics
+	logger           Logger
 }
 
 // newSessionRegistry returns a new instance of a session registry.
@@ -27,6 +29,8 @@ func newSessionRegistry(
 		maxConns:         maxConns,
 		registry:         make(map[string]map[*connection]struct{}),
 		onSessionDestroy: onSessionDestroy,
+		metrics:          NoopMetrics{},
+		logger:           NewStdLogger(nil, nil),
 	}
 }
 
@@ -55,10 +59,75 @@ func (asr *sessionRegistry) register(con *connection) error {
 		con: {},
 	}
 	asr.registry[con.session.Key] = newList
+	// Report while locked to keep the reports in order
+	asr.metrics.ActiveSessions(len(asr.registry))
 	asr.lock.Unlock()
 	return nil
 }
 
+// registerEvicting registers a new connection for the given clients session
+// just like register does. If the session already reached the maximum number
+// of concurrent connections then another connection of the session is chosen
+// according to the given eviction policy and removed from the registry making
+// room for the new connection. The evicted connection is returned and must be
+// notified about the eviction by the caller.
+// Returns an error if the policy is EvictionReject and the session already
+// reached the maximum number of concurrent connections
+func (asr *sessionRegistry) registerEvicting(
+	con *connection,
+	policy SessionEvictionPolicy,
+) (evicted *connection, err error) {
+	if policy == EvictionReject {
+		return nil, asr.register(con)
+	}
+
+	asr.lock.Lock()
+	connSet, exists := asr.registry[con.session.Key]
+	if !exists {
+		asr.registry[con.session.Key] = map[*connection]struct{}{
+			con: {},
+		}
+		asr.metrics.ActiveSessions(len(asr.registry))
+		asr.lock.Unlock()
+		return nil, nil
+	}
+
+	// Evict only when the new connection doesn't fit into the session
+	if _, isMember := connSet[con]; !isMember &&
+		asr.maxConns > 0 &&
+		uint(len(connSet)+1) > asr.maxConns {
+		evicted = evictionCandidate(connSet, policy)
+		delete(connSet, evicted)
+	}
+	connSet[con] = struct{}{}
+	asr.lock.Unlock()
+
+	return evicted, nil
+}
+
+// evictionCandidate returns the connection of the given set
+// that is to be evicted according to the given policy
+func evictionCandidate(
+	connSet map[*connection]struct{},
+	policy SessionEvictionPolicy,
+) (candidate *connection) {
+	var candidateTime time.Time
+	for conn := range connSet {
+		var connTime time.Time
+		switch policy {
+		case EvictionOldest:
+			connTime = conn.Creation()
+		case EvictionLeastRecentlyActive:
+			connTime = conn.lastActive()
+		}
+		if candidate == nil || connTime.Before(candidateTime) {
+			candidate = conn
+			candidateTime = connTime
+		}
+	}
+	return candidate
+}
+
 // deregister removes a connection from the list of connections of a session
 // and returns the number of connections left.
 // If there's only one connection left then the entire session will be removed
@@ -76,9 +145,17 @@ func (asr *sessionRegistry) deregister(
 
 	asr.lock.Lock()
 	if connSet, exists := asr.registry[conn.session.Key]; exists {
+		// Ignore connections that were already removed from the session
+		// (for example evicted ones)
+		if _, isMember := connSet[conn]; !isMember {
+			asr.lock.Unlock()
+			return -1
+		}
+
 		// If a single connection is left then remove or destroy the session
 		if len(connSet) < 2 {
 			delete(asr.registry, conn.session.Key)
+			asr.metrics.ActiveSessions(len(asr.registry))
 			asr.lock.Unlock()
 
 			// Destroy the session
@@ -87,9 +164,14 @@ func (asr *sessionRegistry) deregister(
 				// to avoid panicking the server
 				defer func() {
 					if recvErr := recover(); recvErr != nil {
-						asr.server.errorLog.Printf(
-							"session closure hook panic: %v",
-							recvErr,
+						asr.logger.Log(
+							LogLevelError,
+							"session closure hook panic",
+							LogField{
+								LogKeySessionKeyHash,
+								HashSessionKey(conn.session.Key),
+							},
+							LogField{"panic", recvErr},
 						)
 					}
 				}()
@@ -149,3 +231,19 @@ func (asr *sessionRegistry) sessionConnections(
 	asr.lock.RUnlock()
 	return nil
 }
+
+// activeSessions returns a snapshot of all currently active sessions
+// and their connections
+func (asr *sessionRegistry) activeSessions() map[string][]*connection {
+	asr.lock.RLock()
+	sessions := make(map[string][]*connection, len(asr.registry))
+	for key, connSet := range asr.registry {
+		list := make([]*connection, 0, len(connSet))
+		for conn := range connSet {
+			list = append(list, conn)
+		}
+		sessions[key] = list
+	}
+	asr.lock.RUnlock()
+	return sessions
+}
@@ -4,6 +4,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -104,6 +105,72 @@ func TestSessRegSessionMaxConns(t *testing.T) {
 	)
 }
 
+// TestSessRegRegisterEvictingOldest tests the registerEvicting method using
+// the EvictionOldest policy
+func TestSessRegRegisterEvictingOldest(t *testing.T) {
+	// Set the maximum number of concurrent session connection to 2
+	reg := newSessionRegistry(2, nil)
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	cltA1 := newConnection(nil, nil, ConnectionOptions{})
+	cltA1.info.Creation = time.Now().Add(-2 * time.Second)
+	cltA1.session = &sess
+
+	cltA2 := newConnection(nil, nil, ConnectionOptions{})
+	cltA2.info.Creation = time.Now().Add(-1 * time.Second)
+	cltA2.session = &sess
+
+	cltA3 := newConnection(nil, nil, ConnectionOptions{})
+	cltA3.session = &sess
+
+	evicted, err := reg.registerEvicting(cltA1, EvictionOldest)
+	require.NoError(t, err)
+	require.Nil(t, evicted)
+
+	evicted, err = reg.registerEvicting(cltA2, EvictionOldest)
+	require.NoError(t, err)
+	require.Nil(t, evicted)
+
+	// Expect the oldest connection to be evicted
+	evicted, err = reg.registerEvicting(cltA3, EvictionOldest)
+	require.NoError(t, err)
+	require.Equal(t, cltA1, evicted)
+	require.Equal(t, 2, reg.sessionConnectionsNum("testkey_A"))
+
+	// Expect deregistration of the evicted connection to be ignored
+	require.Equal(t, -1, reg.deregister(cltA1, false))
+	require.Equal(t, 2, reg.sessionConnectionsNum("testkey_A"))
+}
+
+// TestSessRegRegisterEvictingLeastRecentlyActive tests the registerEvicting
+// method using the EvictionLeastRecentlyActive policy
+func TestSessRegRegisterEvictingLeastRecentlyActive(t *testing.T) {
+	// Set the maximum number of concurrent session connection to 2
+	reg := newSessionRegistry(2, nil)
+	sess := NewSession(nil, func() string { return "testkey_A" })
+
+	cltA1 := newConnection(nil, nil, ConnectionOptions{})
+	cltA1.session = &sess
+
+	cltA2 := newConnection(nil, nil, ConnectionOptions{})
+	cltA2.lastActivity = time.Now().Add(-1 * time.Second).UnixNano()
+	cltA2.session = &sess
+
+	cltA3 := newConnection(nil, nil, ConnectionOptions{})
+	cltA3.session = &sess
+
+	_, err := reg.registerEvicting(cltA1, EvictionLeastRecentlyActive)
+	require.NoError(t, err)
+	_, err = reg.registerEvicting(cltA2, EvictionLeastRecentlyActive)
+	require.NoError(t, err)
+
+	// Expect the least recently active connection to be evicted
+	evicted, err := reg.registerEvicting(cltA3, EvictionLeastRecentlyActive)
+	require.NoError(t, err)
+	require.Equal(t, cltA2, evicted)
+	require.Equal(t, 2, reg.sessionConnectionsNum("testkey_A"))
+}
+
 // TestSessRegDeregistration tests deregistration
 func TestSessRegDeregistration(t *testing.T) {
 	reg := newSessionRegistry(0, nil)
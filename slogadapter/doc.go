@@ -0,0 +1,3 @@
+// Package slogadapter implements a webwire.Logger writing to a log/slog
+// handler. It requires Go 1.21 or newer
+package slogadapter
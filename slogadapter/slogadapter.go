@@ -0,0 +1,73 @@
+//go:build go1.21
+// +build go1.21
+
+package slogadapter
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// Logger implements the webwire.Logger interface
+// writing records to a log/slog handler
+type Logger struct {
+	handler slog.Handler
+}
+
+// New creates a new webwire.Logger writing records to the given handler
+func New(handler slog.Handler) *Logger {
+	return &Logger{handler: handler}
+}
+
+// Level converts the given webwire log level to a slog level
+func Level(level wwr.LogLevel) slog.Level {
+	switch level {
+	case wwr.LogLevelDebug:
+		return slog.LevelDebug
+	case wwr.LogLevelInfo:
+		return slog.LevelInfo
+	case wwr.LogLevelWarn:
+		return slog.LevelWarn
+	case wwr.LogLevelError:
+		return slog.LevelError
+	}
+	return slog.Level(int(level) * 4)
+}
+
+// Log implements the webwire.Logger interface
+func (lg *Logger) Log(
+	level wwr.LogLevel,
+	message string,
+	fields ...wwr.LogField,
+) {
+	ctx := context.Background()
+	slogLevel := Level(level)
+	if !lg.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	// Skip runtime.Callers, Log and report the caller of Log
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+
+	record := slog.NewRecord(time.Now(), slogLevel, message, pcs[0])
+	for _, field := range fields {
+		record.AddAttrs(attr(field))
+	}
+	lg.handler.Handle(ctx, record)
+}
+
+// attr converts the given log field to a slog attribute
+func attr(field wwr.LogField) slog.Attr {
+	if err, isErr := field.Value.(error); isErr {
+		return slog.String(field.Key, err.Error())
+	}
+	return slog.Any(field.Key, field.Value)
+}
+
+// Make sure the logger implements the webwire.Logger interface
+var _ wwr.Logger = (*Logger)(nil)
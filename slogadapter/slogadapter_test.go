@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+package slogadapter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/slogadapter"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogger tests writing log entries to a slog handler
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slogadapter.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+
+	// Expect debug entries to be discarded
+	logger.Log(wwr.LogLevelDebug, "discarded")
+	require.Equal(t, 0, buf.Len())
+
+	logger.Log(
+		wwr.LogLevelError,
+		"sample",
+		wwr.LogField{Key: wwr.LogKeyConnectionID, Value: uint64(42)},
+		wwr.LogField{Key: wwr.LogKeyError, Value: errors.New("failure")},
+	)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "ERROR", record["level"])
+	require.Equal(t, "sample", record["msg"])
+	require.Equal(t, float64(42), record[wwr.LogKeyConnectionID])
+	require.Equal(t, "failure", record[wwr.LogKeyError])
+}
@@ -0,0 +1,431 @@
+package webwire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// valueCopier deep-copies the original value into the given settable copy
+type valueCopier func(original, cpy reflect.Value)
+
+// compileCopier reflects the given type once and returns a deep-copy function
+// specialized for it. Recursive types are resolved through the compiled cache
+func compileCopier(
+	typ reflect.Type,
+	compiled map[reflect.Type]*valueCopier,
+) *valueCopier {
+	if copier, exists := compiled[typ]; exists {
+		return copier
+	}
+	copier := new(valueCopier)
+	compiled[typ] = copier
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elemCopier := compileCopier(typ.Elem(), compiled)
+		*copier = func(original, cpy reflect.Value) {
+			if original.IsNil() {
+				return
+			}
+			ptr := reflect.New(typ.Elem())
+			(*elemCopier)(original.Elem(), ptr.Elem())
+			cpy.Set(ptr)
+		}
+
+	case reflect.Slice:
+		elemCopier := compileCopier(typ.Elem(), compiled)
+		*copier = func(original, cpy reflect.Value) {
+			if original.IsNil() {
+				return
+			}
+			slice := reflect.MakeSlice(typ, original.Len(), original.Len())
+			for i := 0; i < original.Len(); i++ {
+				(*elemCopier)(original.Index(i), slice.Index(i))
+			}
+			cpy.Set(slice)
+		}
+
+	case reflect.Array:
+		elemCopier := compileCopier(typ.Elem(), compiled)
+		*copier = func(original, cpy reflect.Value) {
+			for i := 0; i < original.Len(); i++ {
+				(*elemCopier)(original.Index(i), cpy.Index(i))
+			}
+		}
+
+	case reflect.Map:
+		elemCopier := compileCopier(typ.Elem(), compiled)
+		*copier = func(original, cpy reflect.Value) {
+			if original.IsNil() {
+				return
+			}
+			mp := reflect.MakeMapWithSize(typ, original.Len())
+			for _, key := range original.MapKeys() {
+				val := reflect.New(typ.Elem()).Elem()
+				(*elemCopier)(original.MapIndex(key), val)
+				mp.SetMapIndex(key, val)
+			}
+			cpy.Set(mp)
+		}
+
+	case reflect.Struct:
+		type fieldCopier struct {
+			index  int
+			copier *valueCopier
+		}
+		fields := make([]fieldCopier, 0, typ.NumField())
+		for i := 0; i < typ.NumField(); i++ {
+			// Unexported fields can't be set and are copied shallowly
+			if typ.Field(i).PkgPath != "" {
+				continue
+			}
+			fields = append(fields, fieldCopier{
+				index:  i,
+				copier: compileCopier(typ.Field(i).Type, compiled),
+			})
+		}
+		*copier = func(original, cpy reflect.Value) {
+			cpy.Set(original)
+			for _, field := range fields {
+				(*field.copier)(
+					original.Field(field.index),
+					cpy.Field(field.index),
+				)
+			}
+		}
+
+	case reflect.Interface:
+		// The dynamic type of interfaces is only known at runtime
+		*copier = func(original, cpy reflect.Value) {
+			if original.IsNil() {
+				return
+			}
+			cpy.Set(reflect.ValueOf(deepCopy(original.Interface())))
+		}
+
+	default:
+		*copier = func(original, cpy reflect.Value) {
+			cpy.Set(original)
+		}
+	}
+
+	return copier
+}
+
+// isNumericKind returns true if the given kind represents a number
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertNumber converts the given numeric value to the type of the given
+// settable numeric target value. Returns an error if the value can't be
+// represented by the target type without truncation or overflow
+func convertNumber(target, value reflect.Value) error {
+	invalid := fmt.Errorf(
+		"%v can't be represented as %s",
+		value.Interface(),
+		target.Type(),
+	)
+
+	switch target.Kind() {
+	case reflect.Float32, reflect.Float64:
+		// Floating point targets only reject overflowing values
+		if isFloatKind(value.Kind()) && target.OverflowFloat(value.Float()) {
+			return invalid
+		}
+		target.Set(value.Convert(target.Type()))
+		return nil
+	}
+
+	// Integer targets reject negative values for unsigned types
+	// as well as fractional and overflowing values
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 && isUnsignedKind(target.Kind()) {
+			return invalid
+		}
+	case reflect.Float32, reflect.Float64:
+		num := value.Float()
+		if num != math.Trunc(num) ||
+			num < math.MinInt64 || num >= math.MaxUint64 ||
+			(num < 0 && isUnsignedKind(target.Kind())) {
+			return invalid
+		}
+	}
+
+	converted := value.Convert(target.Type())
+	if converted.Convert(value.Type()).Interface() != value.Interface() {
+		return invalid
+	}
+	target.Set(converted)
+	return nil
+}
+
+// isFloatKind returns true if the given kind represents a floating point number
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// isUnsignedKind returns true if the given kind represents an unsigned integer
+func isUnsignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// assignVariant assigns a JSON compliant variant value to the given settable
+// target value converting it to the type of the target
+func assignVariant(target reflect.Value, variant interface{}) error {
+	if variant == nil {
+		return nil
+	}
+	value := reflect.ValueOf(variant)
+
+	if value.Type().AssignableTo(target.Type()) {
+		target.Set(reflect.ValueOf(deepCopy(variant)))
+		return nil
+	}
+
+	if isNumericKind(value.Kind()) && isNumericKind(target.Kind()) {
+		return convertNumber(target, value)
+	}
+
+	// Fall back to a JSON round trip for composite values
+	encoded, err := json.Marshal(variant)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, target.Addr().Interface())
+}
+
+// structSessionInfoField represents a reflected session info struct field
+type structSessionInfoField struct {
+	name   string
+	index  int
+	typ    reflect.Type
+	copier *valueCopier
+}
+
+// StructSessionInfoType represents a plain Go struct type reflected once to be
+// used as session info. It provides webwire.SessionInfo compliant wrappers for
+// values of the struct type as well as a webwire.SessionInfoParser.
+//
+// Fields are named after their json-style tags (`json:"name"`) or the field
+// name if there's no tag. Fields tagged `json:"-"` and unexported fields are
+// ignored. Tag options (such as omitempty) are ignored as well
+type StructSessionInfoType struct {
+	typ     reflect.Type
+	names   []string
+	fields  []structSessionInfoField
+	byName  map[string]int
+	copier  *valueCopier
+	typName string
+}
+
+// NewStructSessionInfoType reflects the type of the given struct (or pointer to
+// struct) prototype value and returns a session info type for it
+func NewStructSessionInfoType(
+	prototype interface{},
+) (*StructSessionInfoType, error) {
+	if prototype == nil {
+		return nil, errors.New("missing session info prototype")
+	}
+	typ := reflect.TypeOf(prototype)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(
+			"session info prototype is not a struct (%s)",
+			typ,
+		)
+	}
+
+	compiled := make(map[reflect.Type]*valueCopier)
+	infoType := &StructSessionInfoType{
+		typ:     typ,
+		names:   make([]string, 0, typ.NumField()),
+		fields:  make([]structSessionInfoField, 0, typ.NumField()),
+		byName:  make(map[string]int, typ.NumField()),
+		copier:  compileCopier(typ, compiled),
+		typName: typ.String(),
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			// Ignore unexported fields
+			continue
+		}
+
+		name := field.Name
+		if tag, hasTag := field.Tag.Lookup("json"); hasTag {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		if _, duplicate := infoType.byName[name]; duplicate {
+			return nil, fmt.Errorf(
+				"duplicate session info field name %q in %s",
+				name,
+				typ,
+			)
+		}
+
+		infoType.byName[name] = len(infoType.fields)
+		infoType.names = append(infoType.names, name)
+		infoType.fields = append(infoType.fields, structSessionInfoField{
+			name:   name,
+			index:  i,
+			typ:    field.Type,
+			copier: compileCopier(field.Type, compiled),
+		})
+	}
+
+	return infoType, nil
+}
+
+// Wrap deep-copies the given struct (or pointer to struct) value into a new
+// webwire.SessionInfo compliant session info object.
+// Returns an error if the value is not of the reflected struct type
+func (typ *StructSessionInfoType) Wrap(value interface{}) (SessionInfo, error) {
+	original := reflect.ValueOf(value)
+	if !original.IsValid() {
+		return nil, errors.New("missing session info value")
+	}
+	if original.Kind() == reflect.Ptr {
+		if original.IsNil() {
+			return nil, errors.New("session info value is nil")
+		}
+		original = original.Elem()
+	}
+	if original.Type() != typ.typ {
+		return nil, fmt.Errorf(
+			"unexpected session info value type: %s (expected: %s)",
+			original.Type(),
+			typ.typName,
+		)
+	}
+
+	cpy := reflect.New(typ.typ).Elem()
+	(*typ.copier)(original, cpy)
+	return &StructSessionInfo{
+		typ:   typ,
+		value: cpy,
+	}, nil
+}
+
+// Parse parses the given variant map into a new session info object of the
+// reflected struct type. Unknown fields are ignored. Returns an error if
+// a value can't be converted to the type of the corresponding struct field
+func (typ *StructSessionInfoType) Parse(
+	data map[string]interface{},
+) (SessionInfo, error) {
+	value := reflect.New(typ.typ).Elem()
+	for name, variant := range data {
+		index, exists := typ.byName[name]
+		if !exists {
+			continue
+		}
+		field := value.Field(typ.fields[index].index)
+		if err := assignVariant(field, variant); err != nil {
+			return nil, fmt.Errorf(
+				"invalid session info field %q: %s",
+				name,
+				err,
+			)
+		}
+	}
+	return &StructSessionInfo{
+		typ:   typ,
+		value: value,
+	}, nil
+}
+
+// Parser returns a webwire.SessionInfoParser compliant function for the
+// reflected struct type. Since the parser function can't return an error
+// the session info of sessions with invalid info fields is omitted (nil)
+// and the parse error is logged to the given logger, which usually is the
+// logger of the server (see ServerOptions.Logger). Use Parse directly
+// to handle parse errors differently
+func (typ *StructSessionInfoType) Parser(logger Logger) SessionInfoParser {
+	return func(data map[string]interface{}) SessionInfo {
+		info, err := typ.Parse(data)
+		if err != nil {
+			if logger != nil {
+				logger.Log(
+					LogLevelError,
+					"couldn't parse struct session info",
+					logErr(err),
+				)
+			}
+			return nil
+		}
+		return info
+	}
+}
+
+// StructSessionInfo represents a webwire.SessionInfo interface implementation
+// backed by a value of a reflected struct type
+type StructSessionInfo struct {
+	typ   *StructSessionInfoType
+	value reflect.Value
+}
+
+// Fields implements the webwire.SessionInfo interface.
+// It returns a constant list of the names of all fields of the struct
+func (sinf *StructSessionInfo) Fields() []string {
+	names := make([]string, len(sinf.typ.names))
+	copy(names, sinf.typ.names)
+	return names
+}
+
+// Value implements the webwire.SessionInfo interface.
+// It returns an exact deep copy of a session info struct field value
+func (sinf *StructSessionInfo) Value(fieldName string) interface{} {
+	index, exists := sinf.typ.byName[fieldName]
+	if !exists {
+		return nil
+	}
+	field := sinf.typ.fields[index]
+	cpy := reflect.New(field.typ).Elem()
+	(*field.copier)(sinf.value.Field(field.index), cpy)
+	return cpy.Interface()
+}
+
+// Copy implements the webwire.SessionInfo interface.
+// It deep-copies the object and returns it's exact clone
+func (sinf *StructSessionInfo) Copy() SessionInfo {
+	cpy := reflect.New(sinf.typ.typ).Elem()
+	(*sinf.typ.copier)(sinf.value, cpy)
+	return &StructSessionInfo{
+		typ:   sinf.typ,
+		value: cpy,
+	}
+}
+
+// Struct returns an exact deep copy of the underlying struct value
+// which can be type-asserted to the reflected struct type
+func (sinf *StructSessionInfo) Struct() interface{} {
+	cpy := reflect.New(sinf.typ.typ).Elem()
+	(*sinf.typ.copier)(sinf.value, cpy)
+	return cpy.Interface()
+}
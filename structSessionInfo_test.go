@@ -0,0 +1,207 @@
+package webwire
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testStructSessInfoNested struct {
+	Tags []string `json:"tags"`
+}
+
+type testStructSessInfo struct {
+	UserID   string                    `json:"uid"`
+	Age      int                       `json:"age"`
+	Roles    map[string]bool           `json:"roles"`
+	Nested   *testStructSessInfoNested `json:"nested"`
+	Untagged float64
+	Ignored  string `json:"-"`
+	hidden   string
+}
+
+func newTestStructSessInfoType(t *testing.T) *StructSessionInfoType {
+	infoType, err := NewStructSessionInfoType(testStructSessInfo{})
+	require.NoError(t, err)
+	return infoType
+}
+
+// TestStructSessionInfoTypeInvalid tests creating struct session info types
+// from invalid prototypes
+func TestStructSessionInfoTypeInvalid(t *testing.T) {
+	_, err := NewStructSessionInfoType(nil)
+	require.Error(t, err)
+
+	_, err = NewStructSessionInfoType("not a struct")
+	require.Error(t, err)
+
+	_, err = NewStructSessionInfoType(struct {
+		A string `json:"B"`
+		B string
+	}{})
+	require.Error(t, err)
+}
+
+// TestStructSessionInfoFields tests the Fields method
+// of the struct session info implementation
+func TestStructSessionInfoFields(t *testing.T) {
+	info, err := newTestStructSessInfoType(t).Wrap(&testStructSessInfo{})
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"uid", "age", "roles", "nested", "Untagged"},
+		info.Fields(),
+	)
+}
+
+// TestStructSessionInfoCopy tests the Copy and Value methods
+// of the struct session info implementation
+func TestStructSessionInfoCopy(t *testing.T) {
+	original := &testStructSessInfo{
+		UserID: "user1",
+		Age:    42,
+		Roles:  map[string]bool{"admin": true},
+		Nested: &testStructSessInfoNested{Tags: []string{"a", "b"}},
+	}
+	info, err := newTestStructSessInfoType(t).Wrap(original)
+	require.NoError(t, err)
+
+	copied := info.Copy()
+
+	check := func() {
+		require.Equal(t, "user1", copied.Value("uid"))
+		require.Equal(t, 42, copied.Value("age"))
+		require.Equal(t, map[string]bool{"admin": true}, copied.Value("roles"))
+		require.Equal(
+			t,
+			&testStructSessInfoNested{Tags: []string{"a", "b"}},
+			copied.Value("nested"),
+		)
+		require.Nil(t, copied.Value("inexistent"))
+		require.Nil(t, copied.Value("Ignored"))
+	}
+
+	// Verify consistency
+	check()
+
+	// Verify immutability of both the wrapped and the copied values
+	original.Roles["admin"] = false
+	original.Nested.Tags[0] = "changed"
+	info.(*StructSessionInfo).value.Field(0).SetString("changed")
+	info.Value("nested").(*testStructSessInfoNested).Tags[1] = "changed"
+	check()
+
+	// Verify the typed struct accessor
+	typed := copied.(*StructSessionInfo).Struct().(testStructSessInfo)
+	require.Equal(t, "user1", typed.UserID)
+	typed.Nested.Tags[0] = "changed"
+	check()
+}
+
+// TestStructSessionInfoWrapInvalid tests wrapping values of unexpected types
+func TestStructSessionInfoWrapInvalid(t *testing.T) {
+	infoType := newTestStructSessInfoType(t)
+
+	_, err := infoType.Wrap(struct{}{})
+	require.Error(t, err)
+
+	_, err = infoType.Wrap((*testStructSessInfo)(nil))
+	require.Error(t, err)
+
+	_, err = infoType.Wrap(nil)
+	require.Error(t, err)
+}
+
+// TestStructSessionInfoParse tests round-trip conversion of struct session info
+// objects to variant maps and back
+func TestStructSessionInfoParse(t *testing.T) {
+	infoType := newTestStructSessInfoType(t)
+
+	// Simulate the types produced by the JSON decoder
+	parsed, err := infoType.Parse(map[string]interface{}{
+		"uid":      "user1",
+		"age":      float64(42),
+		"roles":    map[string]interface{}{"admin": true},
+		"nested":   map[string]interface{}{"tags": []interface{}{"a"}},
+		"Untagged": float64(1.5),
+		"unknown":  "ignored",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, testStructSessInfo{
+		UserID:   "user1",
+		Age:      42,
+		Roles:    map[string]bool{"admin": true},
+		Nested:   &testStructSessInfoNested{Tags: []string{"a"}},
+		Untagged: 1.5,
+	}, parsed.(*StructSessionInfo).Struct())
+
+	// Convert back to a variant map and parse again
+	reparsed, err := infoType.Parse(SessionInfoToVarMap(parsed))
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		parsed.(*StructSessionInfo).Struct(),
+		reparsed.(*StructSessionInfo).Struct(),
+	)
+
+	// Expect unconvertible values to be rejected
+	for _, data := range []map[string]interface{}{
+		{"age": "not a number"},
+		{"age": float64(3.7)},
+		{"age": float64(1e30)},
+		{"Untagged": "not a number"},
+	} {
+		_, err := infoType.Parse(data)
+		require.Error(t, err)
+	}
+
+	// Expect the parser function to omit invalid session info
+	// logging the parse error
+	errBuf := &bytes.Buffer{}
+	parser := infoType.Parser(NewStdLogger(nil, log.New(errBuf, "", 0)))
+	require.Nil(t, parser(map[string]interface{}{"age": "x"}))
+	require.Contains(t, errBuf.String(), "couldn't parse struct session info")
+	require.Contains(t, errBuf.String(), `invalid session info field "age"`)
+
+	errBuf.Reset()
+	require.NotNil(t, parser(map[string]interface{}{"age": 1}))
+	require.Equal(t, 0, errBuf.Len())
+}
+
+// TestStructSessionInfoParseNumbers tests the conversion of numeric variants
+// to numeric struct fields
+func TestStructSessionInfoParseNumbers(t *testing.T) {
+	type numbers struct {
+		Small    uint8
+		Unsigned uint64
+		Float    float32
+	}
+	infoType, err := NewStructSessionInfoType(numbers{})
+	require.NoError(t, err)
+
+	parsed, err := infoType.Parse(map[string]interface{}{
+		"Small":    float64(255),
+		"Unsigned": int64(42),
+		"Float":    float64(1.5),
+	})
+	require.NoError(t, err)
+	require.Equal(t, numbers{
+		Small:    255,
+		Unsigned: 42,
+		Float:    1.5,
+	}, parsed.(*StructSessionInfo).Struct())
+
+	for _, data := range []map[string]interface{}{
+		{"Small": float64(300)},
+		{"Small": int(300)},
+		{"Small": float64(-1)},
+		{"Unsigned": int64(-1)},
+		{"Float": float64(1e300)},
+	} {
+		_, err := infoType.Parse(data)
+		require.Error(t, err, "%v", data)
+	}
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/admin"
+	"github.com/stretchr/testify/require"
+)
+
+// adminCall performs an HTTP request against the given admin handler
+// decoding the JSON response into the given view
+func adminCall(
+	t *testing.T,
+	handler http.Handler,
+	method string,
+	path string,
+	view interface{},
+) int {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	handler.ServeHTTP(recorder, req)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	if view != nil {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), view))
+	}
+	return recorder.Code
+}
+
+// TestAdminHandler tests the admin HTTP handler
+func TestAdminHandler(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					if key != "session1" {
+						// Session not found
+						return nil, nil
+					}
+					return wwr.NewSessionLookupResult(
+						time.Now(), // Creation
+						time.Now(), // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+	handler := admin.New(setup.Server)
+
+	// Restore the same session on two connections
+	clients := make([]wwr.Socket, 2)
+	for i := range clients {
+		clients[i], _ = setup.NewClientSocket()
+		requestRestoreSessionSuccess(t, clients[i], []byte("session1"))
+	}
+
+	// Read the overview
+	var overview admin.Overview
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/", &overview,
+	))
+	require.Equal(t, 2, overview.Connections)
+	require.Equal(t, 1, overview.ActiveSessions)
+	require.Equal(t, uint32(8192), overview.MessagePool.BufferSize)
+	require.True(t, overview.MessagePool.Acquired > 0)
+
+	// Read the options
+	var options admin.Options
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/options", &options,
+	))
+	require.True(t, options.Sessions)
+	require.Equal(t, "reject", options.SessionEvictionPolicy)
+	require.Equal(t, "1m0s", options.ReadTimeout)
+
+	// List the connections
+	var connections []admin.Connection
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/connections", &connections,
+	))
+	require.Len(t, connections, 2)
+	keyHash := wwr.HashSessionKey("session1")
+	for _, conn := range connections {
+		require.Equal(t, keyHash, conn.SessionKeyHash)
+		require.Equal(t, "", conn.Session)
+		require.Equal(t, uint64(1), conn.Stats.MessagesReceived)
+	}
+
+	// List the sessions
+	var sessions []admin.Session
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/sessions", &sessions,
+	))
+	require.Len(t, sessions, 1)
+	require.Equal(t, keyHash, sessions[0].KeyHash)
+	require.Equal(t, "", sessions[0].Key)
+	require.Equal(
+		t,
+		[]uint64{connections[0].ID, connections[1].ID},
+		sessions[0].Connections,
+	)
+
+	// Expect raw session keys not to address sessions
+	require.Equal(t, http.StatusNotFound, adminCall(
+		t, handler, http.MethodPost, "/sessions/session1/close", nil,
+	))
+
+	// Expect actions without the JSON content type to be rejected
+	sessionClosePath := fmt.Sprintf("/sessions/%s/close", keyHash)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodPost,
+		sessionClosePath,
+		nil,
+	))
+	require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+
+	// Close the session
+	notified := expectSessionClosedAsync(t, clients...)
+	var result admin.ActionResult
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodPost, sessionClosePath, &result,
+	))
+	require.Len(t, result.AffectedConnections, 2)
+	require.Len(t, result.Errors, 0)
+	notified.Wait()
+
+	require.Equal(t, http.StatusNotFound, adminCall(
+		t, handler, http.MethodPost, sessionClosePath, nil,
+	))
+
+	// Close a connection
+	closePath := fmt.Sprintf("/connections/%d/close", connections[0].ID)
+	require.Equal(t, http.StatusMethodNotAllowed, adminCall(
+		t, handler, http.MethodGet, closePath, nil,
+	))
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodPost, closePath, nil,
+	))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(setup.Server.Connections()) > 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	require.Len(t, setup.Server.Connections(), 1)
+
+	// Close inexistent and invalid connections
+	var errView admin.Error
+	require.Equal(t, http.StatusNotFound, adminCall(
+		t, handler, http.MethodPost, closePath, &errView,
+	))
+	require.Equal(
+		t,
+		wwr.ErrConnectionNotFound{ConnectionID: connections[0].ID}.Error(),
+		errView.Error,
+	)
+	require.Equal(t, http.StatusBadRequest, adminCall(
+		t, handler, http.MethodPost, "/connections/abc/close", nil,
+	))
+	require.Equal(t, http.StatusNotFound, adminCall(
+		t, handler, http.MethodGet, "/inexistent", nil,
+	))
+}
+
+// TestAdminHandlerExposeSessionKeys tests exposing raw session keys
+func TestAdminHandlerExposeSessionKeys(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					return wwr.NewSessionLookupResult(
+						time.Now(), // Creation
+						time.Now(), // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+	handler := admin.New(setup.Server)
+	handler.ExposeSessionKeys = true
+
+	client, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, client, []byte("session1"))
+
+	var connections []admin.Connection
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/connections", &connections,
+	))
+	require.Len(t, connections, 1)
+	require.Equal(t, "session1", connections[0].Session)
+
+	var sessions []admin.Session
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodGet, "/sessions", &sessions,
+	))
+	require.Len(t, sessions, 1)
+	require.Equal(t, "session1", sessions[0].Key)
+	require.Equal(t, wwr.HashSessionKey("session1"), sessions[0].KeyHash)
+
+	// Close the session by its raw key
+	notified := expectSessionClosedAsync(t, client)
+	require.Equal(t, http.StatusOK, adminCall(
+		t, handler, http.MethodPost, "/sessions/session1/close", nil,
+	))
+	notified.Wait()
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectSessionClosedAsync concurrently awaits a session closure notification
+// on each of the given sockets
+func expectSessionClosedAsync(
+	t *testing.T,
+	socks ...wwr.Socket,
+) *sync.WaitGroup {
+	notified := &sync.WaitGroup{}
+	notified.Add(len(socks))
+	for _, sock := range socks {
+		go func(sock wwr.Socket) {
+			defer notified.Done()
+			msg := message.NewMessage(32)
+			assert.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+			assert.Equal(t, message.MsgNotifySessionClosed, msg.MsgType)
+		}(sock)
+	}
+	return notified
+}
+
+// TestAdminSessionOperations tests listing active sessions, closing sessions
+// by predicate and closing the session of a single connection
+func TestAdminSessionOperations(t *testing.T) {
+	sessionCreation := time.Now().Add(-time.Hour)
+	sessionOwners := map[string]string{
+		"session1": "alice",
+		"session2": "bob",
+		"session3": "alice",
+	}
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					owner, exists := sessionOwners[key]
+					if !exists {
+						// Session not found
+						return nil, nil
+					}
+					info := map[string]interface{}{"uid": owner}
+					return wwr.NewSessionLookupResult(
+						sessionCreation, // Creation
+						time.Now(),      // LastLookup
+						info,            // Info
+					), nil
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	// Restore the sessions, session1 on two connections
+	clients := make([]wwr.Socket, 4)
+	for i, key := range []string{
+		"session1",
+		"session2",
+		"session3",
+		"session1",
+	} {
+		clients[i], _ = setup.NewClientSocket()
+		requestRestoreSessionSuccess(t, clients[i], []byte(key))
+	}
+
+	// List the active sessions
+	sessions := setup.Server.ActiveSessions()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Key < sessions[j].Key
+	})
+	require.Len(t, sessions, 3)
+	require.Equal(t, "session1", sessions[0].Key)
+	require.Equal(t, 2, sessions[0].Connections)
+	require.True(t, sessionCreation.Equal(sessions[0].Creation))
+	require.Equal(t, "session2", sessions[1].Key)
+	require.Equal(t, 1, sessions[1].Connections)
+	require.Equal(t, "session3", sessions[2].Key)
+	require.Equal(t, 1, sessions[2].Connections)
+
+	// Close all sessions of alice
+	notified := expectSessionClosedAsync(t, clients[0], clients[2], clients[3])
+	affected, closeErrs, err := setup.Server.CloseSessions(
+		func(session *wwr.Session) bool {
+			return session.Info.Value("uid") == "alice"
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, affected, 3)
+	require.Len(t, closeErrs, 3)
+	notified.Wait()
+
+	sessions = setup.Server.ActiveSessions()
+	require.Len(t, sessions, 1)
+	require.Equal(t, "session2", sessions[0].Key)
+
+	// Close the session of a single connection
+	connections := setup.Server.SessionConnections("session2")
+	require.Len(t, connections, 1)
+
+	notified = expectSessionClosedAsync(t, clients[1])
+	require.NoError(t, setup.Server.CloseConnectionSession(
+		connections[0].ID(),
+	))
+	notified.Wait()
+	require.Len(t, setup.Server.ActiveSessions(), 0)
+
+	// Close the session of an inexistent connection
+	require.Equal(
+		t,
+		wwr.ErrConnectionNotFound{ConnectionID: 0},
+		setup.Server.CloseConnectionSession(0),
+	)
+}
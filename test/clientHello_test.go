@@ -0,0 +1,145 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialHello connects a new client socket sending the given client hello
+// and returns the first message received from the server
+func dialHello(
+	t *testing.T,
+	setup ServerSetupTest,
+	hello message.ClientHello,
+) (wwr.Socket, *message.Message) {
+	sock, err := setup.NewDisconnectedClientSocket()
+	require.NoError(t, err)
+	require.NoError(t, sock.Dial(time.Time{}))
+
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgClientHello(writer, hello))
+
+	msg := message.NewMessage(64)
+	require.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+	return sock, msg
+}
+
+// TestClientHello tests negotiating the protocol version and capabilities
+func TestClientHello(t *testing.T) {
+	handshakes := make(chan wwr.Handshake, 1)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			ClientHello: wwr.Enabled,
+			OnHandshake: func(handshake wwr.Handshake) wwr.HandshakeResult {
+				handshakes <- handshake
+				return wwr.HandshakeResult{}
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	// Expect the highest mutually supported version to be chosen
+	_, conf := dialHello(t, setup, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MinMinorProtocolVersion: 0,
+		MaxMinorProtocolVersion: 5,
+		Capabilities:            message.CapabilityFragmentation,
+		ClientName:              []byte("test-client/1.0"),
+		AuthBlob:                []byte("token"),
+	})
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+	require.Equal(t, byte(2), conf.ServerConfiguration.MajorProtocolVersion)
+	require.Equal(t, byte(2), conf.ServerConfiguration.MinorProtocolVersion)
+	require.Equal(
+		t,
+		message.Capabilities(0),
+		conf.ServerConfiguration.Capabilities,
+	)
+
+	handshake := <-handshakes
+	require.NotNil(t, handshake.Hello)
+	require.Equal(t, []byte("test-client/1.0"), handshake.Hello.ClientName)
+	require.Equal(t, []byte("token"), handshake.Hello.AuthBlob)
+	require.Equal(
+		t,
+		message.CapabilityFragmentation,
+		handshake.Hello.Capabilities,
+	)
+
+	// Expect older clients to be served
+	_, conf = dialHello(t, setup, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MinMinorProtocolVersion: 0,
+		MaxMinorProtocolVersion: 1,
+	})
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+	require.Equal(t, byte(1), conf.ServerConfiguration.MinorProtocolVersion)
+	<-handshakes
+}
+
+// TestClientHelloUnsupportedVersion tests rejecting clients
+// not supporting any of the protocol versions supported by the server
+func TestClientHelloUnsupportedVersion(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			ClientHello:  wwr.Enabled,
+			SessionCodec: wwr.NewBinarySessionCodec(),
+		},
+		nil, // Use the default transport implementation
+	)
+
+	for _, hello := range []message.ClientHello{
+		{MajorProtocolVersion: 3},
+		// The binary session codec requires protocol version 2.1
+		{MajorProtocolVersion: 2},
+	} {
+		sock, msg := dialHello(t, setup, hello)
+		require.Equal(t, message.MsgRejectConf, msg.MsgType)
+		require.Equal(
+			t,
+			[]byte("unsupported protocol version"),
+			msg.MsgPayload.Data,
+		)
+		assert.NotNil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+	}
+}
+
+// TestClientHelloExpected tests rejecting clients
+// not sending a client hello message
+func TestClientHelloExpected(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			ClientHello: wwr.Enabled,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, err := setup.NewDisconnectedClientSocket()
+	require.NoError(t, err)
+	require.NoError(t, sock.Dial(time.Time{}))
+
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgHeartbeat(writer))
+
+	msg := message.NewMessage(64)
+	require.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+	require.Equal(t, message.MsgRejectConf, msg.MsgType)
+	require.Equal(t, []byte("client hello expected"), msg.MsgPayload.Data)
+}
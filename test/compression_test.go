@@ -0,0 +1,211 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// compressedRequest writes a compressed request and returns the reply
+func compressedRequest(
+	t *testing.T,
+	sock wwr.Socket,
+	identifier byte,
+	data []byte,
+) *message.Message {
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		message.NewCompressingWriter(writer, 0),
+		[]byte{identifier, 0, 0, 0, 0, 0, 0, 0},
+		[]byte("echo"),
+		payload.Binary,
+		data,
+		true,
+	))
+
+	reply := message.NewMessage(1024)
+	require.Nil(t, sock.Read(reply, time.Now().Add(5*time.Second)))
+	return reply
+}
+
+// TestCompression tests compressing messages
+// of connections that negotiated compression
+func TestCompression(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.Payload{
+					Encoding: payload.Binary,
+					Data:     msg.Payload(),
+				}, nil
+			},
+		},
+		wwr.ServerOptions{
+			ClientHello:          wwr.Enabled,
+			Compression:          wwr.Enabled,
+			CompressionThreshold: 128,
+			MessageBufferSize:    1024,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, conf := dialHello(t, setup, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MaxMinorProtocolVersion: 2,
+		Capabilities:            message.CapabilityCompression,
+	})
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+	require.True(t, conf.ServerConfiguration.Capabilities.Has(
+		message.CapabilityCompression,
+	))
+
+	// Expect large replies to be compressed
+	large := bytes.Repeat([]byte("compressible "), 60)
+	reply := compressedRequest(t, sock, 1, large)
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+	require.True(t, reply.Compressed)
+	require.Equal(t, large, reply.MsgPayload.Data)
+
+	// Expect small replies not to be compressed
+	reply = compressedRequest(t, sock, 2, []byte("small"))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+	require.False(t, reply.Compressed)
+	require.Equal(t, []byte("small"), reply.MsgPayload.Data)
+}
+
+// TestCompressionBufferOverflow tests checking the uncompressed length
+// of compressed messages against the message buffer size because the
+// receiver inflates them into a buffer of the same size
+func TestCompressionBufferOverflow(t *testing.T) {
+	fitting := bytes.Repeat([]byte("compressible "), 60)
+	oversized := bytes.Repeat([]byte("compressible "), 300)
+
+	signalErrs := make(chan error, 2)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				data := fitting
+				if string(msg.Name()) == "oversized" {
+					data = oversized
+				}
+				signalErrs <- conn.Signal(
+					[]byte("s"),
+					wwr.Payload{Encoding: payload.Binary, Data: data},
+				)
+				return wwr.Payload{}, nil
+			},
+		},
+		wwr.ServerOptions{
+			ClientHello:       wwr.Enabled,
+			Compression:       wwr.Enabled,
+			MessageBufferSize: 1024,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, conf := dialHello(t, setup, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MaxMinorProtocolVersion: 2,
+		Capabilities:            message.CapabilityCompression,
+	})
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+
+	// Expect the fitting signal to be inflated into the receiver's buffer
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte("fitting"),
+		payload.Binary,
+		nil,
+		true,
+	))
+	signal := message.NewMessage(1024)
+	require.Nil(t, sock.Read(signal, time.Now().Add(5*time.Second)))
+	require.Equal(t, message.MsgSignalBinary, signal.MsgType)
+	require.True(t, signal.Compressed)
+	require.Equal(t, fitting, signal.MsgPayload.Data)
+	require.NoError(t, <-signalErrs)
+
+	reply := message.NewMessage(64)
+	require.Nil(t, sock.Read(reply, time.Now().Add(5*time.Second)))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+
+	// Expect the oversized signal to overflow the buffer even though it would
+	// fit once compressed without affecting the connection
+	requestSuccess(t, sock, 64, []byte("oversized"), payload.Payload{})
+	require.IsType(t, wwr.ErrBufferOverflow{}, <-signalErrs)
+}
+
+// TestCompressionNotNegotiated tests dropping compressed messages
+// of connections that didn't negotiate compression
+func TestCompressionNotNegotiated(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			ClientHello: wwr.Enabled,
+			Compression: wwr.Enabled,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, conf := dialHello(t, setup, message.ClientHello{
+		MajorProtocolVersion:    2,
+		MaxMinorProtocolVersion: 2,
+	})
+	require.Equal(t, message.MsgAcceptConf, conf.MsgType)
+	require.False(t, conf.ServerConfiguration.Capabilities.Has(
+		message.CapabilityCompression,
+	))
+
+	// Send a compressed request which is expected to be dropped
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		message.NewCompressingWriter(writer, 0),
+		[]byte{1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte("compressed"),
+		payload.Binary,
+		bytes.Repeat([]byte("compressible "), 100),
+		true,
+	))
+
+	// Expect only the uncompressed request to be replied to
+	reply := requestSuccess(t, sock, 64, []byte("plain"), payload.Payload{})
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0}, reply.MsgIdentifierBytes)
+	require.False(t, reply.Compressed)
+}
+
+// TestCompressionRequiresClientHello tests enabling compression
+// without enabling the client hello
+func TestCompressionRequiresClientHello(t *testing.T) {
+	_, err := SetupServer(
+		&ServerImpl{},
+		wwr.ServerOptions{Compression: wwr.Enabled},
+		nil, // Use the default transport implementation
+	)
+	require.Error(t, err)
+}
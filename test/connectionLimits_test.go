@@ -0,0 +1,58 @@
+package test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaxConnections tests refusing connections exceeding
+// the maximum number of concurrent connections
+func TestMaxConnections(t *testing.T) {
+	refused := make(chan wwr.ConnectionRefusalReason, 1)
+	disconnected := make(chan struct{}, 1)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientDisconnected: func(_ wwr.Connection, _ error) {
+				disconnected <- struct{}{}
+			},
+		},
+		wwr.ServerOptions{
+			MaxConnections: 2,
+			OnConnectionRefused: func(
+				remoteAddr net.Addr,
+				reason wwr.ConnectionRefusalReason,
+			) {
+				require.NotNil(t, remoteAddr)
+				refused <- reason
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sockA, _ := setup.NewClientSocket()
+	setup.NewClientSocket()
+
+	// Expect the third connection to be refused
+	// before the configuration message is written
+	_, _, err := setup.ServerSetup.NewClientSocket()
+	require.Error(t, err)
+
+	select {
+	case reason := <-refused:
+		require.Equal(t, wwr.RefusedMaxConnections, reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnConnectionRefused wasn't called")
+	}
+
+	// Expect a new connection to be accepted after another one disconnected
+	require.NoError(t, sockA.Close())
+	<-disconnected
+	setup.NewClientSocket()
+}
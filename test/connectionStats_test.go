@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnectionStats tests the statistics of a connection
+func TestConnectionStats(t *testing.T) {
+	connected := make(chan wwr.Connection, 1)
+	signalHandled := make(chan struct{})
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientConnected: func(_ wwr.ConnectionOptions, c wwr.Connection) {
+				connected <- c
+			},
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				if string(msg.Name()) == "fail" {
+					return wwr.Payload{}, wwr.ErrRequest{Code: "ERR"}
+				}
+				time.Sleep(10 * time.Millisecond)
+				return wwr.Payload{}, nil
+			},
+			Signal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				close(signalHandled)
+			},
+		},
+		wwr.ServerOptions{},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+	conn := <-connected
+
+	stats := conn.Stats()
+	require.Equal(t, uint64(0), stats.MessagesReceived)
+	require.Equal(t, int32(0), stats.InFlightHandlers)
+
+	requestSuccess(t, sock, 32, []byte("succeed"), payload.Payload{})
+	reply := request(t, sock, 32, []byte("fail"), payload.Payload{})
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+
+	signal(t, sock, []byte("sample"), payload.Payload{Data: []byte("data")})
+	<-signalHandled
+
+	// Send a signal to the client
+	signalRead := make(chan struct{})
+	go func() {
+		defer close(signalRead)
+		msg := message.NewMessage(32)
+		assert.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+		assert.Equal(t, message.MsgSignalBinary, msg.MsgType)
+	}()
+	require.NoError(t, conn.Signal(nil, wwr.Payload{Data: []byte("out")}))
+	<-signalRead
+
+	// Wait for all handlers to return
+	deadline := time.Now().Add(5 * time.Second)
+	for conn.Stats().InFlightHandlers > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	stats = conn.Stats()
+	require.Equal(t, uint64(3), stats.MessagesReceived)
+	require.True(t, stats.BytesReceived > 0)
+	require.Equal(t, uint64(3), stats.MessagesSent)
+	require.True(t, stats.BytesSent > 0)
+	require.Equal(t, uint64(1), stats.RequestsServed)
+	require.Equal(t, uint64(1), stats.RequestsFailed)
+	require.Equal(t, uint64(1), stats.SignalsReceived)
+	require.Equal(t, uint64(1), stats.SignalsSent)
+	require.Equal(t, int32(0), stats.InFlightHandlers)
+	require.True(t, stats.RequestLatency > 0)
+	require.False(t, stats.LastActivity.IsZero())
+}
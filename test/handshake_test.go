@@ -1,11 +1,18 @@
 package test
 
 import (
+	"crypto/tls"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	wwr "github.com/qbeon/webwire-go"
 	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,3 +54,168 @@ func TestHandshake(t *testing.T) {
 		MessageBufferSize:    messageBufferSize,
 	}, msg.ServerConfiguration)
 }
+
+// setupHandshakeServer sets up a server authenticating connections by the
+// authorization header provided by the transport layer. The memchan transport
+// simulates the credentials of HTTP based and TLS protected transports
+// through the connection options returned by OnBeforeCreation
+func setupHandshakeServer(
+	t *testing.T,
+	onHandshake wwr.OnHandshake,
+	token string,
+	clientConnected chan wwr.Connection,
+) ServerSetupTest {
+	return SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientConnected: func(_ wwr.ConnectionOptions, c wwr.Connection) {
+				clientConnected <- c
+			},
+		},
+		wwr.ServerOptions{
+			OnHandshake: onHandshake,
+		},
+		&memchan.Transport{
+			OnBeforeCreation: func() wwr.ConnectionOptions {
+				return wwr.ConnectionOptions{
+					Header: http.Header{
+						"Authorization": []string{"Bearer " + token},
+					},
+					TLS: &tls.ConnectionState{ServerName: "example.com"},
+				}
+			},
+		},
+	)
+}
+
+// authenticate returns an OnHandshake hook authenticating connections
+// by the bearer token in the authorization header
+func authenticate(t *testing.T) wwr.OnHandshake {
+	return func(handshake wwr.Handshake) wwr.HandshakeResult {
+		assert.NotNil(t, handshake.RemoteAddr)
+		opts := handshake.ConnectionOptions
+		if assert.NotNil(t, opts.TLS) {
+			assert.Equal(t, "example.com", opts.TLS.ServerName)
+		}
+		if opts.Header.Get("Authorization") != "Bearer secret" {
+			return wwr.HandshakeResult{
+				Reject: true,
+				Reason: "unauthorized",
+			}
+		}
+		return wwr.HandshakeResult{
+			SessionInfo: &testAuthenticationSessInfo{
+				User: "alice",
+			},
+		}
+	}
+}
+
+// testAuthenticationSessInfo represents a session info object
+// attached during the handshake
+type testAuthenticationSessInfo struct {
+	User string
+}
+
+// Copy implements the webwire.SessionInfo interface
+func (sinf *testAuthenticationSessInfo) Copy() wwr.SessionInfo {
+	return &testAuthenticationSessInfo{User: sinf.User}
+}
+
+// Fields implements the webwire.SessionInfo interface
+func (sinf *testAuthenticationSessInfo) Fields() []string {
+	return []string{"user"}
+}
+
+// Value implements the webwire.SessionInfo interface
+func (sinf *testAuthenticationSessInfo) Value(fieldName string) interface{} {
+	if fieldName == "user" {
+		return sinf.User
+	}
+	return nil
+}
+
+// TestHandshakeReject tests rejecting connections during the handshake
+func TestHandshakeReject(t *testing.T) {
+	clientConnected := make(chan wwr.Connection, 1)
+	setup := setupHandshakeServer(
+		t,
+		authenticate(t),
+		"invalid",
+		clientConnected,
+	)
+
+	sock, err := setup.NewDisconnectedClientSocket()
+	require.NoError(t, err)
+	require.NoError(t, sock.Dial(time.Time{}))
+
+	// Expect the rejection message instead of the server configuration
+	msg := message.NewMessage(32)
+	require.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+	require.Equal(t, message.MsgRejectConf, msg.MsgType)
+	require.Equal(t, payload.Utf8, msg.MsgPayload.Encoding)
+	require.Equal(t, []byte("unauthorized"), msg.MsgPayload.Data)
+
+	// Expect the connection to be closed
+	require.NotNil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+
+	select {
+	case <-clientConnected:
+		t.Fatal("OnClientConnected was called for a rejected connection")
+	default:
+	}
+}
+
+// TestHandshakeRejectReasonTruncation tests truncating rejection reasons
+// exceeding the maximum length without splitting multi-byte characters
+func TestHandshakeRejectReasonTruncation(t *testing.T) {
+	clientConnected := make(chan wwr.Connection, 1)
+	setup := setupHandshakeServer(
+		t,
+		func(handshake wwr.Handshake) wwr.HandshakeResult {
+			return wwr.HandshakeResult{
+				Reject: true,
+				// 2 byte characters exceeding the maximum length
+				Reason: strings.Repeat("ä", message.MaxLenRejectReason),
+			}
+		},
+		"secret",
+		clientConnected,
+	)
+
+	sock, err := setup.NewDisconnectedClientSocket()
+	require.NoError(t, err)
+	require.NoError(t, sock.Dial(time.Time{}))
+
+	msg := message.NewMessage(1024)
+	require.Nil(t, sock.Read(msg, time.Now().Add(5*time.Second)))
+	require.Equal(t, message.MsgRejectConf, msg.MsgType)
+	require.True(t, utf8.Valid(msg.MsgPayload.Data))
+	require.Equal(
+		t,
+		strings.Repeat("ä", message.MaxLenRejectReason/2),
+		string(msg.MsgPayload.Data),
+	)
+}
+
+// TestHandshakeSession tests creating sessions during the handshake
+func TestHandshakeSession(t *testing.T) {
+	clientConnected := make(chan wwr.Connection, 1)
+	setup := setupHandshakeServer(
+		t,
+		authenticate(t),
+		"secret",
+		clientConnected,
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Expect the session to be created before the client is connected
+	msg := readSessionCreated(t, sock)
+	require.NotEmpty(t, msg.MsgPayload.Data)
+
+	conn := <-clientConnected
+	require.True(t, conn.HasSession())
+	require.Equal(t, "alice", conn.SessionInfo("user"))
+	require.Equal(t, 1, setup.Server.SessionConnectionsNum(conn.SessionKey()))
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHeaders tests message headers and the propagation
+// of the trace context in the traceparent header
+func TestHeaders(t *testing.T) {
+	rec := tracing.NewRecorder()
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Equal(t, []byte("key-1"), msg.Header("idempotency-key"))
+				assert.Nil(t, msg.Header("inexistent"))
+				return wwr.Payload{Data: msg.Payload()}, nil
+			},
+		},
+		wwr.ServerOptions{
+			Tracer: rec,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgHeaders(writer, []message.Header{
+		{
+			Key:   []byte(wwr.TraceParentHeader),
+			Value: []byte("00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01"),
+		},
+		{Key: []byte("idempotency-key"), Value: []byte("key-1")},
+	}))
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{0, 0, 0, 0, 0, 0, 0, 1},
+		[]byte("r"),
+		payload.Binary,
+		[]byte("payload"),
+		true,
+	))
+
+	reply := message.NewMessage(64)
+	require.Nil(t, sock.Read(reply, time.Time{}))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+	require.Equal(t, []byte("payload"), reply.Payload())
+
+	spans := awaitSpans(t, rec, 1)
+	require.Equal(t,
+		"00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01",
+		spans[0].TraceParent,
+	)
+}
+
+// TestReplyHeaders tests attaching headers to replies and signals
+func TestReplyHeaders(t *testing.T) {
+	headers := []wwr.Header{
+		{Key: []byte("content-type"), Value: []byte("application/json")},
+		{Key: []byte("empty"), Value: nil},
+	}
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				assert.NoError(t, conn.Signal([]byte("s"), wwr.Payload{
+					Data:    []byte("signal"),
+					Headers: headers,
+				}))
+				return wwr.Payload{
+					Data:    msg.Payload(),
+					Headers: headers,
+				}, nil
+			},
+		},
+		wwr.ServerOptions{},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		[]byte{0, 0, 0, 0, 0, 0, 0, 1},
+		[]byte("r"),
+		payload.Binary,
+		[]byte("payload"),
+		true,
+	))
+
+	msg := message.NewMessage(128)
+	for _, expected := range []struct {
+		msgType byte
+		payload string
+	}{
+		{message.MsgSignalBinary, "signal"},
+		{message.MsgReplyBinary, "payload"},
+	} {
+		require.Nil(t, sock.Read(msg, time.Time{}))
+		require.Equal(t, expected.msgType, msg.MsgType)
+		require.Equal(t, []byte(expected.payload), msg.Payload())
+		require.Len(t, msg.Headers, 2)
+		require.Equal(t, []byte("application/json"), msg.Header("content-type"))
+		require.Len(t, msg.Header("empty"), 0)
+		require.Nil(t, msg.Header("inexistent"))
+	}
+}
+
+// TestReplyHeadersInvalid tests replying with invalid headers
+// and signaling with invalid headers
+func TestReplyHeadersInvalid(t *testing.T) {
+	invalid := []wwr.Header{{Key: []byte("invalid\n"), Value: nil}}
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				assert.Error(t, conn.Signal([]byte("s"), wwr.Payload{
+					Data:    []byte("signal"),
+					Headers: invalid,
+				}))
+				return wwr.Payload{Headers: invalid}, nil
+			},
+		},
+		wwr.ServerOptions{},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Expect an internal error reply
+	reply := request(t, sock, 128, []byte("r"), payload.Payload{})
+	require.Equal(t, message.MsgReplyInternalError, reply.MsgType)
+}
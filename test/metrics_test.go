@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testMetrics records the metrics reported by the server
+type testMetrics struct {
+	wwr.NoopMetrics
+	lock            sync.Mutex
+	opened          int
+	received        int
+	requests        int
+	failedRequests  int
+	errorReplies    map[string]int
+	bufferOverflows int
+}
+
+func (m *testMetrics) ConnectionOpened() {
+	m.lock.Lock()
+	m.opened++
+	m.lock.Unlock()
+}
+
+func (m *testMetrics) MessageReceived() {
+	m.lock.Lock()
+	m.received++
+	m.lock.Unlock()
+}
+
+func (m *testMetrics) RequestHandled(_ time.Duration, failed bool) {
+	m.lock.Lock()
+	m.requests++
+	if failed {
+		m.failedRequests++
+	}
+	m.lock.Unlock()
+}
+
+func (m *testMetrics) ErrorReplySent(reason string) {
+	m.lock.Lock()
+	m.errorReplies[reason]++
+	m.lock.Unlock()
+}
+
+func (m *testMetrics) BufferOverflow() {
+	m.lock.Lock()
+	m.bufferOverflows++
+	m.lock.Unlock()
+}
+
+// TestMetrics tests whether the server reports metrics
+func TestMetrics(t *testing.T) {
+	metrics := &testMetrics{errorReplies: make(map[string]int)}
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				switch string(msg.Name()) {
+				case "fail":
+					return wwr.Payload{}, wwr.ErrRequest{
+						Code:    "ERR",
+						Message: "failure",
+					}
+				case "overflow":
+					return wwr.Payload{}, conn.Signal(
+						[]byte("overflow"),
+						wwr.Payload{Data: make([]byte, 1024)},
+					)
+				}
+				return wwr.Payload{}, nil
+			},
+		},
+		wwr.ServerOptions{
+			Metrics:           metrics,
+			MessageBufferSize: 512,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	requestSuccess(t, sock, 32, []byte("succeed"), payload.Payload{})
+
+	reply := request(t, sock, 32, []byte("fail"), payload.Payload{})
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+
+	// Expect signals exceeding the message buffer to fail
+	reply = request(t, sock, 32, []byte("overflow"), payload.Payload{})
+	require.Equal(t, message.MsgReplyInternalError, reply.MsgType)
+
+	// Error replies are reported after they're sent,
+	// wait for the last one to be reported
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		metrics.lock.Lock()
+		if metrics.errorReplies[wwr.ErrorReplyInternal] > 0 ||
+			time.Now().After(deadline) {
+			break
+		}
+		metrics.lock.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	defer metrics.lock.Unlock()
+	require.Equal(t, 1, metrics.opened)
+	require.Equal(t, 3, metrics.received)
+	require.Equal(t, 3, metrics.requests)
+	require.Equal(t, 2, metrics.failedRequests)
+	require.Equal(t, 1, metrics.errorReplies[wwr.ErrorReplyRequest])
+	require.Equal(t, 1, metrics.errorReplies[wwr.ErrorReplyInternal])
+	require.Equal(t, 1, metrics.bufferOverflows)
+}
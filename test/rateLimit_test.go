@@ -0,0 +1,213 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// requireRateLimited performs a request and expects it to be rejected
+// due to exceeding a rate limit
+func requireRateLimited(t *testing.T, sock wwr.Socket, name []byte) {
+	reply := request(t, sock, 64, name, payload.Payload{})
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+	require.Equal(t, wwr.RateLimitExceededCode, string(reply.MsgName))
+}
+
+// TestRateLimitConnection tests per-connection rate limits
+func TestRateLimitConnection(t *testing.T) {
+	signalsHandled := uint32(0)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Signal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				atomic.AddUint32(&signalsHandled, 1)
+			},
+		},
+		wwr.ServerOptions{
+			RateLimits: wwr.RateLimits{
+				Connection: wwr.RateLimit{Rate: 0.001, Burst: 2},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sockA, _ := setup.NewClientSocket()
+	sockB, _ := setup.NewClientSocket()
+
+	requestSuccess(t, sockA, 32, []byte("r"), payload.Payload{})
+	requestSuccess(t, sockA, 32, []byte("r"), payload.Payload{})
+	requireRateLimited(t, sockA, []byte("r"))
+
+	// Expect the signal to be dropped, signals are handled synchronously
+	// so the handler would've been called before the request is handled
+	signal(t, sockA, []byte("s"), payload.Payload{})
+	requireRateLimited(t, sockA, []byte("r"))
+	require.Equal(t, uint32(0), atomic.LoadUint32(&signalsHandled))
+
+	// Expect other connections to have separate budgets
+	requestSuccess(t, sockB, 32, []byte("r"), payload.Payload{})
+}
+
+// TestRateLimitMessageName tests per-message-name rate limits
+func TestRateLimitMessageName(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			RateLimits: wwr.RateLimits{
+				Messages: map[string]wwr.RateLimit{
+					"limited": {Rate: 0.001, Burst: 1},
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	requestSuccess(t, sock, 32, []byte("limited"), payload.Payload{})
+	requireRateLimited(t, sock, []byte("limited"))
+	for i := 0; i < 5; i++ {
+		requestSuccess(t, sock, 32, []byte("unlimited"), payload.Payload{})
+	}
+}
+
+// TestRateLimitSession tests per-session rate limits shared by all
+// connections of a session
+func TestRateLimitSession(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					return wwr.NewSessionLookupResult(
+						time.Now(), // Creation
+						time.Now(), // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+			RateLimits: wwr.RateLimits{
+				Session: wwr.RateLimit{Rate: 0.001, Burst: 2},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sockA, _ := setup.NewClientSocket()
+	sockB, _ := setup.NewClientSocket()
+	sockC, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, sockA, []byte("session1"))
+	requestRestoreSessionSuccess(t, sockB, []byte("session1"))
+
+	requestSuccess(t, sockA, 32, []byte("r"), payload.Payload{})
+	requestSuccess(t, sockB, 32, []byte("r"), payload.Payload{})
+	requireRateLimited(t, sockA, []byte("r"))
+	requireRateLimited(t, sockB, []byte("r"))
+
+	// Expect connections without a session not to be limited
+	for i := 0; i < 5; i++ {
+		requestSuccess(t, sockC, 32, []byte("r"), payload.Payload{})
+	}
+}
+
+// TestRateLimitSessionRestore tests rate limiting session restoration
+// and closure requests
+func TestRateLimitSessionRestore(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					// Sessions not found
+					return nil, nil
+				},
+			},
+			RateLimits: wwr.RateLimits{
+				Connection:     wwr.RateLimit{Rate: 0.001, Burst: 4},
+				SessionRestore: wwr.RateLimit{Rate: 0.001, Burst: 2},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Expect guessing session keys to be limited
+	for i := 0; i < 2; i++ {
+		reply := requestRestoreSession(t, sock, []byte("guess"))
+		require.Equal(t, message.MsgReplySessionNotFound, reply.MsgType)
+	}
+	reply := requestRestoreSession(t, sock, []byte("guess"))
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+	require.Equal(t, wwr.RateLimitExceededCode, string(reply.MsgName))
+
+	// Expect the rejected restoration not to drain the connection budget
+	requestSuccess(t, sock, 32, []byte("r"), payload.Payload{})
+
+	// Expect session closures to be subject to the connection limit
+	reply = requestCloseSession(t, sock)
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+	reply = requestCloseSession(t, sock)
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+	require.Equal(t, wwr.RateLimitExceededCode, string(reply.MsgName))
+}
+
+// TestRateLimitRepeatOffender tests closing connections repeatedly exceeding
+// the rate limits
+func TestRateLimitRepeatOffender(t *testing.T) {
+	disconnected := make(chan struct{})
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientDisconnected: func(_ wwr.Connection, _ error) {
+				close(disconnected)
+			},
+		},
+		wwr.ServerOptions{
+			RateLimits: wwr.RateLimits{
+				Global:        wwr.RateLimit{Rate: 0.001, Burst: 1},
+				MaxViolations: 2,
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	requestSuccess(t, sock, 32, []byte("r"), payload.Payload{})
+	requireRateLimited(t, sock, []byte("r"))
+	requireRateLimited(t, sock, []byte("r"))
+
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection wasn't closed")
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/capture"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/replay"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/require"
+)
+
+// replayServerImpl returns a server implementation creating sessions on
+// "login", replying the current time on "time" and transforming
+// the payload of "echo" requests by the given function
+func replayServerImpl(echo func([]byte) []byte) *ServerImpl {
+	return &ServerImpl{
+		Request: func(
+			_ context.Context,
+			conn wwr.Connection,
+			msg wwr.Message,
+		) (wwr.Payload, error) {
+			switch string(msg.Name()) {
+			case "login":
+				return wwr.Payload{}, conn.CreateSession(nil)
+			case "time":
+				data, err := json.Marshal(map[string]interface{}{
+					"now":     time.Now(),
+					"version": 1,
+				})
+				return wwr.Payload{Encoding: wwr.EncodingUtf8, Data: data}, err
+			}
+			return wwr.Payload{
+				Encoding: msg.PayloadEncoding(),
+				Data:     echo(msg.Payload()),
+			}, nil
+		},
+	}
+}
+
+// recordSessions records a session creation on one connection
+// and its restoration on another
+func recordSessions(t *testing.T) []capture.Record {
+	buf := &bytes.Buffer{}
+	writer, err := capture.NewWriter(buf)
+	require.NoError(t, err)
+
+	setup := SetupTestServer(
+		t,
+		replayServerImpl(func(data []byte) []byte { return data }),
+		wwr.ServerOptions{Sessions: wwr.Enabled},
+		capture.NewTransport(&memchan.Transport{}, writer),
+	)
+	utf8 := func(text string) payload.Payload {
+		return payload.Payload{Encoding: payload.Utf8, Data: []byte(text)}
+	}
+
+	// Create a session
+	sock, _ := setup.NewClientSocket()
+	writerLogin, err := sock.GetWriter()
+	require.NoError(t, err)
+	require.NoError(t, message.WriteMsgRequest(
+		writerLogin,
+		[]byte{1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte("login"),
+		payload.Binary,
+		nil,
+		true,
+	))
+	created := readSessionCreated(t, sock)
+	var session wwr.EncodedSession
+	require.NoError(t, json.Unmarshal(created.MsgPayload.Data, &session))
+	reply := message.NewMessage(1024)
+	require.Nil(t, sock.Read(reply, time.Time{}))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+
+	requestSuccess(t, sock, 1024, []byte("echo"), utf8("hello"))
+	requestSuccess(t, sock, 1024, []byte("time"), utf8("?"))
+	require.NoError(t, sock.Close())
+
+	// Restore the session on another connection
+	sock, _ = setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, sock, []byte(session.Key))
+	requestSuccess(t, sock, 1024, []byte("echo"), utf8("restored"))
+	require.NoError(t, sock.Close())
+
+	records, err := replay.Load(buf)
+	require.NoError(t, err)
+	return records
+}
+
+// replayAgainst replays the given records against a fresh server
+// of the given implementation
+func replayAgainst(
+	t *testing.T,
+	impl *ServerImpl,
+	records []capture.Record,
+	ignore []string,
+) *replay.Report {
+	setup := SetupTestServer(
+		t,
+		impl,
+		wwr.ServerOptions{Sessions: wwr.Enabled},
+		nil, // Use the default transport implementation
+	)
+	player, err := replay.NewPlayer(replay.Options{
+		Transport: &memchan.ClientTransport{
+			Server: setup.Transport.(*memchan.Transport),
+		},
+		Ignore:  ignore,
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	report, err := player.Play(records)
+	require.NoError(t, err)
+	return report
+}
+
+// TestReplay tests replaying a recorded session against an unchanged server
+func TestReplay(t *testing.T) {
+	records := recordSessions(t)
+
+	report := replayAgainst(
+		t,
+		replayServerImpl(func(data []byte) []byte { return data }),
+		records,
+		append([]string{"payload/now"}, replay.DefaultIgnore...),
+	)
+	require.NoError(t, report.Err())
+	require.Equal(t, 2, report.Sessions)
+	require.Equal(t, 5, report.Sent)
+	require.Equal(t, 8, report.Received)
+}
+
+// TestReplayTransport tests replaying a recorded session using
+// the replay transport as the server transport
+func TestReplayTransport(t *testing.T) {
+	trans := &replay.Transport{
+		Records: recordSessions(t),
+		Options: replay.Options{
+			Ignore:  append([]string{"payload/now"}, replay.DefaultIgnore...),
+			Timeout: time.Second,
+		},
+	}
+	setup := SetupTestServer(
+		t,
+		replayServerImpl(func(data []byte) []byte { return data }),
+		wwr.ServerOptions{Sessions: wwr.Enabled},
+		trans,
+	)
+	defer setup.Server.Shutdown()
+
+	report, err := trans.Wait()
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.Equal(t, 2, report.Sessions)
+	require.Equal(t, 5, report.Sent)
+	require.Equal(t, 8, report.Received)
+}
+
+// TestReplayDivergence tests reporting the divergences of a changed server
+func TestReplayDivergence(t *testing.T) {
+	records := recordSessions(t)
+
+	report := replayAgainst(
+		t,
+		replayServerImpl(func(data []byte) []byte {
+			return []byte(strings.ToUpper(string(data)))
+		}),
+		records,
+		nil, // Ignore the session keys and timestamps only
+	)
+	require.Equal(t, 2, report.Sessions)
+	require.Len(t, report.Divergences, 3)
+
+	require.Equal(t, "payload", report.Divergences[0].Field)
+	require.Equal(t, `"hello"`, report.Divergences[0].Expected)
+	require.Equal(t, `"HELLO"`, report.Divergences[0].Actual)
+	require.Equal(t, "payload/now", report.Divergences[1].Field)
+	require.Equal(t, "payload", report.Divergences[2].Field)
+	require.Equal(t, `"RESTORED"`, report.Divergences[2].Actual)
+
+	require.Error(t, report.Err())
+	require.Contains(
+		t,
+		report.Err().Error(),
+		`MsgReplyUtf8: payload: expected "hello", got "HELLO"`,
+	)
+}
+
+// TestReplayMissingReply tests reporting replies that weren't received
+func TestReplayMissingReply(t *testing.T) {
+	records := recordSessions(t)
+
+	report := replayAgainst(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Never reply to echo requests
+				if string(msg.Name()) == "echo" {
+					time.Sleep(2 * time.Second)
+				}
+				return replayServerImpl(
+					func(data []byte) []byte { return data },
+				).Request(context.Background(), conn, msg)
+			},
+		},
+		records[:6], // Connection, login and echo of the first session
+		nil,
+	)
+	require.Len(t, report.Divergences, 1)
+	require.Equal(t, message.MsgReplyUtf8, report.Divergences[0].MsgType)
+	require.Contains(t, report.Divergences[0].String(), "wasn't received")
+}
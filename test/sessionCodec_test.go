@@ -0,0 +1,89 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBinarySessInfo struct {
+	Blob  []byte `json:"blob"`
+	Count int64  `json:"count"`
+}
+
+// TestBinarySessionCodec tests creating and restoring sessions using the
+// compact binary session codec
+func TestBinarySessionCodec(t *testing.T) {
+	infoType, err := wwr.NewStructSessionInfoType(testBinarySessInfo{})
+	require.NoError(t, err)
+
+	expectedInfo := testBinarySessInfo{
+		Blob:  []byte{0, 1, 2, 255},
+		Count: -9007199254740993,
+	}
+	codec := wwr.NewBinarySessionCodec()
+	createSession := sync.Once{}
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientConnected: func(_ wwr.ConnectionOptions, c wwr.Connection) {
+				// Create the session on the first connection only
+				createSession.Do(func() {
+					info, err := infoType.Wrap(&expectedInfo)
+					assert.NoError(t, err)
+					assert.NoError(t, c.CreateSession(info))
+				})
+			},
+		},
+		wwr.ServerOptions{
+			SessionInfoParser: infoType.Parser(nil),
+			SessionCodec:      codec,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	// Expect the codec to be advertised during the handshake
+	sock, srvConf := setup.NewClientSocket()
+	require.Equal(t, byte(2), srvConf.MajorProtocolVersion)
+	require.Equal(t, byte(1), srvConf.MinorProtocolVersion)
+	require.Equal(t, message.SessionCodecBinary, srvConf.SessionCodec)
+
+	// Expect binary blobs and precise integers to be preserved
+	notification := readSessionCreated(t, sock)
+	session, err := codec.Decode(notification.Payload())
+	require.NoError(t, err)
+	require.Equal(t, expectedInfo.Blob, session.Info["blob"])
+	require.Equal(t, expectedInfo.Count, session.Info["count"])
+
+	// Expect the restoration reply to be encoded using the codec as well
+	otherSock, _ := setup.NewClientSocket()
+	reply := requestRestoreSession(t, otherSock, []byte(session.Key))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+
+	restored, err := codec.Decode(reply.Payload())
+	require.NoError(t, err)
+	require.Equal(t, session.Key, restored.Key)
+	require.True(t, session.Creation.Equal(restored.Creation))
+}
+
+// TestDefaultSessionCodec tests whether the JSON session codec and protocol
+// version 2.0 are used by default
+func TestDefaultSessionCodec(t *testing.T) {
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{},
+		nil, // Use the default transport implementation
+	)
+
+	_, srvConf := setup.NewClientSocket()
+	require.Equal(t, byte(2), srvConf.MajorProtocolVersion)
+	require.Equal(t, byte(0), srvConf.MinorProtocolVersion)
+	require.Equal(t, message.SessionCodecJSON, srvConf.SessionCodec)
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/stretchr/testify/require"
+)
+
+// newSessionEventsTestServer sets up a server hosting a single session
+// identified by the given key and subscribes to its session events
+func newSessionEventsTestServer(
+	t *testing.T,
+	sessionKey string,
+	hashKeys bool,
+) (ServerSetupTest, chan wwr.SessionEvent) {
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			HashSessionEventKeys: hashKeys,
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					if key != sessionKey {
+						// Session not found
+						return nil, nil
+					}
+					return wwr.NewSessionLookupResult(
+						time.Now(), // Creation
+						time.Now(), // LastLookup
+						nil,        // Info
+					), nil
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	events := make(chan wwr.SessionEvent, 16)
+	setup.Server.SubscribeSessionEvents(func(event wwr.SessionEvent) {
+		events <- event
+	})
+
+	return setup, events
+}
+
+// expectSessionEvent reads the next session event and verifies its type and
+// outcome
+func expectSessionEvent(
+	t *testing.T,
+	events chan wwr.SessionEvent,
+	eventType wwr.SessionEventType,
+	outcome wwr.SessionEventOutcome,
+) wwr.SessionEvent {
+	select {
+	case event := <-events:
+		require.Equal(t, eventType, event.Type)
+		require.Equal(t, outcome, event.Outcome)
+		require.False(t, event.Time.IsZero())
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("session event %s not published", eventType)
+	}
+	return wwr.SessionEvent{}
+}
+
+// TestSessionEventsRestoreAndClose tests the session events published during
+// the restoration and closure of a session
+func TestSessionEventsRestoreAndClose(t *testing.T) {
+	sessionKey := "testsessionkey"
+	setup, events := newSessionEventsTestServer(t, sessionKey, false)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Restore an inexistent session
+	requestRestoreSession(t, sock, []byte("inexistent"))
+	failed := expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventRestoreFailed,
+		wwr.SessionOutcomeNotFound,
+	)
+	require.Equal(t, "inexistent", failed.SessionKey)
+	require.IsType(t, wwr.ErrSessionNotFound{}, failed.Err)
+
+	// Restore the session
+	requestRestoreSessionSuccess(t, sock, []byte(sessionKey))
+	restored := expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventRestored,
+		wwr.SessionOutcomeSuccess,
+	)
+	require.Equal(t, sessionKey, restored.SessionKey)
+	require.NotZero(t, restored.ConnectionID)
+	require.Equal(t, failed.ConnectionID, restored.ConnectionID)
+	attached := expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventAttached,
+		wwr.SessionOutcomeSuccess,
+	)
+	require.Equal(t, restored.ConnectionID, attached.ConnectionID)
+
+	// Close the session
+	requestCloseSessionSuccess(t, sock)
+	expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventClosed,
+		wwr.SessionOutcomeDestroyed,
+	)
+	expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventDetached,
+		wwr.SessionOutcomeSuccess,
+	)
+}
+
+// TestSessionEventsDisconnect tests whether a detachment is published when a
+// connection with a session is closed
+func TestSessionEventsDisconnect(t *testing.T) {
+	sessionKey := "testsessionkey"
+	setup, events := newSessionEventsTestServer(t, sessionKey, false)
+
+	sock, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, sock, []byte(sessionKey))
+	expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventRestored,
+		wwr.SessionOutcomeSuccess,
+	)
+	expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventAttached,
+		wwr.SessionOutcomeSuccess,
+	)
+
+	require.NoError(t, sock.Close())
+	detached := expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventDetached,
+		wwr.SessionOutcomeDisconnected,
+	)
+	require.Equal(t, sessionKey, detached.SessionKey)
+}
+
+// TestSessionEventsHashedKeys tests whether session keys are hashed when
+// ServerOptions.HashSessionEventKeys is enabled
+func TestSessionEventsHashedKeys(t *testing.T) {
+	sessionKey := "testsessionkey"
+	setup, events := newSessionEventsTestServer(t, sessionKey, true)
+
+	sock, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, sock, []byte(sessionKey))
+
+	hash := sha256.Sum256([]byte(sessionKey))
+	restored := expectSessionEvent(
+		t,
+		events,
+		wwr.SessionEventRestored,
+		wwr.SessionOutcomeSuccess,
+	)
+	require.Equal(t, hex.EncodeToString(hash[:]), restored.SessionKey)
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionEvictionOldest tests the EvictionOldest session eviction policy
+// expecting the oldest connection of a session to be evicted when a new
+// connection restores a session that reached the maximum number of
+// concurrent connections
+func TestSessionEvictionOldest(t *testing.T) {
+	sessionKey := "testsessionkey"
+	sessionCreation := time.Now()
+
+	// Initialize server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{},
+		wwr.ServerOptions{
+			MaxSessionConnections: 1,
+			SessionEvictionPolicy: wwr.EvictionOldest,
+			SessionManager: &SessionManager{
+				SessionLookup: func(key string) (
+					wwr.SessionLookupResult,
+					error,
+				) {
+					if key != sessionKey {
+						// Session not found
+						return nil, nil
+					}
+					return wwr.NewSessionLookupResult(
+						sessionCreation, // Creation
+						time.Now(),      // LastLookup
+						nil,             // Info
+					), nil
+				},
+			},
+		},
+		nil, // Use the default transport implementation
+	)
+
+	// Restore the session on the first client
+	oldClient, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, oldClient, []byte(sessionKey))
+
+	// Expect the first client to be notified about the eviction
+	evictionNotified := sync.WaitGroup{}
+	evictionNotified.Add(1)
+	go func() {
+		defer evictionNotified.Done()
+		msg := message.NewMessage(32)
+		assert.Nil(t, oldClient.Read(msg, time.Now().Add(5*time.Second)))
+		assert.Equal(t, message.MsgNotifySessionClosed, msg.MsgType)
+		assert.Equal(
+			t,
+			message.SessionClosureEvicted,
+			msg.SessionClosureReason,
+		)
+	}()
+
+	// Restore the session on a new client and expect it to succeed
+	newClient, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, newClient, []byte(sessionKey))
+
+	evictionNotified.Wait()
+
+	require.Equal(t, 1, setup.Server.SessionConnectionsNum(sessionKey))
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStructSessInfo struct {
+	UserID string   `json:"uid"`
+	Level  uint8    `json:"lvl"`
+	Scopes []string `json:"scopes"`
+}
+
+// TestStructSessionInfo tests creating and restoring sessions with session info
+// objects backed by a plain Go struct
+func TestStructSessionInfo(t *testing.T) {
+	infoType, err := wwr.NewStructSessionInfoType(testStructSessInfo{})
+	require.NoError(t, err)
+
+	expectedInfo := testStructSessInfo{
+		UserID: "user1",
+		Level:  3,
+		Scopes: []string{"read", "write"},
+	}
+
+	createSession := sync.Once{}
+	handlerFinished := sync.WaitGroup{}
+	handlerFinished.Add(1)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			ClientConnected: func(_ wwr.ConnectionOptions, c wwr.Connection) {
+				// Create the session on the first connection only
+				createSession.Do(func() {
+					info, err := infoType.Wrap(&expectedInfo)
+					assert.NoError(t, err)
+					assert.NoError(t, c.CreateSession(info))
+				})
+			},
+			Request: func(
+				_ context.Context,
+				conn wwr.Connection,
+				_ wwr.Message,
+			) (wwr.Payload, error) {
+				defer handlerFinished.Done()
+
+				// Expect the restored session info to be parsed
+				// into the struct type
+				info, isStructInfo := conn.Session().Info.(*wwr.StructSessionInfo)
+				assert.True(t, isStructInfo)
+				if isStructInfo {
+					assert.Equal(t, expectedInfo, info.Struct())
+				}
+				assert.Equal(t, uint8(3), conn.SessionInfo("lvl"))
+				return wwr.Payload{}, nil
+			},
+		},
+		wwr.ServerOptions{
+			SessionInfoParser: infoType.Parser(nil),
+		},
+		nil, // Use the default transport implementation
+	)
+
+	// Expect the session creation notification to carry the session info
+	sock, _ := setup.NewClientSocket()
+	notification := readSessionCreated(t, sock)
+
+	var session wwr.JSONEncodedSession
+	require.NoError(t, json.Unmarshal(notification.Payload(), &session))
+	require.Equal(t, "user1", session.Info["uid"])
+	require.Equal(t, float64(3), session.Info["lvl"])
+	require.Equal(t, []interface{}{"read", "write"}, session.Info["scopes"])
+
+	// Restore the session on another connection
+	otherSock, _ := setup.NewClientSocket()
+	requestRestoreSessionSuccess(t, otherSock, []byte(session.Key))
+	requestSuccess(t, otherSock, 32, []byte("verify"), payload.Payload{})
+
+	handlerFinished.Wait()
+}
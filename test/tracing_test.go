@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// awaitSpans waits until the given number of spans was recorded
+func awaitSpans(
+	t *testing.T,
+	rec *tracing.Recorder,
+	num int,
+) []tracing.RecordedSpan {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		spans := rec.Spans()
+		if len(spans) >= num {
+			require.Len(t, spans, num)
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d spans, got %d", num, len(spans))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestTracing tests whether handled messages are traced
+func TestTracing(t *testing.T) {
+	rec := tracing.NewRecorder()
+	signalHandled := make(chan struct{})
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				// Expect the span to be passed to the handler
+				assert.NotNil(t, tracing.SpanFromContext(ctx))
+
+				if string(msg.Name()) == "fail" {
+					return wwr.Payload{}, wwr.ErrRequest{
+						Code:    "SAMPLE_ERROR",
+						Message: "sample error",
+					}
+				}
+				return wwr.Payload{}, nil
+			},
+			Signal: func(
+				ctx context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				assert.NotNil(t, tracing.SpanFromContext(ctx))
+				close(signalHandled)
+			},
+		},
+		wwr.ServerOptions{
+			Tracer: rec,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Successful request
+	requestSuccess(t, sock, 32, []byte("succeed"), payload.Payload{
+		Encoding: payload.Binary,
+		Data:     []byte("sample"),
+	})
+	spans := awaitSpans(t, rec, 1)
+	require.Equal(t, wwr.SpanRequest, spans[0].Operation)
+	require.Equal(t, "succeed", spans[0].Attributes[wwr.AttrMessageName])
+	require.Equal(t, 6, spans[0].Attributes[wwr.AttrPayloadSize])
+	require.Equal(
+		t,
+		payload.Binary.String(),
+		spans[0].Attributes[wwr.AttrPayloadEncoding],
+	)
+	require.Equal(
+		t,
+		wwr.ReplyTypeSuccess,
+		spans[0].Attributes[wwr.AttrReplyType],
+	)
+	require.NotNil(t, spans[0].Attributes[wwr.AttrConnectionID])
+	rec.Reset()
+
+	// Failing request
+	reply := request(t, sock, 64, []byte("fail"), payload.Payload{})
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+	spans = awaitSpans(t, rec, 1)
+	require.Equal(
+		t,
+		wwr.ErrorReplyRequest,
+		spans[0].Attributes[wwr.AttrReplyType],
+	)
+	require.Equal(t, "SAMPLE_ERROR", spans[0].Attributes[wwr.AttrErrorCode])
+	rec.Reset()
+
+	// Signal
+	signal(t, sock, []byte("sample"), payload.Payload{})
+	<-signalHandled
+	spans = awaitSpans(t, rec, 1)
+	require.Equal(t, wwr.SpanSignal, spans[0].Operation)
+	require.Equal(t, "sample", spans[0].Attributes[wwr.AttrMessageName])
+	rec.Reset()
+
+	// Failing session restoration
+	reply = requestRestoreSession(t, sock, []byte("inexistent"))
+	require.Equal(t, message.MsgReplySessionNotFound, reply.MsgType)
+	spans = awaitSpans(t, rec, 1)
+	require.Equal(t, wwr.SpanSessionRestore, spans[0].Operation)
+	require.Equal(
+		t,
+		wwr.ErrorReplySessionNotFound,
+		spans[0].Attributes[wwr.AttrReplyType],
+	)
+	rec.Reset()
+
+	// Session closure
+	requestCloseSessionSuccess(t, sock)
+	spans = awaitSpans(t, rec, 1)
+	require.Equal(t, wwr.SpanSessionClosure, spans[0].Operation)
+	require.Equal(
+		t,
+		wwr.ReplyTypeSuccess,
+		spans[0].Attributes[wwr.AttrReplyType],
+	)
+}
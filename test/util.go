@@ -7,6 +7,7 @@ import (
 	"time"
 
 	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/capture"
 	"github.com/qbeon/webwire-go/message"
 	"github.com/qbeon/webwire-go/payload"
 	"github.com/qbeon/webwire-go/transport/memchan"
@@ -90,6 +91,10 @@ func (setup *ServerSetup) NewDisconnectedClientSocket() (
 	case *memchan.Transport:
 		_, sock = memchan.NewEntangledSockets(srvTrans)
 		return sock, nil
+	case *capture.Transport:
+		// Connect to the wrapped transport
+		wrapped := ServerSetup{Transport: srvTrans.Transport}
+		return wrapped.NewDisconnectedClientSocket()
 	}
 	return nil, fmt.Errorf(
 		"unexpected server transport implementation: %s",
@@ -271,7 +276,7 @@ func requestCloseSession(t *testing.T, socket wwr.Socket) *message.Message {
 	require.NoError(t, err)
 	require.NotNil(t, writer)
 
-	reply := message.NewMessage(32)
+	reply := message.NewMessage(1024)
 
 	require.NoError(t, message.WriteMsgNamelessRequest(
 		writer,
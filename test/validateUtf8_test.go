@@ -0,0 +1,183 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// malformedUtf8 represents a malformed UTF8 byte sequence
+var malformedUtf8 = []byte{0x41, 0xC3, 0x28}
+
+// sendUnsafe writes a UTF8 encoded signal or request (if id isn't nil)
+// without validating the payload
+func sendUnsafe(t *testing.T, sock wwr.Socket, id []byte, data []byte) {
+	writer, err := sock.GetWriter()
+	require.NoError(t, err)
+
+	if id == nil {
+		require.NoError(t, message.WriteMsgSignal(
+			writer,
+			[]byte("s"),
+			payload.Utf8,
+			data,
+			false,
+		))
+		return
+	}
+	require.NoError(t, message.WriteMsgRequest(
+		writer,
+		id,
+		[]byte("r"),
+		payload.Utf8,
+		data,
+		false,
+	))
+}
+
+// TestValidateUtf8 tests the rejection of malformed UTF8 encoded payloads
+func TestValidateUtf8(t *testing.T) {
+	handled := uint32(0)
+
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Signal: func(
+				_ context.Context,
+				_ wwr.Connection,
+				_ wwr.Message,
+			) {
+				atomic.AddUint32(&handled, 1)
+			},
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				atomic.AddUint32(&handled, 1)
+				return wwr.Payload{
+					Encoding: msg.PayloadEncoding(),
+					Data:     msg.Payload(),
+				}, nil
+			},
+		},
+		wwr.ServerOptions{
+			ValidateUtf8: wwr.Enabled,
+		},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	// Expect the signal to be dropped
+	sendUnsafe(t, sock, nil, malformedUtf8)
+
+	// Expect the request to be rejected with a protocol error reply
+	sendUnsafe(t, sock, []byte{0, 0, 0, 0, 0, 0, 0, 1}, malformedUtf8)
+	reply := message.NewMessage(256)
+	require.Nil(t, sock.Read(reply, time.Time{}))
+	require.Equal(t, message.MsgReplyError, reply.MsgType)
+	require.Equal(t, wwr.ProtocolErrorCode, string(reply.MsgName))
+	require.Equal(t, uint32(0), atomic.LoadUint32(&handled))
+
+	// Expect well-formed payloads to be accepted
+	requestSuccess(t, sock, 32, []byte("r"), payload.Payload{
+		Encoding: payload.Utf8,
+		Data:     []byte("ёжз 😀"),
+	})
+	require.Equal(t, uint32(1), atomic.LoadUint32(&handled))
+}
+
+// TestValidateUtf8Disabled tests accepting malformed UTF8 encoded payloads
+// when the validation is disabled
+func TestValidateUtf8Disabled(t *testing.T) {
+	// Initialize webwire server
+	setup := SetupTestServer(
+		t,
+		&ServerImpl{
+			Request: func(
+				_ context.Context,
+				_ wwr.Connection,
+				msg wwr.Message,
+			) (wwr.Payload, error) {
+				return wwr.Payload{Data: msg.Payload()}, nil
+			},
+		},
+		wwr.ServerOptions{},
+		nil, // Use the default transport implementation
+	)
+
+	sock, _ := setup.NewClientSocket()
+
+	sendUnsafe(t, sock, []byte{0, 0, 0, 0, 0, 0, 0, 1}, malformedUtf8)
+	reply := message.NewMessage(256)
+	require.Nil(t, sock.Read(reply, time.Time{}))
+	require.Equal(t, message.MsgReplyBinary, reply.MsgType)
+	require.Equal(t, malformedUtf8, reply.Payload())
+}
+
+// TestValidateUtf8ServerSignal tests validating UTF8 encoded server-side
+// signals only when the validation is enabled
+func TestValidateUtf8ServerSignal(t *testing.T) {
+	for _, validate := range []wwr.OptionValue{wwr.Disabled, wwr.Enabled} {
+		signalErr := make(chan error, 1)
+
+		// Initialize webwire server
+		setup := SetupTestServer(
+			t,
+			&ServerImpl{
+				Request: func(
+					_ context.Context,
+					conn wwr.Connection,
+					_ wwr.Message,
+				) (wwr.Payload, error) {
+					signalErr <- conn.Signal([]byte("s"), wwr.Payload{
+						Encoding: payload.Utf8,
+						Data:     malformedUtf8,
+					})
+					return wwr.Payload{}, nil
+				},
+			},
+			wwr.ServerOptions{
+				ValidateUtf8: validate,
+			},
+			nil, // Use the default transport implementation
+		)
+
+		sock, _ := setup.NewClientSocket()
+
+		writer, err := sock.GetWriter()
+		require.NoError(t, err)
+		require.NoError(t, message.WriteMsgRequest(
+			writer,
+			[]byte{0, 0, 0, 0, 0, 0, 0, 1},
+			[]byte("r"),
+			payload.Binary,
+			nil,
+			true,
+		))
+
+		msg := message.NewMessage(256)
+		require.Nil(t, sock.Read(msg, time.Time{}))
+		if validate == wwr.Enabled {
+			// Expect the signal to be rejected
+			require.Error(t, <-signalErr)
+			require.Equal(t, message.MsgReplyBinary, msg.MsgType)
+			continue
+		}
+
+		// Expect the signal to be sent before the reply
+		require.NoError(t, <-signalErr)
+		require.Equal(t, message.MsgSignalUtf8, msg.MsgType)
+		require.Equal(t, malformedUtf8, msg.MsgPayload.Data)
+		require.Nil(t, sock.Read(msg, time.Time{}))
+		require.Equal(t, message.MsgReplyBinary, msg.MsgType)
+	}
+}
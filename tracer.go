@@ -0,0 +1,146 @@
+package webwire
+
+import (
+	"context"
+
+	"github.com/qbeon/webwire-go/message"
+)
+
+// Names of the operations traced by the server
+const (
+	// SpanRequest is the operation name of request handler spans
+	SpanRequest = "webwire.request"
+
+	// SpanSignal is the operation name of signal handler spans
+	SpanSignal = "webwire.signal"
+
+	// SpanSessionRestore is the operation name of session restoration spans
+	SpanSessionRestore = "webwire.session.restore"
+
+	// SpanSessionClosure is the operation name of session closure spans
+	SpanSessionClosure = "webwire.session.close"
+)
+
+// Keys of the span attributes set by the server
+const (
+	// AttrConnectionID is the identifier of the connection (uint64)
+	AttrConnectionID = "webwire.connection.id"
+
+	// AttrMessageName is the name of the message (string)
+	AttrMessageName = "webwire.message.name"
+
+	// AttrPayloadSize is the size of the message payload in bytes (int)
+	AttrPayloadSize = "webwire.message.payload_size"
+
+	// AttrPayloadEncoding is the encoding of the message payload (string)
+	AttrPayloadEncoding = "webwire.message.payload_encoding"
+
+	// AttrReplyType is the type of the reply sent to the client (string),
+	// either "reply" or the reason of the error reply (see ErrorReply*)
+	AttrReplyType = "webwire.reply.type"
+
+	// AttrErrorCode is the code of the ErrRequest error
+	// returned by the request handler (string)
+	AttrErrorCode = "webwire.error.code"
+)
+
+// ReplyTypeSuccess is the reply type attribute value of successful replies
+const ReplyTypeSuccess = "reply"
+
+// TraceParentHeader is the name of the message header the trace context of
+// the client is propagated in (W3C trace context "traceparent" format)
+const TraceParentHeader = "traceparent"
+
+// Span defines the interface of a traced operation
+type Span interface {
+	// SetAttribute sets an attribute of the span
+	SetAttribute(key string, value interface{})
+
+	// End marks the end of the span
+	End()
+}
+
+// Tracer defines the interface of a vendor-neutral tracer
+type Tracer interface {
+	// StartSpan starts a new span for the given operation. traceParent is the
+	// trace context propagated by the client (see TraceParentHeader) and is
+	// empty if the client didn't provide any. The returned context is passed
+	// to the user-space handler (OnRequest or OnSignal) and should carry the
+	// span to allow continuing the trace
+	StartSpan(
+		ctx context.Context,
+		operation string,
+		traceParent string,
+	) (context.Context, Span)
+}
+
+// NoopTracer implements the webwire.Tracer interface discarding all spans.
+// It's the default tracer
+type NoopTracer struct{}
+
+// StartSpan implements the webwire.Tracer interface
+func (NoopTracer) StartSpan(
+	ctx context.Context,
+	_ string,
+	_ string,
+) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan represents a discarded span
+type noopSpan struct{}
+
+// SetAttribute implements the webwire.Span interface
+func (noopSpan) SetAttribute(string, interface{}) {}
+
+// End implements the webwire.Span interface
+func (noopSpan) End() {}
+
+// traceParent returns the trace context propagated by the client
+// in the given message or an empty string if there's none
+func traceParent(msg *message.Message) string {
+	return string(msg.Header(TraceParentHeader))
+}
+
+// startSpan starts a new span for the given operation
+// setting the basic connection and message attributes
+func (srv *server) startSpan(
+	operation string,
+	con *connection,
+	msg *message.Message,
+) (context.Context, Span) {
+	ctx, span := srv.tracer.StartSpan(
+		context.Background(),
+		operation,
+		traceParent(msg),
+	)
+	span.SetAttribute(AttrConnectionID, con.ID())
+	if len(msg.MsgName) > 0 {
+		span.SetAttribute(AttrMessageName, string(msg.MsgName))
+	}
+	span.SetAttribute(AttrPayloadSize, len(msg.MsgPayload.Data))
+	span.SetAttribute(
+		AttrPayloadEncoding,
+		msg.MsgPayload.Encoding.String(),
+	)
+	return ctx, span
+}
+
+// endSpan sets the reply attributes according to the given request error
+// and ends the span
+func endSpan(span Span, reqErr error) {
+	if reqErr == nil {
+		span.SetAttribute(AttrReplyType, ReplyTypeSuccess)
+		span.End()
+		return
+	}
+
+	span.SetAttribute(AttrReplyType, errorReplyReason(reqErr))
+	switch err := reqErr.(type) {
+	case ErrRequest:
+		span.SetAttribute(AttrErrorCode, err.Code)
+	case *ErrRequest:
+		span.SetAttribute(AttrErrorCode, err.Code)
+	}
+	span.End()
+}
@@ -0,0 +1,137 @@
+// Package tracing provides an in-memory webwire.Tracer implementation
+// recording all spans, which is mostly useful for testing
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// RecordedSpan represents a span recorded by the Recorder
+type RecordedSpan struct {
+	// Operation is the name of the traced operation
+	Operation string
+
+	// TraceParent is the trace context propagated by the client
+	TraceParent string
+
+	// Parent is the span that was active in the context the span was started
+	// from or nil if there was none
+	Parent *Span
+
+	// Attributes are the attributes set on the span
+	Attributes map[string]interface{}
+
+	// Start is the time the span was started at
+	Start time.Time
+
+	// End is the time the span was ended at, zero if it's still active
+	End time.Time
+}
+
+// Span implements the webwire.Span interface recording the span
+type Span struct {
+	lock     sync.Mutex
+	recorder *Recorder
+	span     RecordedSpan
+}
+
+// SetAttribute implements the webwire.Span interface
+func (span *Span) SetAttribute(key string, value interface{}) {
+	span.lock.Lock()
+	span.span.Attributes[key] = value
+	span.lock.Unlock()
+}
+
+// End implements the webwire.Span interface.
+// Only the first call ends the span, subsequent calls are ignored
+func (span *Span) End() {
+	span.lock.Lock()
+	if !span.span.End.IsZero() {
+		span.lock.Unlock()
+		return
+	}
+	span.span.End = time.Now()
+	span.lock.Unlock()
+
+	span.recorder.lock.Lock()
+	span.recorder.ended = append(span.recorder.ended, span)
+	span.recorder.lock.Unlock()
+}
+
+// Snapshot returns a copy of the recorded span
+func (span *Span) Snapshot() RecordedSpan {
+	span.lock.Lock()
+	snapshot := span.span
+	snapshot.Attributes = make(map[string]interface{}, len(span.span.Attributes))
+	for key, value := range span.span.Attributes {
+		snapshot.Attributes[key] = value
+	}
+	span.lock.Unlock()
+	return snapshot
+}
+
+// spanCtxKey is the context key of the active span
+type spanCtxKey struct{}
+
+// SpanFromContext returns the span active in the given context
+// or nil if there's none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanCtxKey{}).(*Span)
+	return span
+}
+
+// Recorder implements the webwire.Tracer interface recording all spans in
+// memory
+type Recorder struct {
+	lock  sync.Mutex
+	ended []*Span
+}
+
+// NewRecorder creates a new span recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// StartSpan implements the webwire.Tracer interface
+func (rec *Recorder) StartSpan(
+	ctx context.Context,
+	operation string,
+	traceParent string,
+) (context.Context, wwr.Span) {
+	span := &Span{
+		recorder: rec,
+		span: RecordedSpan{
+			Operation:   operation,
+			TraceParent: traceParent,
+			Parent:      SpanFromContext(ctx),
+			Attributes:  make(map[string]interface{}),
+			Start:       time.Now(),
+		},
+	}
+	return context.WithValue(ctx, spanCtxKey{}, span), span
+}
+
+// Spans returns snapshots of all ended spans in the order they were ended
+func (rec *Recorder) Spans() []RecordedSpan {
+	rec.lock.Lock()
+	spans := make([]RecordedSpan, len(rec.ended))
+	for i, span := range rec.ended {
+		spans[i] = span.Snapshot()
+	}
+	rec.lock.Unlock()
+	return spans
+}
+
+// Reset removes all recorded spans
+func (rec *Recorder) Reset() {
+	rec.lock.Lock()
+	rec.ended = nil
+	rec.lock.Unlock()
+}
+
+// Make sure the recorder implements the webwire.Tracer interface
+var _ wwr.Tracer = (*Recorder)(nil)
@@ -0,0 +1,38 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qbeon/webwire-go/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecorder tests recording spans
+func TestRecorder(t *testing.T) {
+	rec := tracing.NewRecorder()
+
+	ctx, parent := rec.StartSpan(context.Background(), "parent", "tp")
+	_, child := rec.StartSpan(ctx, "child", "")
+	child.SetAttribute("key", "value")
+
+	// Active spans are not recorded
+	require.Len(t, rec.Spans(), 0)
+
+	child.End()
+	child.End()
+	parent.End()
+
+	spans := rec.Spans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Operation)
+	require.Equal(t, "value", spans[0].Attributes["key"])
+	require.Equal(t, tracing.SpanFromContext(ctx), spans[0].Parent)
+	require.False(t, spans[0].End.Before(spans[0].Start))
+	require.Equal(t, "parent", spans[1].Operation)
+	require.Equal(t, "tp", spans[1].TraceParent)
+	require.Nil(t, spans[1].Parent)
+
+	rec.Reset()
+	require.Len(t, rec.Spans(), 0)
+}
@@ -0,0 +1,150 @@
+package memchan
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+)
+
+// Faults defines the faults injected into the sockets of a transport.
+// All random decisions are drawn from pseudo-random number generators derived
+// from Seed, each socket owns a separate generator for its reader and writer
+// so that a given seed reproduces the same faults as long as the sockets are
+// created in the same order
+type Faults struct {
+	// Seed seeds the random number generators
+	Seed int64
+
+	// Latency delays the delivery of each written message by a duration drawn
+	// from the distribution. Messages are not delayed if nil
+	Latency LatencyDistribution
+
+	// Bandwidth caps the throughput of each socket to the given number of
+	// bytes per second. The throughput is unlimited if zero
+	Bandwidth uint64
+
+	// DisconnectProbability defines the probability of a socket being
+	// disconnected in the middle of writing a message. The message is lost and
+	// the writer fails with a wwr.ErrDisconnected error
+	DisconnectProbability float64
+
+	// StallProbability defines the probability of a write being stalled
+	// for StallDuration before the message is delivered
+	StallProbability float64
+	StallDuration    time.Duration
+
+	// ReadTimeoutProbability defines the probability of a read with a deadline
+	// being forced to wait until the deadline is exceeded regardless of
+	// incoming messages, which closes the socket just like a regular read
+	// deadline expiry does
+	ReadTimeoutProbability float64
+}
+
+// validate returns an error if the fault configuration is invalid
+func (flt *Faults) validate() error {
+	probabilities := []struct {
+		name  string
+		value float64
+	}{
+		{"disconnect", flt.DisconnectProbability},
+		{"stall", flt.StallProbability},
+		{"read timeout", flt.ReadTimeoutProbability},
+	}
+	for _, prob := range probabilities {
+		if prob.value < 0 || prob.value > 1 {
+			return fmt.Errorf(
+				"invalid %s probability: %f (must be between 0 and 1)",
+				prob.name,
+				prob.value,
+			)
+		}
+	}
+	if flt.StallDuration < 0 {
+		return fmt.Errorf("invalid stall duration: %s", flt.StallDuration)
+	}
+	return nil
+}
+
+// faultInjector derives the fault injectors of the sockets of a transport
+// from a copy of the fault configuration taken when the transport
+// is initialized
+type faultInjector struct {
+	conf Faults
+	lock *sync.Mutex
+	rng  *rand.Rand
+}
+
+// newFaultInjector creates a new fault injector seeding its random number
+// generator with the seed of the given configuration
+func newFaultInjector(conf Faults) *faultInjector {
+	return &faultInjector{
+		conf: conf,
+		lock: &sync.Mutex{},
+		rng:  rand.New(rand.NewSource(conf.Seed)),
+	}
+}
+
+// newSocketFaults derives the fault injector of a new socket
+func (inj *faultInjector) newSocketFaults() *socketFaults {
+	inj.lock.Lock()
+	writeSeed := inj.rng.Int63()
+	readSeed := inj.rng.Int63()
+	inj.lock.Unlock()
+
+	return &socketFaults{
+		conf:     &inj.conf,
+		writeRng: rand.New(rand.NewSource(writeSeed)),
+		readRng:  rand.New(rand.NewSource(readSeed)),
+	}
+}
+
+// socketFaults injects faults into a single socket. The writer and reader
+// generators are only ever accessed under the socket's writer and read lock
+// respectively
+type socketFaults struct {
+	conf     *Faults
+	writeRng *rand.Rand
+	readRng  *rand.Rand
+}
+
+// beforeWrite is called before a message of the given size is delivered to
+// the remote socket. It blocks for the injected delay and returns an error
+// if the socket is to be disconnected
+func (flt *socketFaults) beforeWrite(size int) error {
+	// Always draw the same number of samples per write to keep the sequence
+	// of decisions independent of the outcome of previous ones
+	disconnect := flt.writeRng.Float64() < flt.conf.DisconnectProbability
+	stall := flt.writeRng.Float64() < flt.conf.StallProbability
+
+	var delay time.Duration
+	if flt.conf.Latency != nil {
+		delay += flt.conf.Latency.Sample(flt.writeRng)
+	}
+	if flt.conf.Bandwidth > 0 {
+		delay += time.Duration(
+			float64(size) / float64(flt.conf.Bandwidth) * float64(time.Second),
+		)
+	}
+	if stall {
+		delay += flt.conf.StallDuration
+	}
+
+	if disconnect {
+		// Disconnect half-way through the transmission
+		time.Sleep(delay / 2)
+		return wwr.ErrDisconnected{
+			Cause: fmt.Errorf("injected disconnect"),
+		}
+	}
+
+	time.Sleep(delay)
+	return nil
+}
+
+// forceReadTimeout returns true if the current read is to time out
+func (flt *socketFaults) forceReadTimeout() bool {
+	return flt.readRng.Float64() < flt.conf.ReadTimeoutProbability
+}
@@ -0,0 +1,291 @@
+package memchan_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	wwr "github.com/qbeon/webwire-go"
+	"github.com/qbeon/webwire-go/message"
+	"github.com/qbeon/webwire-go/payload"
+	"github.com/qbeon/webwire-go/transport/memchan"
+	"github.com/stretchr/testify/require"
+)
+
+func testNewFaultyServer(
+	t *testing.T,
+	faults *memchan.Faults,
+) *memchan.Transport {
+	t.Helper()
+	server := &memchan.Transport{Faults: faults}
+	require.NoError(t, server.Initialize(
+		wwr.ServerOptions{MessageBufferSize: 1024},
+		func() bool { return false },
+		func(_ wwr.ConnectionOptions, _ wwr.Socket) {},
+	))
+	return server
+}
+
+// sendMessage writes a request message of the given payload to the sender
+// and reads it on the receiver returning the error of the writer
+func sendMessage(
+	sender *memchan.Socket,
+	receiver *memchan.Socket,
+	data []byte,
+) error {
+	read := make(chan wwr.ErrSockRead, 1)
+	go func() {
+		read <- receiver.Read(message.NewMessage(1024), time.Time{})
+	}()
+
+	writer, err := sender.GetWriter()
+	if err != nil {
+		<-read
+		return err
+	}
+	err = message.WriteMsgRequest(
+		writer,
+		[]byte("00000000"),
+		nil,
+		payload.Binary,
+		data,
+		true,
+	)
+	<-read
+	return err
+}
+
+// countWritesBeforeDisconnect returns the number of successful writes before
+// an injected disconnect for each of the given number of connections
+func countWritesBeforeDisconnect(
+	t *testing.T,
+	faults *memchan.Faults,
+	connections int,
+) []int {
+	server := testNewFaultyServer(t, faults)
+	counts := make([]int, connections)
+	for i := range counts {
+		counts[i] = countWrites(t, server)
+	}
+	return counts
+}
+
+// countWrites returns the number of successful writes before an injected
+// disconnect of a new connection
+func countWrites(t *testing.T, server *memchan.Transport) int {
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+	count := 0
+	for sendMessage(cltSock, srvSock, []byte("12345678")) == nil {
+		count++
+	}
+	ensureSocketClosed(t, cltSock)
+	ensureSocketClosed(t, srvSock)
+	return count
+}
+
+// TestFaultsReproducible tests whether faults are reproduced given
+// the same seed
+func TestFaultsReproducible(t *testing.T) {
+	newFaults := func() *memchan.Faults {
+		return &memchan.Faults{
+			Seed:                  42,
+			DisconnectProbability: 0.2,
+		}
+	}
+
+	first := countWritesBeforeDisconnect(t, newFaults(), 8)
+	second := countWritesBeforeDisconnect(t, newFaults(), 8)
+	require.Equal(t, first, second)
+
+	// Reinitializing a transport must restart the sequence
+	faults := newFaults()
+	countWritesBeforeDisconnect(t, faults, 8)
+	require.Equal(t, first, countWritesBeforeDisconnect(t, faults, 8))
+}
+
+// TestFaultsShared tests sharing a fault configuration between transports
+func TestFaultsShared(t *testing.T) {
+	faults := &memchan.Faults{Seed: 42, DisconnectProbability: 0.2}
+	expected := countWritesBeforeDisconnect(t, faults, 8)
+
+	// Expect the faults of each transport to be independent
+	// of the sockets created by the other one
+	servers := []*memchan.Transport{
+		testNewFaultyServer(t, faults),
+		testNewFaultyServer(t, faults),
+	}
+	counts := make([][]int, len(servers))
+	for i := 0; i < 8; i++ {
+		for j, server := range servers {
+			counts[j] = append(counts[j], countWrites(t, server))
+		}
+	}
+	require.Equal(t, expected, counts[0])
+	require.Equal(t, expected, counts[1])
+	require.Equal(t, &memchan.Faults{
+		Seed:                  42,
+		DisconnectProbability: 0.2,
+	}, faults)
+}
+
+// TestFaultsDisconnect tests injected disconnects
+func TestFaultsDisconnect(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		DisconnectProbability: 1,
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	err := sendMessage(cltSock, srvSock, []byte("12345678"))
+	require.Error(t, err)
+	require.IsType(t, wwr.ErrDisconnected{}, err)
+
+	ensureSocketClosed(t, cltSock)
+	ensureSocketClosed(t, srvSock)
+}
+
+// TestFaultsLatency tests injected latency
+func TestFaultsLatency(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		Latency: memchan.FixedLatency(50 * time.Millisecond),
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	start := time.Now()
+	require.NoError(t, sendMessage(cltSock, srvSock, []byte("12345678")))
+	require.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+// TestFaultsStall tests injected write stalls
+func TestFaultsStall(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		StallProbability: 1,
+		StallDuration:    50 * time.Millisecond,
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	start := time.Now()
+	require.NoError(t, sendMessage(srvSock, cltSock, []byte("12345678")))
+	require.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+// TestFaultsBandwidth tests the bandwidth cap
+func TestFaultsBandwidth(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		Bandwidth: 1000,
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	// 90 bytes payload + 10 bytes header at 1000 bytes per second
+	start := time.Now()
+	require.NoError(t, sendMessage(cltSock, srvSock, make([]byte, 90)))
+	require.True(t, time.Since(start) >= 100*time.Millisecond)
+}
+
+// TestFaultsReadTimeout tests forced read timeouts
+func TestFaultsReadTimeout(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		ReadTimeoutProbability: 1,
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	// Reads without a deadline are unaffected
+	require.NoError(t, sendMessage(cltSock, srvSock, []byte("12345678")))
+
+	// Write a message the reader must not receive
+	written := make(chan error, 1)
+	go func() {
+		writer, err := cltSock.GetWriter()
+		if err != nil {
+			written <- err
+			return
+		}
+		written <- message.WriteMsgRequest(
+			writer,
+			[]byte("00000000"),
+			nil,
+			payload.Binary,
+			[]byte("12345678"),
+			true,
+		)
+	}()
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	readErr := srvSock.Read(message.NewMessage(1024), deadline)
+	require.NotNil(t, readErr)
+	require.False(t, readErr.IsCloseErr())
+	require.False(t, time.Now().Before(deadline))
+
+	require.Error(t, <-written)
+	ensureSocketClosed(t, cltSock)
+	ensureSocketClosed(t, srvSock)
+}
+
+// TestFaultsReadTimeoutClose tests closing a socket
+// during a forced read timeout
+func TestFaultsReadTimeoutClose(t *testing.T) {
+	server := testNewFaultyServer(t, &memchan.Faults{
+		ReadTimeoutProbability: 1,
+	})
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cltSock.Close()
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	readErr := srvSock.Read(message.NewMessage(1024), deadline)
+	require.NotNil(t, readErr)
+	require.True(t, readErr.IsCloseErr())
+	require.True(t, time.Now().Before(deadline))
+	ensureSocketClosed(t, srvSock)
+}
+
+// TestFaultsInvalid tests initializing a transport with invalid faults
+func TestFaultsInvalid(t *testing.T) {
+	for _, faults := range []*memchan.Faults{
+		{DisconnectProbability: -0.1},
+		{StallProbability: 1.5},
+		{ReadTimeoutProbability: 2},
+		{StallDuration: -time.Second},
+	} {
+		server := &memchan.Transport{Faults: faults}
+		require.Error(t, server.Initialize(
+			wwr.ServerOptions{MessageBufferSize: 1024},
+			func() bool { return false },
+			func(_ wwr.ConnectionOptions, _ wwr.Socket) {},
+		))
+	}
+}
+
+// TestLatencyDistributions tests the sampled latency ranges
+func TestLatencyDistributions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	require.Equal(
+		t,
+		10*time.Millisecond,
+		memchan.FixedLatency(10*time.Millisecond).Sample(rng),
+	)
+
+	uniform := memchan.UniformLatency{
+		Min: 10 * time.Millisecond,
+		Max: 20 * time.Millisecond,
+	}
+	normal := memchan.NormalLatency{
+		Mean:   time.Millisecond,
+		StdDev: 10 * time.Millisecond,
+	}
+	for i := 0; i < 1000; i++ {
+		sample := uniform.Sample(rng)
+		require.True(t, sample >= uniform.Min && sample <= uniform.Max)
+		require.True(t, normal.Sample(rng) >= 0)
+	}
+}
@@ -0,0 +1,54 @@
+package memchan
+
+import (
+	"math/rand"
+	"time"
+)
+
+// LatencyDistribution represents a distribution of message delivery latencies
+type LatencyDistribution interface {
+	// Sample draws a latency from the distribution using the given random
+	// number generator
+	Sample(rng *rand.Rand) time.Duration
+}
+
+// FixedLatency delays every message by the same duration
+type FixedLatency time.Duration
+
+// Sample implements the LatencyDistribution interface
+func (lat FixedLatency) Sample(rng *rand.Rand) time.Duration {
+	return time.Duration(lat)
+}
+
+// UniformLatency delays messages by a duration uniformly distributed
+// between Min and Max
+type UniformLatency struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Sample implements the LatencyDistribution interface
+func (lat UniformLatency) Sample(rng *rand.Rand) time.Duration {
+	if lat.Max <= lat.Min {
+		return lat.Min
+	}
+	return lat.Min + time.Duration(rng.Int63n(int64(lat.Max-lat.Min)+1))
+}
+
+// NormalLatency delays messages by a normally distributed duration.
+// Negative samples are clamped to zero
+type NormalLatency struct {
+	Mean   time.Duration
+	StdDev time.Duration
+}
+
+// Sample implements the LatencyDistribution interface
+func (lat NormalLatency) Sample(rng *rand.Rand) time.Duration {
+	sample := time.Duration(
+		rng.NormFloat64()*float64(lat.StdDev) + float64(lat.Mean),
+	)
+	if sample < 0 {
+		return 0
+	}
+	return sample
+}
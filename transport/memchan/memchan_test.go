@@ -487,3 +487,42 @@ func TestClientConcurrentReadWrite(t *testing.T) {
 	readerFinished.Wait()
 	writersFinished.Wait()
 }
+
+// TestServeAfterShutdown tests serving a transport that was already shut down
+// expecting Serve to return immediately instead of failing
+func TestServeAfterShutdown(t *testing.T) {
+	server := testNewServer()
+	require.NoError(t, server.Shutdown())
+	require.NoError(t, server.Serve())
+
+	require.Error(t, (&memchan.Transport{}).Serve())
+}
+
+// TestReadParseErr tests whether read errors caused by corrupt messages
+// are distinguished from other read errors
+func TestReadParseErr(t *testing.T) {
+	server := testNewServer()
+	srvSock, cltSock := createSockets(t, server)
+	dial(t, srvSock, cltSock)
+
+	go func() {
+		writer, err := cltSock.GetWriter()
+		require.NoError(t, err)
+		_, err = writer.Write([]byte{255})
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}()
+
+	readErr := srvSock.Read(message.NewMessage(32), time.Time{})
+	require.NotNil(t, readErr)
+	require.True(t, readErr.(wwr.ErrSockReadParse).IsParseErr())
+
+	// Expect deadline expiry not to be reported as a parse error
+	readErr = srvSock.Read(
+		message.NewMessage(32),
+		time.Now().Add(10*time.Millisecond),
+	)
+	require.NotNil(t, readErr)
+	require.False(t, readErr.IsCloseErr())
+	require.False(t, readErr.(wwr.ErrSockReadParse).IsParseErr())
+}
@@ -25,6 +25,10 @@ func newSocket(server *Transport, bufferSize uint32) *Socket {
 		status:     nil,
 	}
 
+	if server != nil && server.faults != nil {
+		socket.faults = server.faults.newSocketFaults()
+	}
+
 	// Allocate the outbound buffer
 	socket.outboundBuffer = NewBuffer(
 		make([]byte, bufferSize),
@@ -7,6 +7,10 @@ type ErrSockRead struct {
 	// closed is true when the error was caused by a graceful socket closure
 	closed bool
 
+	// parse is true when the error was caused by a message that
+	// couldn't be parsed
+	parse bool
+
 	err error
 }
 
@@ -22,3 +26,8 @@ func (err ErrSockRead) Error() string {
 func (err ErrSockRead) IsCloseErr() bool {
 	return err.closed
 }
+
+// IsParseErr implements the webwire.ErrSockReadParse interface
+func (err ErrSockRead) IsParseErr() bool {
+	return err.parse
+}
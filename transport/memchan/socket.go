@@ -62,6 +62,10 @@ type Socket struct {
 	reader     chan []byte
 	readerErr  chan error
 	readerLock *sync.Mutex
+
+	// faults injects faults into the socket, it's nil if fault injection
+	// is disabled
+	faults *socketFaults
 }
 
 // onBufferFlush is a slot method that's called by the outbound buffer's onFlush
@@ -74,6 +78,19 @@ func (sock *Socket) onBufferFlush(data []byte) (err error) {
 		return errors.New("can't write to a closed socket")
 	}
 
+	if sock.faults != nil {
+		if err := sock.faults.beforeWrite(len(data)); err != nil {
+			sock.Close()
+			sock.writerLock.Unlock()
+			return err
+		}
+		// The socket might have been closed while the write was delayed
+		if !sock.IsConnected() {
+			sock.writerLock.Unlock()
+			return errors.New("can't write to a closed socket")
+		}
+	}
+
 	// Notify the remote reader about the new message
 	sock.remote.writeReader(data)
 
@@ -202,6 +219,30 @@ func (sock *Socket) readWithDeadline(deadline time.Time) (
 	return
 }
 
+// readForcedTimeout waits for either the deadline or the socket closure
+// dropping any incoming messages
+func (sock *Socket) readForcedTimeout(deadline time.Time) wwr.ErrSockRead {
+	sock.readTimer.Reset(time.Until(deadline))
+	defer sock.readTimer.Stop()
+
+	reader := sock.getReader()
+	for {
+		select {
+		case <-sock.readTimer.C:
+			// Deadline exceeded
+			return ErrSockRead{err: errors.New("read deadline exceeded")}
+
+		case result := <-reader:
+			if result == nil {
+				// Socket closed
+				return ErrSockRead{closed: true}
+			}
+			// Drop the message, the remote writer is failed
+			// when the socket is closed due to the timeout
+		}
+	}
+}
+
 // Read implements the transport.Socket interface
 func (sock *Socket) Read(
 	msg *message.Message,
@@ -220,6 +261,9 @@ func (sock *Socket) Read(
 	if deadline.IsZero() {
 		// No deadline
 		data, err = sock.readWithoutDeadline()
+	} else if sock.faults != nil && sock.faults.forceReadTimeout() {
+		// Injected read timeout
+		err = sock.readForcedTimeout(deadline)
 	} else {
 		// Set deadline
 		data, err = sock.readWithDeadline(deadline)
@@ -237,12 +281,12 @@ func (sock *Socket) Read(
 	if parseErr != nil {
 		sock.readerErr <- nil
 		sock.readLock.Unlock()
-		return ErrSockRead{err: parseErr}
+		return ErrSockRead{parse: true, err: parseErr}
 	}
 	if !typeParsed {
 		sock.readerErr <- nil
 		sock.readLock.Unlock()
-		return ErrSockRead{err: errors.New("no message type")}
+		return ErrSockRead{parse: true, err: errors.New("no message type")}
 	}
 
 	sock.readerErr <- nil
@@ -17,9 +17,22 @@ const serverActive = 1
 // Transport implements the Transport
 type Transport struct {
 	// OnBeforeCreation is called before the creation of a new connection and
-	// must return the options to be assigned to the new connection
+	// must return the options to be assigned to the new connection.
+	// The returned options can carry HTTP headers and a TLS connection state
+	// to simulate the credentials provided by HTTP based and TLS protected
+	// transports
 	OnBeforeCreation func() wwr.ConnectionOptions
 
+	// Faults defines the faults injected into the sockets of the transport.
+	// It must be set before the transport is initialized, faults are disabled
+	// if nil. The configuration is copied during the initialization and isn't
+	// modified, so it can be shared by multiple transports
+	Faults *Faults
+
+	// faults derives the fault injectors of new sockets,
+	// it's nil until the transport is initialized with faults
+	faults *faultInjector
+
 	onNewConnection wwr.OnNewConnection
 	isShuttingdown  wwr.IsShuttingDown
 
@@ -37,6 +50,14 @@ func (srv *Transport) Initialize(
 	isShuttingdown wwr.IsShuttingDown,
 	onNewConnection wwr.OnNewConnection,
 ) error {
+	srv.faults = nil
+	if srv.Faults != nil {
+		if err := srv.Faults.validate(); err != nil {
+			return fmt.Errorf("invalid faults: %s", err)
+		}
+		srv.faults = newFaultInjector(*srv.Faults)
+	}
+
 	srv.readTimeout = options.ReadTimeout
 	srv.bufferSize = options.MessageBufferSize
 	srv.isShuttingdown = isShuttingdown
@@ -57,9 +78,10 @@ func (srv *Transport) Initialize(
 
 // Serve implements the Transport interface
 func (srv *Transport) Serve() error {
-	if atomic.LoadUint32(&srv.status) != serverActive {
-		return errors.New("server is closed")
+	if srv.shutdown == nil {
+		return errors.New("server is not initialized")
 	}
+	// Return immediately if the server was already shut down before serving
 	<-srv.shutdown
 	return nil
 }
@@ -0,0 +1,49 @@
+package webwire
+
+import (
+	"github.com/qbeon/webwire-go/message"
+	pld "github.com/qbeon/webwire-go/payload"
+)
+
+// ProtocolErrorCode defines the error code of the error replies to
+// requests rejected due to violating the protocol
+const ProtocolErrorCode = "PROTOCOL_ERROR"
+
+// validatePayload verifies the payload of UTF8 encoded requests and signals
+// if ServerOptions.ValidateUtf8 is enabled. Messages carrying malformed
+// payloads are rejected and released returning false
+func (srv *server) validatePayload(
+	con *connection,
+	msg *message.Message,
+) bool {
+	if srv.options.ValidateUtf8 != Enabled {
+		return true
+	}
+
+	switch msg.MsgType {
+	case message.MsgSignalUtf8, message.MsgRequestUtf8:
+	default:
+		return true
+	}
+
+	err := pld.ValidateUtf8(msg.MsgPayload.Data)
+	if err == nil {
+		return true
+	}
+
+	srv.metrics.ProtocolViolation()
+	srv.logger.Log(
+		LogLevelWarn,
+		"received message with malformed UTF8 payload",
+		con.logFields(
+			LogField{LogKeyMessageType, msg.MsgType},
+			logErr(err),
+		)...,
+	)
+
+	srv.failMsg(con, msg, ErrNewProtocol(err))
+
+	// Release message buffer
+	msg.Close()
+	return false
+}